@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "dealls-case-study/docs"
 	"dealls-case-study/internal/db"
 	_ "dealls-case-study/internal/dto"
+	"dealls-case-study/internal/storage"
+	"dealls-case-study/internal/utils"
+	"dealls-case-study/internal/worker"
 
 	"dealls-case-study/internal/route"
 
@@ -23,7 +32,53 @@ func main() {
 	_ = godotenv.Load()
 
 	db.InitDB()
-	route.SetupRoutes()
+	storage.Init()
+
+	// cancelled on SIGINT/SIGTERM, so the server and workers stop together
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+
+	interval := 5 * time.Second
+	if v := os.Getenv("PAYROLL_WORKER_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid PAYROLL_WORKER_INTERVAL: %v", err)
+		}
+		interval = d
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		worker.RunPayrollWorker(ctx, db.DB, interval)
+	}()
+
+	attendanceInterval := time.Hour
+	if v := os.Getenv("ATTENDANCE_WORKER_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid ATTENDANCE_WORKER_INTERVAL: %v", err)
+		}
+		attendanceInterval = d
+	}
+	var shiftEnd time.Duration
+	if v := os.Getenv("ATTENDANCE_SHIFT_END"); v != "" {
+		d, err := utils.ParseTimeOfDay(v)
+		if err != nil {
+			log.Fatalf("invalid ATTENDANCE_SHIFT_END: %v", err)
+		}
+		shiftEnd = d
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		worker.RunAttendanceWorker(ctx, db.DB, attendanceInterval, shiftEnd)
+	}()
 
 	log.Println("App started!")
+	route.SetupRoutes(ctx)
+
+	// let a payroll that is being processed finish before exiting
+	workers.Wait()
+	log.Println("App stopped")
 }
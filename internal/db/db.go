@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/seed"
+	"dealls-case-study/internal/utils"
 	"fmt"
 	"log"
 	"os"
@@ -36,7 +38,8 @@ func InitDB() {
 
 	var err error
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// unique violations come back as gorm.ErrDuplicatedKey
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 
 	if err != nil {
 		panic("Failed to connect to database!")
@@ -64,6 +67,444 @@ func RunMigrations(db *gorm.DB) error {
 				return tx.Migrator().DropTable(&models.Attendance{}, &models.Overtime{}, &models.Payroll{}, &models.Payslip{}, &models.Reimbursement{}, &models.Role{}, &models.User{})
 			},
 		},
+		{
+			ID: "202610180900",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Payroll{}, &models.PayrollJob{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.PayrollJob{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&models.Payroll{}, "Attempts"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&models.Payroll{}, "LastError")
+			},
+		},
+		{
+			ID: "202610181000",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Payroll{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&models.Payroll{}, "FailureReason"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&models.Payroll{}, "FailedAt")
+			},
+		},
+		{
+			ID: "202610181100",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Payroll{}, &models.Payslip{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"Version", "ReopenedAt", "ReopenedBy", "ReopenReason"} {
+					if err := tx.Migrator().DropColumn(&models.Payroll{}, column); err != nil {
+						return err
+					}
+				}
+				for _, column := range []string{"Version", "SupersededAt", "SupersededBy"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// money columns move from float to NUMERIC
+			ID: "202610181200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.User{}, &models.Reimbursement{}, &models.Payslip{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				columns := map[string][]string{
+					"users":          {"salary"},
+					"reimbursements": {"amount"},
+					"payslips":       {"base_salary", "overtime_pay", "reimbursement", "total_salary", "monthly_salary", "hourly_rate", "overtime_rate_per_hour"},
+				}
+				for table, cols := range columns {
+					for _, col := range cols {
+						if err := tx.Exec("ALTER TABLE " + table + " ALTER COLUMN " + col + " TYPE double precision").Error; err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// PPh 21 tax tables, employee tax profiles and payslip tax lines
+			ID: "202610181300",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.TaxTable{}, &models.TaxBracket{}, &models.TaxPTKP{}, &models.User{}, &models.Payslip{}); err != nil {
+					return err
+				}
+				return seed.TaxTables(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.TaxBracket{}, &models.TaxPTKP{}, &models.TaxTable{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"PTKPStatus", "NPWP"} {
+					if err := tx.Migrator().DropColumn(&models.User{}, column); err != nil {
+						return err
+					}
+				}
+				for _, column := range []string{"TaxableIncome", "TaxRate", "Tax", "NetSalary", "TaxMethod", "TaxTableVersion", "PTKPStatus"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610181400",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.BPJSRateTable{}, &models.BPJSRate{}, &models.Payslip{}); err != nil {
+					return err
+				}
+				return seed.BPJSRateTables(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.BPJSRate{}, &models.BPJSRateTable{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"BPJSEmployee", "BPJSEmployer", "BPJSTaxDeductible", "BPJSBreakdown"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610181500",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.PayComponent{}, &models.PayComponentAssignment{}, &models.PayslipLine{}, &models.Payslip{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.PayslipLine{}, &models.PayComponentAssignment{}, &models.PayComponent{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"OtherEarnings", "OtherDeductions"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610181600",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.SalaryHistory{}, &models.Payslip{}); err != nil {
+					return err
+				}
+				// the current salary becomes the first history entry
+				return tx.Exec(`INSERT INTO salary_histories (user_id, salary, effective_from, reason, created_at, created_by)
+					SELECT id, salary, DATE(created_at)::timestamp AT TIME ZONE 'UTC', 'initial salary', NOW(), 999
+					FROM users WHERE salary > 0`).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.SalaryHistory{}); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&models.Payslip{}, "SalaryBreakdown")
+			},
+		},
+		{
+			ID: "202610181700",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.User{}, &models.Payslip{}); err != nil {
+					return err
+				}
+				// admin accounts are not employees on the payroll
+				return tx.Exec(`UPDATE users SET employment_status = ?
+					WHERE role_id IN (SELECT id FROM roles WHERE name = 'Admin')`, models.EmploymentStatusInactive).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"EmploymentStatus", "HireDate", "TerminationDate", "TerminationReason"} {
+					if err := tx.Migrator().DropColumn(&models.User{}, column); err != nil {
+						return err
+					}
+				}
+				for _, column := range []string{"Kind", "EmployedWorkingDays"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610181800",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Holiday{}, &models.Payslip{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.Holiday{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"HolidayOvertimeRatePerHour", "HolidayOvertimeHours"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610181900",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.LeaveType{}, &models.LeaveBalance{}, &models.LeaveRequest{}, &models.User{}, &models.Payslip{}); err != nil {
+					return err
+				}
+				return seed.LeaveTypes(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.LeaveRequest{}, &models.LeaveBalance{}, &models.LeaveType{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&models.User{}, "ManagerID"); err != nil {
+					return err
+				}
+				for _, column := range []string{"PaidLeaveDays", "UnpaidLeaveDays", "LeaveBreakdown"} {
+					if err := tx.Migrator().DropColumn(&models.Payslip{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610182000",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.Overtime{}); err != nil {
+					return err
+				}
+				// overtime submitted so far was paid without review
+				return tx.Model(&models.Overtime{}).Where("1 = 1").Update("status", models.OvertimeStatusApproved).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"Status", "ReviewedBy", "ReviewedAt", "ReviewReason"} {
+					if err := tx.Migrator().DropColumn(&models.Overtime{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202610182100",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.ReimbursementCategory{}, &models.Reimbursement{}); err != nil {
+					return err
+				}
+				if err := seed.ReimbursementCategories(tx); err != nil {
+					return err
+				}
+				// claims submitted so far were paid without review, by the payroll covering their date
+				if err := tx.Exec(`UPDATE reimbursements SET category_id = (SELECT id FROM reimbursement_categories WHERE code = ?)`,
+					models.ReimbursementCategoryOther).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec(`UPDATE reimbursements r SET status = ?, payroll_id = p.id, paid_at = p.updated_at
+					FROM payrolls p WHERE p.status = ? AND r.date >= p.period_start AND r.date < p.period_end + INTERVAL '1 day'`,
+					models.ReimbursementStatusPaid, models.PayrollStatusProcessed).Error; err != nil {
+					return err
+				}
+				return tx.Model(&models.Reimbursement{}).
+					Where("status = ?", models.ReimbursementStatusSubmitted).
+					Update("status", models.ReimbursementStatusApproved).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"CategoryID", "Status", "ReviewedBy", "ReviewedAt", "ReviewReason", "PayrollID", "PaidAt"} {
+					if err := tx.Migrator().DropColumn(&models.Reimbursement{}, column); err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&models.ReimbursementCategory{})
+			},
+		},
+		{
+			ID: "202610182200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.ReimbursementAttachment{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&models.ReimbursementAttachment{})
+			},
+		},
+		{
+			ID: "202610182300",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.OvertimeRuleSet{}, &models.OvertimeRule{}); err != nil {
+					return err
+				}
+				return seed.OvertimeRuleSets(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&models.OvertimeRule{}, &models.OvertimeRuleSet{})
+			},
+		},
+		{
+			ID: "202610182400",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&models.User{}); err != nil {
+					return err
+				}
+				// dates written at the moment of check-in and the like become the calendar date in the
+				// company timezone, payroll periods the day they start and end on; dates already at
+				// midnight UTC are calendar dates and stay as they are
+				zone := utils.CompanyLocation().String()
+				for _, column := range []struct{ table, name string }{
+					{"attendances", "date"},
+					{"overtimes", "date"},
+					{"reimbursements", "date"},
+					{"payrolls", "period_start"},
+					{"payrolls", "period_end"},
+				} {
+					sql := fmt.Sprintf(`
+						UPDATE %[1]s SET %[2]s = ((%[2]s AT TIME ZONE ?)::date)::timestamp AT TIME ZONE 'UTC'
+						WHERE %[2]s <> ((%[2]s AT TIME ZONE 'UTC')::date)::timestamp AT TIME ZONE 'UTC'`,
+						column.table, column.name)
+					if err := tx.Exec(sql, zone).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&models.User{}, "Timezone")
+			},
+		},
+		{
+			ID: "202610182500",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.AttendanceAudit{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&models.AttendanceAudit{})
+			},
+		},
+		{
+			ID: "202610182600",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.AttendanceAudit{}, &models.AttendanceCorrectionRequest{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.AttendanceCorrectionRequest{}); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&models.AttendanceAudit{}, "CorrectionRequestID")
+			},
+		},
+		{
+			ID: "202610182700",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Attendance{}, &models.Payroll{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"AutoClosedAt", "Anomaly"} {
+					if err := tx.Migrator().DropColumn(&models.Attendance{}, column); err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropColumn(&models.Payroll{}, "OpenAttendances")
+			},
+		},
+		{
+			ID: "202610182800",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.Shift{}, &models.WorkPattern{}, &models.WorkPatternDay{}, &models.UserWorkPattern{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&models.UserWorkPattern{}, &models.WorkPatternDay{}, &models.WorkPattern{}, &models.Shift{})
+			},
+		},
+		{
+			ID: "202610182900",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.User{}, &models.Payslip{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&models.User{}, "PayBasis"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&models.Payslip{}, "PayBasis")
+			},
+		},
+		{
+			ID: "202610183000",
+			Migrate: func(tx *gorm.DB) error {
+				// of several overtimes on a day the approved one is kept, then the submitted one, the latest first
+				if err := tx.Exec(`DELETE FROM overtimes WHERE id IN (
+					SELECT id FROM (
+						SELECT id, ROW_NUMBER() OVER (
+							PARTITION BY user_id, date
+							ORDER BY CASE status WHEN ? THEN 0 WHEN ? THEN 1 ELSE 2 END, id DESC
+						) AS n
+						FROM overtimes
+					) ranked WHERE n > 1)`,
+					models.OvertimeStatusApproved, models.OvertimeStatusSubmitted).Error; err != nil {
+					return err
+				}
+				return tx.AutoMigrate(&models.Overtime{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropIndex(&models.Overtime{}, "idx_overtimes_user_date")
+			},
+		},
+		{
+			ID: "202610183100",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&models.User{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&models.User{}, "OvertimeMonthlyCap")
+			},
+		},
+		{
+			ID: "202610183200",
+			Migrate: func(tx *gorm.DB) error {
+				// only the latest of several pending requests for a date is kept pending
+				if err := tx.Exec(`UPDATE attendance_correction_requests r SET status = ?
+					WHERE r.status = ? AND EXISTS (
+						SELECT 1 FROM attendance_correction_requests o
+						WHERE o.user_id = r.user_id AND o.date = r.date AND o.status = r.status AND o.id > r.id)`,
+					models.CorrectionStatusCancelled, models.CorrectionStatusPending).Error; err != nil {
+					return err
+				}
+				return tx.AutoMigrate(&models.AttendanceCorrectionRequest{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropIndex(&models.AttendanceCorrectionRequest{}, "idx_correction_requests_pending")
+			},
+		},
+		{
+			ID: "202610183300",
+			Migrate: func(tx *gorm.DB) error {
+				// of several attendances on a day the checked-out one is kept, then the first check-in
+				if err := tx.Exec(`DELETE FROM attendances WHERE id IN (
+					SELECT id FROM (
+						SELECT id, ROW_NUMBER() OVER (
+							PARTITION BY user_id, date
+							ORDER BY CASE WHEN check_out_at IS NULL THEN 1 ELSE 0 END, id
+						) AS n
+						FROM attendances
+					) ranked WHERE n > 1)`).Error; err != nil {
+					return err
+				}
+				return tx.AutoMigrate(&models.Attendance{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropIndex(&models.Attendance{}, "idx_attendances_user_date")
+			},
+		},
 	})
 
 	return m.Migrate()
@@ -96,16 +537,37 @@ func InitTestDB() (*gorm.DB, func(), error) {
 
 	dsn := fmt.Sprintf("host=%s port=%s user=postgres password=password dbname=testdb sslmode=disable", host, port.Port())
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		// panic("failed to connect to database")
 		return nil, nil, err
 	}
 
-	db.AutoMigrate(&models.Attendance{}, &models.Overtime{}, &models.Payroll{}, &models.Payslip{}, &models.Reimbursement{}, &models.Role{}, &models.User{})
+	db.AutoMigrate(&models.Attendance{}, &models.Overtime{}, &models.Payroll{}, &models.Payslip{}, &models.Reimbursement{}, &models.Role{}, &models.User{}, &models.PayrollJob{},
+		&models.TaxTable{}, &models.TaxBracket{}, &models.TaxPTKP{}, &models.BPJSRateTable{}, &models.BPJSRate{},
+		&models.PayComponent{}, &models.PayComponentAssignment{}, &models.PayslipLine{}, &models.SalaryHistory{}, &models.Holiday{},
+		&models.LeaveType{}, &models.LeaveBalance{}, &models.LeaveRequest{}, &models.ReimbursementCategory{}, &models.ReimbursementAttachment{},
+		&models.OvertimeRuleSet{}, &models.OvertimeRule{}, &models.AttendanceAudit{}, &models.AttendanceCorrectionRequest{},
+		&models.Shift{}, &models.WorkPattern{}, &models.WorkPatternDay{}, &models.UserWorkPattern{})
 
 	DB = db
 
+	if err := seed.TaxTables(db); err != nil {
+		return nil, nil, err
+	}
+	if err := seed.BPJSRateTables(db); err != nil {
+		return nil, nil, err
+	}
+	if err := seed.LeaveTypes(db); err != nil {
+		return nil, nil, err
+	}
+	if err := seed.ReimbursementCategories(db); err != nil {
+		return nil, nil, err
+	}
+	if err := seed.OvertimeRuleSets(db); err != nil {
+		return nil, nil, err
+	}
+
 	roles := []models.Role{
 		{Name: "Admin", CreatedBy: 999},
 		{Name: "Employee", CreatedBy: 999},
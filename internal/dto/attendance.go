@@ -3,8 +3,80 @@ package dto
 import "time"
 
 type AttendanceResponse struct {
-	ID         uint       `json:"id"`
-	Date       time.Time  `json:"date"`
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id,omitempty"`
+	Date        time.Time  `json:"date"`
+	CheckInAt   *time.Time `json:"check_in_at,omitempty"`
+	CheckOutAt  *time.Time `json:"check_out_at,omitempty"`
+	HoursWorked float64    `json:"hours_worked"`
+
+	AutoClosedAt *time.Time `json:"auto_closed_at,omitempty"`
+	Anomaly      string     `json:"anomaly,omitempty"`
+}
+
+type CreateAttendanceRequest struct {
+	UserID     uint       `json:"user_id" binding:"required"`
+	Date       time.Time  `json:"date" binding:"required"`
+	CheckInAt  *time.Time `json:"check_in_at" binding:"required"`
+	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
+	Reason     string     `json:"reason" binding:"required"`
+}
+
+// UpdateAttendanceRequest replaces the check-in and check-out of an attendance, a null check_out_at
+// removes the check-out. The date stays as it is when not given.
+type UpdateAttendanceRequest struct {
+	Date       *time.Time `json:"date,omitempty"`
+	CheckInAt  *time.Time `json:"check_in_at" binding:"required"`
+	CheckOutAt *time.Time `json:"check_out_at"`
+	Reason     string     `json:"reason" binding:"required"`
+}
+
+type VoidAttendanceRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AttendanceValues struct {
+	Date       string     `json:"date"`
 	CheckInAt  *time.Time `json:"check_in_at,omitempty"`
 	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
 }
+
+type AttendanceAuditResponse struct {
+	ID                  uint              `json:"id"`
+	AttendanceID        uint              `json:"attendance_id"`
+	UserID              uint              `json:"user_id"`
+	Action              string            `json:"action"`
+	Reason              string            `json:"reason"`
+	CorrectionRequestID *uint             `json:"correction_request_id,omitempty"`
+	Before              *AttendanceValues `json:"before,omitempty"`
+	After               *AttendanceValues `json:"after,omitempty"`
+	CreatedBy           uint              `json:"created_by"`
+	CreatedAt           time.Time         `json:"created_at"`
+}
+
+// CreateAttendanceCorrectionRequest asks to set the check-in, the check-out or both on a past date,
+// whatever is left out stays as it is.
+type CreateAttendanceCorrectionRequest struct {
+	Date       time.Time  `json:"date" binding:"required"`
+	CheckInAt  *time.Time `json:"check_in_at,omitempty"`
+	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
+	Reason     string     `json:"reason" binding:"required"`
+}
+
+type ReviewAttendanceCorrectionRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type AttendanceCorrectionResponse struct {
+	ID           uint       `json:"id"`
+	UserID       uint       `json:"user_id"`
+	Date         string     `json:"date"`
+	CheckInAt    *time.Time `json:"check_in_at,omitempty"`
+	CheckOutAt   *time.Time `json:"check_out_at,omitempty"`
+	Reason       string     `json:"reason"`
+	Status       string     `json:"status"`
+	ReviewedBy   *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+	AttendanceID *uint      `json:"attendance_id,omitempty"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type HolidayRequest struct {
+	Date time.Time `json:"date" binding:"required"`
+	Name string    `json:"name" binding:"required"`
+}
+
+type HolidayResponse struct {
+	ID     uint   `json:"id"`
+	Date   string `json:"date"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+type HolidayImportResponse struct {
+	Created  int               `json:"created"`
+	Updated  int               `json:"updated"`
+	Skipped  int               `json:"skipped"`
+	Holidays []HolidayResponse `json:"holidays"`
+}
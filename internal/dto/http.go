@@ -5,6 +5,20 @@ type SuccessResponse[T any] struct {
 	Data    T      `json:"data"`
 }
 
+// PageResponse is a page of a list, see utils.ParsePage for the query parameters.
+type PageResponse[T any] struct {
+	Message string   `json:"message"`
+	Data    T        `json:"data"`
+	Meta    PageMeta `json:"meta"`
+}
+
+type PageMeta struct {
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
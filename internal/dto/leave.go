@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type LeaveTypeResponse struct {
+	ID                uint   `json:"id"`
+	Code              string `json:"code"`
+	Name              string `json:"name"`
+	Paid              bool   `json:"paid"`
+	AnnualEntitlement int    `json:"annual_entitlement"`
+}
+
+type CreateLeaveRequest struct {
+	LeaveType string    `json:"leave_type" binding:"required"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+type ReviewLeaveRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type LeaveRequestResponse struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	LeaveType  string     `json:"leave_type"`
+	StartDate  string     `json:"start_date"`
+	EndDate    string     `json:"end_date"`
+	Days       int        `json:"days"`
+	Reason     string     `json:"reason,omitempty"`
+	Status     string     `json:"status"`
+	ReviewedBy *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+}
+
+type LeaveBalanceResponse struct {
+	LeaveType string `json:"leave_type"`
+	Year      int    `json:"year"`
+	Entitled  int    `json:"entitled"`
+	Used      int    `json:"used"`
+	Pending   int    `json:"pending"`
+	Available int    `json:"available"`
+}
@@ -1,10 +1,63 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type SubmitOvertimeRequest struct {
 	HoursWorked float64 `json:"hours_worked" binding:"required,gt=0,lte=3"`
+	// the day the overtime was worked, today when omitted
+	Date *time.Time `json:"date,omitempty"`
 }
 
 type SubmitOvertimeResponse struct {
 	ID          uint    `json:"id"`
+	Date        string  `json:"date"`
+	DayType     string  `json:"day_type"`
 	HoursWorked float64 `json:"hours_worked"`
+	Status      string  `json:"status"`
+}
+
+type ReviewOvertimeRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type OvertimeResponse struct {
+	ID           uint       `json:"id"`
+	UserID       uint       `json:"user_id"`
+	Date         string     `json:"date"`
+	HoursWorked  float64    `json:"hours_worked"`
+	Status       string     `json:"status"`
+	ReviewedBy   *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	ReviewReason string     `json:"review_reason,omitempty"`
+}
+
+type OvertimeRuleRequest struct {
+	DayType    string              `json:"day_type" binding:"required,oneof=weekday rest_day holiday"`
+	FromHour   decimal.Decimal     `json:"from_hour" swaggertype:"string"`
+	ToHour     decimal.NullDecimal `json:"to_hour" swaggertype:"string"`
+	Multiplier decimal.Decimal     `json:"multiplier" swaggertype:"string"`
+}
+
+type OvertimeRuleSetRequest struct {
+	Version       string                `json:"version" binding:"required"`
+	EffectiveFrom time.Time             `json:"effective_from" binding:"required"`
+	Rules         []OvertimeRuleRequest `json:"rules" binding:"required,dive"`
+}
+
+type OvertimeRuleResponse struct {
+	DayType    string              `json:"day_type"`
+	FromHour   decimal.Decimal     `json:"from_hour" swaggertype:"string"`
+	ToHour     decimal.NullDecimal `json:"to_hour" swaggertype:"string"`
+	Multiplier decimal.Decimal     `json:"multiplier" swaggertype:"string"`
+}
+
+type OvertimeRuleSetResponse struct {
+	ID            uint                   `json:"id"`
+	Version       string                 `json:"version"`
+	EffectiveFrom string                 `json:"effective_from"`
+	Rules         []OvertimeRuleResponse `json:"rules"`
 }
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PayComponentRequest struct {
+	Code            string          `json:"code" binding:"required"`
+	Name            string          `json:"name" binding:"required"`
+	Type            string          `json:"type" binding:"required,oneof=earning deduction"`
+	CalculationType string          `json:"calculation_type" binding:"required,oneof=fixed formula"`
+	Amount          decimal.Decimal `json:"amount" swaggertype:"string"`
+	Formula         string          `json:"formula,omitempty"`
+	Taxable         bool            `json:"taxable"`
+	Recurring       *bool           `json:"recurring,omitempty"`
+	Active          *bool           `json:"active,omitempty"`
+}
+
+type PayComponentResponse struct {
+	ID              uint            `json:"id"`
+	Code            string          `json:"code"`
+	Name            string          `json:"name"`
+	Type            string          `json:"type"`
+	CalculationType string          `json:"calculation_type"`
+	Amount          decimal.Decimal `json:"amount" swaggertype:"string"`
+	Formula         string          `json:"formula,omitempty"`
+	Taxable         bool            `json:"taxable"`
+	Recurring       bool            `json:"recurring"`
+	Active          bool            `json:"active"`
+}
+
+type PayComponentAssignmentRequest struct {
+	UserID        *uint               `json:"user_id,omitempty"`
+	RoleID        *uint               `json:"role_id,omitempty"`
+	Amount        decimal.NullDecimal `json:"amount,omitempty" swaggertype:"string"`
+	EffectiveFrom time.Time           `json:"effective_from" binding:"required"`
+	EffectiveTo   *time.Time          `json:"effective_to,omitempty"`
+}
+
+type PayComponentAssignmentResponse struct {
+	ID             uint                `json:"id"`
+	PayComponentID uint                `json:"pay_component_id"`
+	UserID         *uint               `json:"user_id,omitempty"`
+	RoleID         *uint               `json:"role_id,omitempty"`
+	Amount         decimal.NullDecimal `json:"amount" swaggertype:"string"`
+	EffectiveFrom  time.Time           `json:"effective_from"`
+	EffectiveTo    *time.Time          `json:"effective_to,omitempty"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type UpsertPayrollRequest struct {
 	Name        *string    `json:"name,omitempty"`
@@ -9,26 +13,114 @@ type UpsertPayrollRequest struct {
 }
 
 type PayrollResponse struct {
-	ID          uint       `json:"id"`
-	Name        string     `json:"name"`
-	PeriodStart *time.Time `json:"period_start,omitempty"`
-	PeriodEnd   *time.Time `json:"period_end,omitempty"`
-	Status      string     `json:"status"`
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	PeriodStart   *time.Time `json:"period_start,omitempty"`
+	PeriodEnd     *time.Time `json:"period_end,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
+	Version       int        `json:"version"`
+	ReopenedAt    *time.Time `json:"reopened_at,omitempty"`
+	ReopenReason  string     `json:"reopen_reason,omitempty"`
+
+	OpenAttendances int `json:"open_attendances"`
+}
+
+type ReopenPayrollRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 type PayrollSummaryResponse struct {
-	PayrollID     uint                   `json:"payroll_id"`
-	Year          int                    `json:"year"`
-	Month         int                    `json:"month"`
-	TotalSalaries float64                `json:"total_salaries"`
-	Payslips      []EmployeePayslipBrief `json:"payslips"`
+	PayrollID     uint            `json:"payroll_id"`
+	Year          int             `json:"year"`
+	Month         int             `json:"month"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+	Version       int             `json:"version"`
+	TotalSalaries decimal.Decimal `json:"total_salaries" swaggertype:"string"`
+	TotalTax      decimal.Decimal `json:"total_tax" swaggertype:"string"`
+	TotalNetPay   decimal.Decimal `json:"total_net_pay" swaggertype:"string"`
+
+	// employer side: gross pay plus employer BPJS contributions
+	TotalBPJSEmployee decimal.Decimal `json:"total_bpjs_employee" swaggertype:"string"`
+	TotalBPJSEmployer decimal.Decimal `json:"total_bpjs_employer" swaggertype:"string"`
+	TotalEmployerCost decimal.Decimal `json:"total_employer_cost" swaggertype:"string"`
+
+	// attendances in the period without a check-out when the payroll was run
+	OpenAttendances int `json:"open_attendances"`
+
+	Payslips []EmployeePayslipBrief `json:"payslips"`
 }
 
 type EmployeePayslipBrief struct {
-	UserID        uint    `json:"user_id"`
-	Username      string  `json:"username"`
-	BaseSalary    float64 `json:"base_salary"`
-	OvertimePay   float64 `json:"overtime_pay"`
-	Reimbursement float64 `json:"reimbursement"`
-	TotalPay      float64 `json:"total_pay"`
+	UserID          uint            `json:"user_id"`
+	Username        string          `json:"username"`
+	Kind            string          `json:"kind"`
+	PayBasis        string          `json:"pay_basis"`
+	BaseSalary      decimal.Decimal `json:"base_salary" swaggertype:"string"`
+	OvertimePay     decimal.Decimal `json:"overtime_pay" swaggertype:"string"`
+	Reimbursement   decimal.Decimal `json:"reimbursement" swaggertype:"string"`
+	OtherEarnings   decimal.Decimal `json:"other_earnings" swaggertype:"string"`
+	TotalPay        decimal.Decimal `json:"total_pay" swaggertype:"string"`
+	OtherDeductions decimal.Decimal `json:"other_deductions" swaggertype:"string"`
+	Tax             decimal.Decimal `json:"tax" swaggertype:"string"`
+	BPJSEmployee    decimal.Decimal `json:"bpjs_employee" swaggertype:"string"`
+	NetPay          decimal.Decimal `json:"net_pay" swaggertype:"string"`
+	BPJSEmployer    decimal.Decimal `json:"bpjs_employer" swaggertype:"string"`
+	EmployerCost    decimal.Decimal `json:"employer_cost" swaggertype:"string"`
+}
+
+type PayrollPreviewResponse struct {
+	PayrollID     uint            `json:"payroll_id"`
+	Year          int             `json:"year"`
+	Month         int             `json:"month"`
+	Status        string          `json:"status"`
+	TotalSalaries decimal.Decimal `json:"total_salaries" swaggertype:"string"`
+	TotalTax      decimal.Decimal `json:"total_tax" swaggertype:"string"`
+	TotalNetPay   decimal.Decimal `json:"total_net_pay" swaggertype:"string"`
+
+	TotalBPJSEmployee decimal.Decimal `json:"total_bpjs_employee" swaggertype:"string"`
+	TotalBPJSEmployer decimal.Decimal `json:"total_bpjs_employer" swaggertype:"string"`
+	TotalEmployerCost decimal.Decimal `json:"total_employer_cost" swaggertype:"string"`
+
+	Warnings []string                 `json:"warnings"`
+	Payslips []EmployeePayslipPreview `json:"payslips"`
+}
+
+type EmployeePayslipPreview struct {
+	EmployeePayslipBrief
+
+	// calculation context
+	MonthlySalary       decimal.Decimal `json:"monthly_salary" swaggertype:"string"`
+	ExpectedWorkingDays int             `json:"expected_working_days"`
+	EmployedWorkingDays int             `json:"employed_working_days"`
+	DaysAttended        int             `json:"days_attended"`
+	PaidLeaveDays       int             `json:"paid_leave_days"`
+	UnpaidLeaveDays     int             `json:"unpaid_leave_days"`
+	HourlyRate          decimal.Decimal `json:"hourly_rate" swaggertype:"string"`
+	OvertimeRatePerHour decimal.Decimal `json:"overtime_rate_per_hour" swaggertype:"string"`
+	TotalHoursWorked    float64         `json:"total_hours_worked"`
+	TotalOvertimeHours  float64         `json:"total_overtime_hours"`
+
+	HolidayOvertimeRatePerHour decimal.Decimal `json:"holiday_overtime_rate_per_hour" swaggertype:"string"`
+	HolidayOvertimeHours       float64         `json:"holiday_overtime_hours"`
+
+	// PPh 21 withholding
+	TaxableIncome decimal.Decimal `json:"taxable_income" swaggertype:"string"`
+	TaxRate       decimal.Decimal `json:"tax_rate" swaggertype:"string"`
+	TaxMethod     string          `json:"tax_method"`
+	PTKPStatus    string          `json:"ptkp_status"`
+
+	SalaryBreakdown []SalaryBreakdownItem `json:"salary_breakdown"`
+	BPJSBreakdown   []BPJSBreakdownItem   `json:"bpjs_breakdown"`
+	Lines           []PayslipLineItem     `json:"lines"`
+
+	AttendanceBreakdown []AttendanceBreakdownItem `json:"attendance_breakdown"`
+
+	Warnings []string `json:"warnings"`
 }
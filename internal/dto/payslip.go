@@ -1,44 +1,178 @@
 // dto/payslip_response.go
 package dto
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type AttendanceBreakdownItem struct {
 	Date string `json:"date"`
+
+	// per-day hours: the time between check-in and check-out and the hours paid for it,
+	// which are the shift's hours unless the employee is paid by the hour
+	CheckInAt   *time.Time      `json:"check_in_at,omitempty"`
+	CheckOutAt  *time.Time      `json:"check_out_at,omitempty"`
+	HoursWorked float64         `json:"hours_worked"`
+	BreakHours  decimal.Decimal `json:"break_hours" swaggertype:"string"`
+	PaidHours   decimal.Decimal `json:"paid_hours" swaggertype:"string"`
+	// what the day pays employees paid by the hour or day, 0 for monthly salaries
+	Amount decimal.Decimal `json:"amount" swaggertype:"string"`
 }
 
 type OvertimeBreakdownItem struct {
 	Date        string  `json:"date"`
 	HoursWorked float64 `json:"hours_worked"`
+	Holiday     bool    `json:"holiday"`
+	Status      string  `json:"status,omitempty"`
+	ReviewedBy  *uint   `json:"reviewed_by,omitempty"`
+	Reviewer    string  `json:"reviewer,omitempty"`
+
+	// rates applied, the amount is 0 for overtime that is not approved
+	DayType     string             `json:"day_type,omitempty"`
+	RuleVersion string             `json:"rule_version,omitempty"`
+	Multiplier  decimal.Decimal    `json:"multiplier" swaggertype:"string"`
+	Tiers       []OvertimeTierItem `json:"tiers,omitempty"`
+	Amount      decimal.Decimal    `json:"amount" swaggertype:"string"`
+}
+
+type OvertimeTierItem struct {
+	Hours      decimal.Decimal `json:"hours" swaggertype:"string"`
+	Multiplier decimal.Decimal `json:"multiplier" swaggertype:"string"`
+}
+
+type LeaveBreakdownItem struct {
+	Date      string `json:"date"`
+	LeaveType string `json:"leave_type"`
+	Paid      bool   `json:"paid"`
 }
 
 type ReimbursementBreakdownItem struct {
-	Date        string  `json:"date"`
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
+	ID          uint            `json:"id"`
+	Date        string          `json:"date"`
+	Category    string          `json:"category,omitempty"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string"`
+	Description string          `json:"description"`
+}
+
+type BPJSBreakdownItem struct {
+	Program        string          `json:"program"`
+	Base           decimal.Decimal `json:"base" swaggertype:"string"`
+	EmployeeRate   decimal.Decimal `json:"employee_rate" swaggertype:"string"`
+	EmployerRate   decimal.Decimal `json:"employer_rate" swaggertype:"string"`
+	EmployeeAmount decimal.Decimal `json:"employee_amount" swaggertype:"string"`
+	EmployerAmount decimal.Decimal `json:"employer_amount" swaggertype:"string"`
+}
+
+type SalaryBreakdownItem struct {
+	EffectiveFrom       string          `json:"effective_from,omitempty"`
+	Start               string          `json:"start"`
+	End                 string          `json:"end"`
+	Salary              decimal.Decimal `json:"salary" swaggertype:"string"`
+	ExpectedWorkingDays int             `json:"expected_working_days"`
+	DaysAttended        int             `json:"days_attended"`
+	LeaveDays           int             `json:"leave_days"`
+	Amount              decimal.Decimal `json:"amount" swaggertype:"string"`
+}
+
+type PayslipLineItem struct {
+	Code    string          `json:"code"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Amount  decimal.Decimal `json:"amount" swaggertype:"string"`
+	Taxable bool            `json:"taxable"`
 }
 
 type PayslipResponse struct {
-	ID     uint `json:"id"`
-	Month  int  `json:"month"`
-	Year   int  `json:"year"`
-	UserID uint `json:"user_id"`
+	ID     uint   `json:"id"`
+	Month  int    `json:"month"`
+	Year   int    `json:"year"`
+	UserID uint   `json:"user_id"`
+	Kind   string `json:"kind"`
+
+	PayBasis string `json:"pay_basis"`
+
+	// versioning
+	Version      int        `json:"version"`
+	SupersededAt *time.Time `json:"superseded_at,omitempty"`
 
 	// summary totals
-	BaseSalary    float64 `json:"base_salary"`
-	OvertimePay   float64 `json:"overtime_pay"`
-	Reimbursement float64 `json:"reimbursement"`
-	TotalSalary   float64 `json:"total_salary"`
+	BaseSalary    decimal.Decimal `json:"base_salary" swaggertype:"string"`
+	OvertimePay   decimal.Decimal `json:"overtime_pay" swaggertype:"string"`
+	Reimbursement decimal.Decimal `json:"reimbursement" swaggertype:"string"`
+	TotalSalary   decimal.Decimal `json:"total_salary" swaggertype:"string"`
+
+	// pay components
+	OtherEarnings   decimal.Decimal   `json:"other_earnings" swaggertype:"string"`
+	OtherDeductions decimal.Decimal   `json:"other_deductions" swaggertype:"string"`
+	Lines           []PayslipLineItem `json:"lines"`
+
+	// PPh 21 withholding
+	TaxableIncome   decimal.Decimal `json:"taxable_income" swaggertype:"string"`
+	TaxRate         decimal.Decimal `json:"tax_rate" swaggertype:"string"`
+	Tax             decimal.Decimal `json:"tax" swaggertype:"string"`
+	NetSalary       decimal.Decimal `json:"net_salary" swaggertype:"string"`
+	TaxMethod       string          `json:"tax_method"`
+	TaxTableVersion string          `json:"tax_table_version"`
+	PTKPStatus      string          `json:"ptkp_status"`
+
+	// BPJS contributions
+	BPJSEmployee decimal.Decimal `json:"bpjs_employee" swaggertype:"string"`
+	BPJSEmployer decimal.Decimal `json:"bpjs_employer" swaggertype:"string"`
 
 	// calculation context
-	MonthlySalary       float64 `json:"monthly_salary"`
-	ExpectedWorkingDays int     `json:"expected_working_days"`
-	DaysAttended        int     `json:"days_attended"`
-	HourlyRate          float64 `json:"hourly_rate"`
-	OvertimeRatePerHour float64 `json:"overtime_rate_per_hour"`
+	MonthlySalary       decimal.Decimal `json:"monthly_salary" swaggertype:"string"`
+	ExpectedWorkingDays int             `json:"expected_working_days"`
+	EmployedWorkingDays int             `json:"employed_working_days"`
+	DaysAttended        int             `json:"days_attended"`
+	PaidLeaveDays       int             `json:"paid_leave_days"`
+	UnpaidLeaveDays     int             `json:"unpaid_leave_days"`
+	HourlyRate          decimal.Decimal `json:"hourly_rate" swaggertype:"string"`
+	OvertimeRatePerHour decimal.Decimal `json:"overtime_rate_per_hour" swaggertype:"string"`
+
+	HolidayOvertimeRatePerHour decimal.Decimal `json:"holiday_overtime_rate_per_hour" swaggertype:"string"`
 
 	// breakdowns
 	TotalHoursWorked       float64                      `json:"total_hours_worked"`
 	TotalOvertimeHours     float64                      `json:"total_overtime_hours"`
+	HolidayOvertimeHours   float64                      `json:"holiday_overtime_hours"`
+	SalaryBreakdown        []SalaryBreakdownItem        `json:"salary_breakdown"`
 	AttendanceBreakdown    []AttendanceBreakdownItem    `json:"attendance_breakdown"`
 	OvertimeBreakdown      []OvertimeBreakdownItem      `json:"overtime_breakdown"`
+	LeaveBreakdown         []LeaveBreakdownItem         `json:"leave_breakdown"`
 	ReimbursementBreakdown []ReimbursementBreakdownItem `json:"reimbursement_breakdown"`
+	BPJSBreakdown          []BPJSBreakdownItem          `json:"bpjs_breakdown"`
+
+	YearToDate PayslipYearToDate `json:"year_to_date"`
+}
+
+// PayslipYearToDate sums the employee's payslips of the processed payrolls of the calendar year,
+// up to and including the payslip's month.
+type PayslipYearToDate struct {
+	Months int `json:"months"`
+	// gross pay: base salary, overtime and other earnings. Reimbursements are not pay and,
+	// like in taxable_income, are left out; they are summed in Reimbursement.
+	TotalSalary   decimal.Decimal `json:"total_salary" swaggertype:"string"`
+	OvertimePay   decimal.Decimal `json:"overtime_pay" swaggertype:"string"`
+	Reimbursement decimal.Decimal `json:"reimbursement" swaggertype:"string"`
+	TaxableIncome decimal.Decimal `json:"taxable_income" swaggertype:"string"`
+	Tax           decimal.Decimal `json:"tax" swaggertype:"string"`
+	NetSalary     decimal.Decimal `json:"net_salary" swaggertype:"string"`
+}
+
+type PayslipSummaryResponse struct {
+	ID          uint   `json:"id"`
+	Month       int    `json:"month"`
+	Year        int    `json:"year"`
+	Kind        string `json:"kind"`
+	Version     int    `json:"version"`
+	PeriodStart string `json:"period_start"`
+	PeriodEnd   string `json:"period_end"`
+
+	TotalSalary     decimal.Decimal `json:"total_salary" swaggertype:"string"`
+	OtherDeductions decimal.Decimal `json:"other_deductions" swaggertype:"string"`
+	Tax             decimal.Decimal `json:"tax" swaggertype:"string"`
+	BPJSEmployee    decimal.Decimal `json:"bpjs_employee" swaggertype:"string"`
+	NetSalary       decimal.Decimal `json:"net_salary" swaggertype:"string"`
 }
@@ -1,12 +1,66 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type SubmitReimbursementRequest struct {
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description *string `json:"description,omitempty"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string"`
+	Category    string          `json:"category,omitempty"`
+	Description *string         `json:"description,omitempty"`
 }
 
 type SubmitReimbursementResponse struct {
-	ID          uint    `json:"id"`
-	Amount      float64 `json:"amount"`
-	Description *string `json:"description,omitempty"`
+	ID          uint            `json:"id"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string"`
+	Category    string          `json:"category"`
+	Status      string          `json:"status"`
+	Description *string         `json:"description,omitempty"`
+
+	Attachments []ReimbursementAttachmentResponse `json:"attachments"`
+}
+
+type ReviewReimbursementRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type ReimbursementResponse struct {
+	ID           uint            `json:"id"`
+	UserID       uint            `json:"user_id"`
+	Date         string          `json:"date"`
+	Amount       decimal.Decimal `json:"amount" swaggertype:"string"`
+	Category     string          `json:"category"`
+	Description  string          `json:"description,omitempty"`
+	Status       string          `json:"status"`
+	ReviewedBy   *uint           `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewReason string          `json:"review_reason,omitempty"`
+	PayrollID    *uint           `json:"payroll_id,omitempty"`
+	PaidAt       *time.Time      `json:"paid_at,omitempty"`
+
+	Attachments []ReimbursementAttachmentResponse `json:"attachments"`
+}
+
+type ReimbursementAttachmentResponse struct {
+	ID          uint      `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ReimbursementCategoryRequest struct {
+	Name          string              `json:"name" binding:"required"`
+	PerClaimLimit decimal.NullDecimal `json:"per_claim_limit" swaggertype:"string"`
+	MonthlyLimit  decimal.NullDecimal `json:"monthly_limit" swaggertype:"string"`
+}
+
+type ReimbursementCategoryResponse struct {
+	ID            uint                `json:"id"`
+	Code          string              `json:"code"`
+	Name          string              `json:"name"`
+	PerClaimLimit decimal.NullDecimal `json:"per_claim_limit" swaggertype:"string"`
+	MonthlyLimit  decimal.NullDecimal `json:"monthly_limit" swaggertype:"string"`
 }
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ShiftRequest struct {
+	Name      string          `json:"name" binding:"required"`
+	StartTime string          `json:"start_time" binding:"required" example:"08:00"`
+	EndTime   string          `json:"end_time" binding:"required" example:"17:00"`
+	Hours     decimal.Decimal `json:"hours" swaggertype:"string" example:"8"`
+}
+
+type ShiftResponse struct {
+	ID        uint            `json:"id"`
+	Name      string          `json:"name"`
+	StartTime string          `json:"start_time"`
+	EndTime   string          `json:"end_time"`
+	Hours     decimal.Decimal `json:"hours" swaggertype:"string"`
+}
+
+type WorkPatternRequest struct {
+	Name      string    `json:"name" binding:"required"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	// the shift ID of every day of the cycle, null on rest days
+	Shifts []*uint `json:"shifts" binding:"required"`
+}
+
+type WorkPatternResponse struct {
+	ID        uint    `json:"id"`
+	Name      string  `json:"name"`
+	StartDate string  `json:"start_date"`
+	Shifts    []*uint `json:"shifts"`
+}
+
+type CreateUserWorkPatternRequest struct {
+	WorkPatternID uint      `json:"work_pattern_id" binding:"required"`
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"`
+}
+
+type UserWorkPatternResponse struct {
+	ID            uint   `json:"id"`
+	UserID        uint   `json:"user_id"`
+	WorkPatternID uint   `json:"work_pattern_id"`
+	WorkPattern   string `json:"work_pattern"`
+	EffectiveFrom string `json:"effective_from"`
+}
+
+type ScheduleDayResponse struct {
+	Date string `json:"date"`
+	// nil on a rest day
+	Shift   *ShiftResponse `json:"shift"`
+	Holiday string         `json:"holiday,omitempty"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type UpdateTaxProfileRequest struct {
+	PTKPStatus string  `json:"ptkp_status" binding:"required"`
+	NPWP       *string `json:"npwp,omitempty"`
+}
+
+type UserResponse struct {
+	ID         uint            `json:"id"`
+	Username   string          `json:"username"`
+	Salary     decimal.Decimal `json:"salary" swaggertype:"string"`
+	PTKPStatus string          `json:"ptkp_status"`
+	NPWP       string          `json:"npwp,omitempty"`
+
+	EmploymentStatus  string     `json:"employment_status"`
+	HireDate          *time.Time `json:"hire_date,omitempty"`
+	TerminationDate   *time.Time `json:"termination_date,omitempty"`
+	TerminationReason string     `json:"termination_reason,omitempty"`
+	ManagerID         *uint      `json:"manager_id,omitempty"`
+	Timezone          string     `json:"timezone,omitempty"`
+	PayBasis          string     `json:"pay_basis"`
+
+	OvertimeMonthlyCap *float64 `json:"overtime_monthly_cap,omitempty"`
+}
+
+type UpdateEmploymentRequest struct {
+	EmploymentStatus  string     `json:"employment_status" binding:"required,oneof=active inactive terminated"`
+	HireDate          *time.Time `json:"hire_date,omitempty"`
+	TerminationDate   *time.Time `json:"termination_date,omitempty"`
+	TerminationReason string     `json:"termination_reason,omitempty"`
+}
+
+type UpdateManagerRequest struct {
+	ManagerID *uint `json:"manager_id"`
+}
+
+type UpdatePayBasisRequest struct {
+	PayBasis string `json:"pay_basis" binding:"required,oneof=monthly hourly daily"`
+}
+
+type UpdateOvertimeCapRequest struct {
+	OvertimeMonthlyCap *float64 `json:"overtime_monthly_cap" binding:"omitempty,gte=0" example:"20"`
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" example:"Asia/Makassar"`
+}
+
+type CreateSalaryRequest struct {
+	Salary        decimal.Decimal `json:"salary" swaggertype:"string"`
+	EffectiveFrom time.Time       `json:"effective_from" binding:"required"`
+	Reason        string          `json:"reason,omitempty"`
+}
+
+type SalaryHistoryResponse struct {
+	ID            uint            `json:"id"`
+	UserID        uint            `json:"user_id"`
+	Salary        decimal.Decimal `json:"salary" swaggertype:"string"`
+	EffectiveFrom string          `json:"effective_from"`
+	Reason        string          `json:"reason,omitempty"`
+}
@@ -5,16 +5,19 @@ import (
 	"dealls-case-study/internal/dto"
 	"dealls-case-study/internal/models"
 	"dealls-case-study/internal/utils"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // CheckInAttendance godoc
 // @Summary      Submit check-in for current user
-// @Description  Allows an employee to check in for the current day.
-// @Description  Only one check-in is allowed per day. Check-ins on weekends are not allowed.
+// @Description  Allows an employee to check in for the current day, the day in the employee's timezone.
+// @Description  Only one check-in is allowed per day. Check-ins on the employee's rest days (weekends without a
+// @Description  work pattern) and public holidays are not allowed, work on a public holiday is submitted as overtime.
 // @Tags         Attendance
 // @Accept       json
 // @Produce      json
@@ -28,44 +31,63 @@ func CheckInAttendance(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	now := time.Now()
 
-	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot check in on weekends"})
+	// the day is the employee's, not the server's
+	loc, err := userLocation(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
 		return
 	}
+	today := utils.CalendarDate(now, loc)
 
-	var attendance models.Attendance
-	dateStr := now.Format("2006-01-02")
-	tx := db.DB.Where("user_id = ? AND DATE(date) = ?", userID, dateStr).First(&attendance)
+	schedule, err := findSchedule(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+		return
+	}
+	if schedule.ShiftOn(today) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot check in on a rest day"})
+		return
+	}
+	if holiday, ok := findHolidayOn(db.DB, today); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot check in on a public holiday (" + holiday.Name + "), submit the hours as overtime"})
+		return
+	}
 
-	if tx.Error == nil {
+	var attendance models.Attendance
+	err = db.DB.Where("user_id = ? AND date = ?", userID, today).First(&attendance).Error
+	if err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Already checked in today"})
 		return
 	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+		return
+	}
 
 	newAttendance := models.Attendance{
 		UserID:    userID,
-		Date:      now,
+		Date:      today,
 		CheckInAt: &now,
 		CreatedBy: userID,
 	}
 
-	if err := db.DB.Create(&newAttendance).Error; err != nil {
+	// the database backs the check, for check-ins racing each other
+	if err := db.DB.Create(&newAttendance).Error; errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Already checked in today"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.AttendanceResponse{
-		ID:         newAttendance.ID,
-		Date:       newAttendance.Date,
-		CheckInAt:  newAttendance.CheckInAt,
-		CheckOutAt: newAttendance.CheckOutAt,
-	}))
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceResponse(newAttendance)))
 }
 
 // CheckOutAttendance godoc
 // @Summary      Submit check-out for current user
-// @Description  Allows an employee to check out for the current day.
-// @Description  Must have checked in first. Only one check-out is allowed per day. Check-outs on weekends are not allowed.
+// @Description  Allows an employee to check out for the current day, the day in the employee's timezone.
+// @Description  Must have checked in first. Only one check-out is allowed per day. A night shift is checked out
+// @Description  the day after its check-in.
 // @Tags         Attendance
 // @Accept       json
 // @Produce      json
@@ -79,15 +101,28 @@ func CheckOutAttendance(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	now := time.Now()
 
-	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot check out on weekends"})
+	loc, err := userLocation(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check out"})
 		return
 	}
+	today := utils.CalendarDate(now, loc)
 
 	var attendance models.Attendance
-	dateStr := now.Format("2006-01-02")
-	tx := db.DB.Where("user_id = ? AND DATE(date) = ?", userID, dateStr).First(&attendance)
-
+	tx := db.DB.Where("user_id = ? AND date = ?", userID, today).First(&attendance)
+
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		// a night shift is checked out the day after it started
+		schedule, err := findSchedule(db.DB, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check out"})
+			return
+		}
+		yesterday := today.AddDate(0, 0, -1)
+		if shift := schedule.ShiftOn(yesterday); shift != nil && shift.Overnight() {
+			tx = db.DB.Where("user_id = ? AND date = ? AND check_out_at IS NULL", userID, yesterday).First(&attendance)
+		}
+	}
 	if tx.Error != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "You have not checked in today"})
 		return
@@ -106,10 +141,19 @@ func CheckOutAttendance(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.AttendanceResponse{
-		ID:         attendance.ID,
-		Date:       attendance.Date,
-		CheckInAt:  attendance.CheckInAt,
-		CheckOutAt: attendance.CheckOutAt,
-	}))
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceResponse(attendance)))
+}
+
+func toAttendanceResponse(attendance models.Attendance) dto.AttendanceResponse {
+	return dto.AttendanceResponse{
+		ID:          attendance.ID,
+		UserID:      attendance.UserID,
+		Date:        attendance.Date,
+		CheckInAt:   attendance.CheckInAt,
+		CheckOutAt:  attendance.CheckOutAt,
+		HoursWorked: attendance.HoursWorked,
+
+		AutoClosedAt: attendance.AutoClosedAt,
+		Anomaly:      attendance.Anomaly,
+	}
 }
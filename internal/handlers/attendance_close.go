@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CloseOpenAttendances handles the attendances of previous days that were never checked out.
+// Employees on a work pattern are checked out at the end of their shift, others at shiftEnd,
+// the time of day in the employee's timezone, through the correction path so the audit trail
+// shows it. Without a shift end, or when the check-in is after it or the period's payroll already
+// ran, they are flagged as anomalies for an admin to correct, as is one that can't be closed, e.g.
+// because the employee's timezone changed since. Flagged attendances are left alone until corrected.
+func CloseOpenAttendances(d *gorm.DB, now time.Time, shiftEnd time.Duration) (closed, flagged int, err error) {
+	// employees ahead of UTC may be a day further, the exact day is checked per employee
+	var attendances []models.Attendance
+	if err := d.Preload("User").
+		Where("check_in_at IS NOT NULL AND check_out_at IS NULL AND anomaly = ''").
+		Where("date <= ?", utils.CalendarDate(now, time.UTC)).
+		Order("date, id").
+		Find(&attendances).Error; err != nil {
+		return 0, 0, err
+	}
+
+	schedules := make(map[uint]models.Schedule)
+	for _, attendance := range attendances {
+		loc, err := utils.LoadTimezone(attendance.User.Timezone)
+		if err != nil {
+			if err := flagOpenAttendance(d, attendance, "no check-out, unknown timezone "+attendance.User.Timezone); err != nil {
+				return closed, flagged, err
+			}
+			flagged++
+			continue
+		}
+		if !attendance.Date.Before(utils.CalendarDate(now, loc)) {
+			continue
+		}
+
+		schedule, ok := schedules[attendance.UserID]
+		if !ok {
+			if schedule, err = findSchedule(d, attendance.UserID); err != nil {
+				return closed, flagged, err
+			}
+			schedules[attendance.UserID] = schedule
+		}
+		checkOut, known := scheduledCheckOut(schedule, attendance.Date, loc, shiftEnd)
+		if known && checkOut.After(now) {
+			// a night shift that has not ended yet
+			continue
+		}
+
+		var (
+			handled bool
+			anomaly string
+		)
+		err = d.Transaction(func(tx *gorm.DB) error {
+			// another instance may be handling the same attendance
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("check_out_at IS NULL AND anomaly = ''").
+				First(&attendance, attendance.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			handled = true
+
+			anomaly, err = closeOpenAttendance(tx, attendance, checkOut, known, now)
+			if err != nil || anomaly == "" {
+				return err
+			}
+			return flagOpenAttendance(tx, attendance, anomaly)
+		})
+		switch {
+		case err != nil:
+			return closed, flagged, err
+		case !handled:
+		case anomaly != "":
+			flagged++
+		default:
+			closed++
+		}
+	}
+
+	return closed, flagged, nil
+}
+
+// scheduledCheckOut is when the employee's shift on date ends, false when it is not known:
+// on a rest day of a work pattern, or without a work pattern and shiftEnd.
+func scheduledCheckOut(schedule models.Schedule, date time.Time, loc *time.Location, shiftEnd time.Duration) (time.Time, bool) {
+	if schedule.Rostered(date) {
+		if shift := schedule.ShiftOn(date); shift != nil {
+			return shift.EndOn(date, loc), true
+		}
+		return time.Time{}, false
+	}
+	if shiftEnd <= 0 {
+		return time.Time{}, false
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc).Add(shiftEnd), true
+}
+
+// closeOpenAttendance checks the employee out at the end of their shift.
+// It returns the anomaly to flag the attendance with instead when it can't.
+func closeOpenAttendance(tx *gorm.DB, attendance models.Attendance, checkOut time.Time, known bool, now time.Time) (string, error) {
+	if !known {
+		return "no check-out", nil
+	}
+	if !checkOut.After(*attendance.CheckInAt) {
+		return "no check-out, checked in after the shift end", nil
+	}
+
+	_, err := correctAttendance(tx, attendanceCorrection{
+		Action:       models.AttendanceAuditUpdate,
+		AttendanceID: attendance.ID,
+		CheckInAt:    attendance.CheckInAt,
+		CheckOutAt:   &checkOut,
+		Reason:       fmt.Sprintf("no check-out, closed at the %s shift end", checkOut.Format("15:04")),
+	}, 0)
+	if errors.Is(err, errPayrollLocked) {
+		return "no check-out in a period whose payroll already ran", nil
+	}
+	if errors.Is(err, errAttendanceCorrectionInvalid) {
+		return "no check-out, it could not be closed: " + err.Error(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", tx.Model(&models.Attendance{}).Where("id = ?", attendance.ID).Update("auto_closed_at", now).Error
+}
+
+// flagOpenAttendance flags the attendance with the anomaly, unless it was checked out meanwhile.
+func flagOpenAttendance(tx *gorm.DB, attendance models.Attendance, anomaly string) error {
+	return tx.Model(&models.Attendance{}).
+		Where("id = ? AND check_out_at IS NULL", attendance.ID).
+		Update("anomaly", anomaly).Error
+}
+
+// countOpenAttendances counts the attendances from start to end without a check-out.
+func countOpenAttendances(tx *gorm.DB, start, end time.Time) (int, error) {
+	var count int64
+	err := tx.Model(&models.Attendance{}).
+		Where("check_in_at IS NOT NULL AND check_out_at IS NULL").
+		Where("date BETWEEN ? AND ?", start, end).
+		Count(&count).Error
+	return int(count), err
+}
+
+// ListAttendanceAnomalies godoc
+// @Summary      List attendance anomalies
+// @Description  Lists the attendances flagged for review, e.g. without a check-out the attendance job could not
+// @Description  fill in, oldest first. Correcting an attendance clears its anomaly.
+// @Tags         Attendance
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.AttendanceResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances/anomalies [get]
+func ListAttendanceAnomalies(c *gin.Context) {
+	var attendances []models.Attendance
+	if err := db.DB.Where("anomaly <> ''").Order("date, id").Find(&attendances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list attendance anomalies"})
+		return
+	}
+
+	resp := make([]dto.AttendanceResponse, 0, len(attendances))
+	for _, attendance := range attendances {
+		resp = append(resp, toAttendanceResponse(attendance))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
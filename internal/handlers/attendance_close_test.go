@@ -0,0 +1,155 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseOpenAttendances(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	r := gin.Default()
+	r.Use(AuthStubMiddlewareForLeaves())
+	r.GET("/attendances/anomalies", handlers.ListAttendanceAnomalies)
+	r.GET("/attendances/:id/audits", handlers.ListAttendanceAudits)
+	r.POST("/payrolls/:year/:month/run", handlers.RunPayroll)
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 6, day, hour, 0, 0, 0, jakarta)
+		return &t
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	mayCheckIn := time.Date(2025, 5, 30, 8, 0, 0, 0, jakarta)
+	forgot := models.Attendance{UserID: 2, Date: date(6, 5), CheckInAt: at(5, 8)}
+	late := models.Attendance{UserID: 3, Date: date(6, 6), CheckInAt: at(6, 18)}
+	today := models.Attendance{UserID: 4, Date: date(6, 10), CheckInAt: at(10, 8)}
+	locked := models.Attendance{UserID: 2, Date: date(5, 30), CheckInAt: &mayCheckIn}
+	d.Create(&[]*models.Attendance{&forgot, &late, &today, &locked})
+	d.Create(&models.Payroll{
+		Month: 5, Year: 2025, Status: models.PayrollStatusProcessed,
+		PeriodStart: date(5, 1), PeriodEnd: date(5, 31),
+	})
+
+	now := time.Date(2025, 6, 10, 9, 0, 0, 0, jakarta)
+	closed, flagged, err := handlers.CloseOpenAttendances(d, now, 17*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	assert.Equal(t, 2, flagged)
+
+	d.First(&forgot, forgot.ID)
+	assert.True(t, forgot.CheckOutAt.Equal(*at(5, 17)))
+	assert.Equal(t, 9.0, forgot.HoursWorked)
+	assert.NotNil(t, forgot.AutoClosedAt)
+
+	// closed through the correction path, by nobody in particular
+	w := leaveRequest(r, 1, http.MethodGet, "/attendances/"+strconv.Itoa(int(forgot.ID))+"/audits", nil)
+	var audits dto.SuccessResponse[[]dto.AttendanceAuditResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &audits))
+	if assert.Len(t, audits.Data, 1) {
+		assert.Equal(t, uint(0), audits.Data[0].CreatedBy)
+		assert.Contains(t, audits.Data[0].Reason, "17:00")
+	}
+
+	d.First(&today, today.ID)
+	assert.Nil(t, today.CheckOutAt)
+	assert.Empty(t, today.Anomaly)
+
+	w = leaveRequest(r, 1, http.MethodGet, "/attendances/anomalies", nil)
+	var anomalies dto.SuccessResponse[[]dto.AttendanceResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &anomalies))
+	if assert.Len(t, anomalies.Data, 2) {
+		assert.Equal(t, locked.ID, anomalies.Data[0].ID)
+		assert.Contains(t, anomalies.Data[0].Anomaly, "payroll already ran")
+		assert.Equal(t, late.ID, anomalies.Data[1].ID)
+		assert.Contains(t, anomalies.Data[1].Anomaly, "after the shift end")
+	}
+
+	// flagged attendances wait for a correction
+	closed, flagged, err = handlers.CloseOpenAttendances(d, now, 17*time.Hour)
+	assert.NoError(t, err)
+	assert.Zero(t, closed)
+	assert.Zero(t, flagged)
+
+	// the flagged and today's attendances are still open
+	d.Create(&models.Payroll{Month: 6, Year: 2025, Status: models.PayrollStatusDraft, PeriodStart: date(6, 1), PeriodEnd: date(6, 30)})
+	w = leaveRequest(r, 1, http.MethodPost, "/payrolls/2025/6/run", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var run dto.SuccessResponse[dto.PayrollResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.Equal(t, 2, run.Data.OpenAttendances)
+}
+
+func TestCloseOpenAttendances_WithoutShiftEnd(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	checkIn := time.Date(2025, 6, 5, 8, 0, 0, 0, jakarta)
+	attendance := models.Attendance{UserID: 2, Date: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), CheckInAt: &checkIn}
+	d.Create(&attendance)
+
+	closed, flagged, err := handlers.CloseOpenAttendances(d, checkIn.AddDate(0, 0, 1), 0)
+	assert.NoError(t, err)
+	assert.Zero(t, closed)
+	assert.Equal(t, 1, flagged)
+
+	d.First(&attendance, attendance.ID)
+	assert.Nil(t, attendance.CheckOutAt)
+	assert.Equal(t, "no check-out", attendance.Anomaly)
+}
+
+func TestCloseOpenAttendances_FlagsWhatCannotBeClosed(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// employee 2's timezone is not a zone, employee 4 moved to Los Angeles: the check-in is on June 4th there
+	d.Model(&models.User{}).Where("id = ?", 2).Update("timezone", "Mars/Olympus")
+	d.Model(&models.User{}).Where("id = ?", 4).Update("timezone", "America/Los_Angeles")
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	checkIn := time.Date(2025, 6, 5, 8, 0, 0, 0, jakarta)
+	date := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	unknown := models.Attendance{UserID: 2, Date: date, CheckInAt: &checkIn}
+	moved := models.Attendance{UserID: 4, Date: date, CheckInAt: &checkIn}
+	fine := models.Attendance{UserID: 3, Date: date, CheckInAt: &checkIn}
+	d.Create(&[]*models.Attendance{&unknown, &moved, &fine})
+
+	// neither stops the others from being closed
+	closed, flagged, err := handlers.CloseOpenAttendances(d, checkIn.AddDate(0, 0, 5), 17*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	assert.Equal(t, 2, flagged)
+
+	d.First(&unknown, unknown.ID)
+	assert.Equal(t, "no check-out, unknown timezone Mars/Olympus", unknown.Anomaly)
+	d.First(&moved, moved.ID)
+	assert.Nil(t, moved.CheckOutAt)
+	assert.Contains(t, moved.Anomaly, "could not be closed")
+	d.First(&fine, fine.ID)
+	assert.NotNil(t, fine.CheckOutAt)
+}
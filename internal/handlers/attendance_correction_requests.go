@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateAttendanceCorrectionRequest godoc
+// @Summary      Request attendance correction
+// @Description  Requests a fix of the current user's attendance on a past date, e.g. a forgotten check-out.
+// @Description  Only dates in the open period can be corrected: from the day after the last pending or processed
+// @Description  payroll (or the first of the month when none ran yet) up to today. One pending request per date.
+// @Description  The request is reviewed by the user's manager or an admin.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.CreateAttendanceCorrectionRequest true "Correction"
+// @Success      201    {object}  dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections [post]
+func CreateAttendanceCorrectionRequest(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req dto.CreateAttendanceCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CheckInAt == nil && req.CheckOutAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check_in_at or check_out_at is required"})
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a reason is required"})
+		return
+	}
+
+	request := models.AttendanceCorrectionRequest{
+		UserID:     userID,
+		Date:       dateOnly(req.Date),
+		CheckInAt:  req.CheckInAt,
+		CheckOutAt: req.CheckOutAt,
+		Reason:     strings.TrimSpace(req.Reason),
+		Status:     models.CorrectionStatusPending,
+		CreatedBy:  userID,
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := userLocation(tx, userID)
+		if err != nil {
+			return err
+		}
+		today := utils.CalendarDate(time.Now(), loc)
+		start, err := openPeriodStart(tx, today)
+		if err != nil {
+			return err
+		}
+		if request.Date.Before(start) || request.Date.After(today) {
+			return fmt.Errorf("%w: corrections can only be requested from %s to %s", errAttendanceCorrectionInvalid,
+				start.Format("2006-01-02"), today.Format("2006-01-02"))
+		}
+
+		var pending int64
+		if err := tx.Model(&models.AttendanceCorrectionRequest{}).
+			Where("user_id = ? AND date = ? AND status = ?", userID, request.Date, models.CorrectionStatusPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%w: a correction for %s is already pending", errAttendanceCorrectionInvalid, request.DateOnlyString())
+		}
+
+		// the attendance as it would be once the request is approved must be valid
+		var attendance models.Attendance
+		if err := tx.Where("user_id = ? AND date = ?", userID, request.Date).First(&attendance).Error; err != nil &&
+			!errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		checkIn, checkOut := correctedTimes(attendance, request)
+		if err := validateAttendanceTimes(tx, userID, request.Date, checkIn, checkOut); err != nil {
+			return err
+		}
+		if err := checkPayrollNotLocked(tx, request.Date, request.Date); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&request).Error; errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: a correction for %s is already pending", errAttendanceCorrectionInvalid, request.DateOnlyString())
+		} else if err != nil {
+			return err
+		}
+		return nil
+	})
+	if errors.Is(err, errAttendanceCorrectionInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to request attendance correction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toAttendanceCorrectionResponse(request)))
+}
+
+// ListAttendanceCorrectionRequests godoc
+// @Summary      List own attendance correction requests
+// @Description  Lists the current user's attendance correction requests, most recent date first.
+// @Tags         Attendance
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.AttendanceCorrectionResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections [get]
+func ListAttendanceCorrectionRequests(c *gin.Context) {
+	var requests []models.AttendanceCorrectionRequest
+	if err := db.DB.
+		Where("user_id = ?", c.GetUint("user_id")).
+		Order("date DESC, id DESC").
+		Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list attendance correction requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceCorrectionResponses(requests)))
+}
+
+// ListPendingAttendanceCorrectionRequests godoc
+// @Summary      List attendance correction requests to review
+// @Description  Lists the pending correction requests the current user can review: those of their reports, or all for admins.
+// @Tags         Attendance
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.AttendanceCorrectionResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections/pending [get]
+func ListPendingAttendanceCorrectionRequests(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	query := db.DB.
+		Where("status = ?", models.CorrectionStatusPending).
+		Where("user_id <> ?", userID).
+		Order("date")
+	if c.GetString("role") != "Admin" {
+		query = query.Where("user_id IN (?)", db.DB.Model(&models.User{}).Select("id").Where("manager_id = ?", userID))
+	}
+
+	var requests []models.AttendanceCorrectionRequest
+	if err := query.Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list attendance correction requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceCorrectionResponses(requests)))
+}
+
+// ApproveAttendanceCorrectionRequest godoc
+// @Summary      Approve attendance correction request
+// @Description  Approves a pending correction request and applies it to the attendance like an admin correction,
+// @Description  recorded in the attendance's audit trail. Only the requester's manager or an admin can approve,
+// @Description  and not once the payroll covering the date is pending or processed.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Correction request ID"
+// @Param        request body     dto.ReviewAttendanceCorrectionRequest false "Review note"
+// @Success      200    {object}  dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections/{id}/approve [post]
+func ApproveAttendanceCorrectionRequest(c *gin.Context) {
+	reviewAttendanceCorrectionRequest(c, models.CorrectionStatusApproved)
+}
+
+// RejectAttendanceCorrectionRequest godoc
+// @Summary      Reject attendance correction request
+// @Description  Rejects a pending correction request. Only the requester's manager or an admin can reject.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Correction request ID"
+// @Param        request body     dto.ReviewAttendanceCorrectionRequest false "Review note"
+// @Success      200    {object}  dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections/{id}/reject [post]
+func RejectAttendanceCorrectionRequest(c *gin.Context) {
+	reviewAttendanceCorrectionRequest(c, models.CorrectionStatusRejected)
+}
+
+func reviewAttendanceCorrectionRequest(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid correction request id"})
+		return
+	}
+
+	var req dto.ReviewAttendanceCorrectionRequest
+	// the note is optional, so is the body
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var request models.AttendanceCorrectionRequest
+	if err := db.DB.Preload("User").First(&request, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "correction request not found"})
+		return
+	}
+
+	reviewerID := c.GetUint("user_id")
+	if !canReview(c, request.User) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the employee's manager or an admin can review this correction request"})
+		return
+	}
+	if request.Status != models.CorrectionStatusPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "correction request is already " + request.Status})
+		return
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// a concurrent review or cancellation waits here, and the status is checked again after it
+		var locked models.AttendanceCorrectionRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, request.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status != models.CorrectionStatusPending {
+			return fmt.Errorf("%w: correction request is already %s", errAttendanceCorrectionInvalid, locked.Status)
+		}
+
+		now := time.Now()
+		request.Status = status
+		request.ReviewedBy = &reviewerID
+		request.ReviewedAt = &now
+		request.ReviewNote = req.Note
+		request.UpdatedBy = reviewerID
+		if status == models.CorrectionStatusApproved {
+			attendance, err := applyAttendanceCorrectionRequest(tx, request, reviewerID)
+			if err != nil {
+				return err
+			}
+			request.AttendanceID = &attendance.ID
+		}
+		return tx.Omit("User").Save(&request).Error
+	})
+	if errors.Is(err, errAttendanceCorrectionInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review correction request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceCorrectionResponse(request)))
+}
+
+// CancelAttendanceCorrectionRequest godoc
+// @Summary      Cancel attendance correction request
+// @Description  Cancels one of the current user's pending correction requests.
+// @Tags         Attendance
+// @Produce      json
+// @Param        id     path      int  true  "Correction request ID"
+// @Success      200    {object}  dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance-corrections/{id}/cancel [post]
+func CancelAttendanceCorrectionRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid correction request id"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var request models.AttendanceCorrectionRequest
+	if err := db.DB.Where("id = ? AND user_id = ?", id, userID).First(&request).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "correction request not found"})
+		return
+	}
+	if request.Status != models.CorrectionStatusPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "correction request is already " + request.Status})
+		return
+	}
+
+	// only a request still pending is cancelled, a concurrent review of it wins
+	result := db.DB.Model(&models.AttendanceCorrectionRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.CorrectionStatusPending).
+		Updates(map[string]any{"status": models.CorrectionStatusCancelled, "updated_by": userID})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel correction request"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		var current models.AttendanceCorrectionRequest
+		if err := db.DB.Select("status").First(&current, request.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel correction request"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "correction request is already " + current.Status})
+		return
+	}
+	request.Status = models.CorrectionStatusCancelled
+	request.UpdatedBy = userID
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceCorrectionResponse(request)))
+}
+
+// applyAttendanceCorrectionRequest creates or updates the attendance on the request's date.
+func applyAttendanceCorrectionRequest(tx *gorm.DB, request models.AttendanceCorrectionRequest, reviewerID uint) (models.Attendance, error) {
+	correction := attendanceCorrection{
+		Action:    models.AttendanceAuditCreate,
+		UserID:    request.UserID,
+		Date:      request.Date,
+		Reason:    request.Reason,
+		RequestID: &request.ID,
+	}
+
+	var attendance models.Attendance
+	err := tx.Where("user_id = ? AND date = ?", request.UserID, request.Date).First(&attendance).Error
+	switch {
+	case err == nil:
+		correction.Action = models.AttendanceAuditUpdate
+		correction.AttendanceID = attendance.ID
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return attendance, err
+	}
+	correction.CheckInAt, correction.CheckOutAt = correctedTimes(attendance, request)
+
+	return correctAttendance(tx, correction, reviewerID)
+}
+
+// correctedTimes are the check-in and check-out of the attendance once the request is applied.
+func correctedTimes(attendance models.Attendance, request models.AttendanceCorrectionRequest) (checkIn, checkOut *time.Time) {
+	checkIn, checkOut = attendance.CheckInAt, attendance.CheckOutAt
+	if request.CheckInAt != nil {
+		checkIn = request.CheckInAt
+	}
+	if request.CheckOutAt != nil {
+		checkOut = request.CheckOutAt
+	}
+	return checkIn, checkOut
+}
+
+// openPeriodStart is the first day employees can request corrections for: the day after the last
+// pending or processed payroll, or the first of the month when none ran yet.
+func openPeriodStart(tx *gorm.DB, today time.Time) (time.Time, error) {
+	var payroll models.Payroll
+	err := tx.Where("status IN ?", []string{models.PayrollStatusPending, models.PayrollStatusProcessed}).
+		Order("period_end DESC").
+		First(&payroll).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dateOnly(payroll.PeriodEnd).AddDate(0, 0, 1), nil
+}
+
+func toAttendanceCorrectionResponse(request models.AttendanceCorrectionRequest) dto.AttendanceCorrectionResponse {
+	return dto.AttendanceCorrectionResponse{
+		ID:           request.ID,
+		UserID:       request.UserID,
+		Date:         request.DateOnlyString(),
+		CheckInAt:    request.CheckInAt,
+		CheckOutAt:   request.CheckOutAt,
+		Reason:       request.Reason,
+		Status:       request.Status,
+		ReviewedBy:   request.ReviewedBy,
+		ReviewedAt:   request.ReviewedAt,
+		ReviewNote:   request.ReviewNote,
+		AttendanceID: request.AttendanceID,
+	}
+}
+
+func toAttendanceCorrectionResponses(requests []models.AttendanceCorrectionRequest) []dto.AttendanceCorrectionResponse {
+	resp := make([]dto.AttendanceCorrectionResponse, 0, len(requests))
+	for _, request := range requests {
+		resp = append(resp, toAttendanceCorrectionResponse(request))
+	}
+	return resp
+}
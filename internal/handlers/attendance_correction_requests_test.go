@@ -0,0 +1,194 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestRouterForAttendanceCorrectionRequests() *gin.Engine {
+	r := setupTestRouterForAttendanceCorrections()
+	r.POST("/attendance-corrections", handlers.CreateAttendanceCorrectionRequest)
+	r.GET("/attendance-corrections", handlers.ListAttendanceCorrectionRequests)
+	r.GET("/attendance-corrections/pending", handlers.ListPendingAttendanceCorrectionRequests)
+	r.POST("/attendance-corrections/:id/approve", handlers.ApproveAttendanceCorrectionRequest)
+	r.POST("/attendance-corrections/:id/reject", handlers.RejectAttendanceCorrectionRequest)
+	r.POST("/attendance-corrections/:id/cancel", handlers.CancelAttendanceCorrectionRequest)
+	return r
+}
+
+func TestAttendanceCorrectionRequests(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	r := setupTestRouterForAttendanceCorrectionRequests()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	today := utils.CalendarDate(time.Now(), jakarta)
+	at := func(hour int) string {
+		return time.Date(today.Year(), today.Month(), today.Day(), hour, 0, 0, 0, jakarta).Format(time.RFC3339)
+	}
+
+	// employee 2 checked in today but forgot to check out
+	checkIn := time.Date(today.Year(), today.Month(), today.Day(), 8, 0, 0, 0, jakarta)
+	d.Create(&models.Attendance{UserID: 2, Date: today, CheckInAt: &checkIn})
+
+	w := leaveRequest(r, 2, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today.AddDate(0, 0, 1), "check_out_at": at(18), "reason": "not yet",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "can only be requested")
+
+	w = leaveRequest(r, 2, http.MethodPost, "/attendance-corrections", gin.H{"date": today, "reason": "nothing"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "check_in_at or check_out_at is required")
+
+	w = leaveRequest(r, 2, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today, "check_out_at": at(18), "reason": "forgot to check out",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, models.CorrectionStatusPending, created.Data.Status)
+	path := "/attendance-corrections/" + strconv.Itoa(int(created.Data.ID))
+
+	w = leaveRequest(r, 2, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today, "check_out_at": at(19), "reason": "forgot to check out",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already pending")
+
+	// there is no attendance to add a check-out to
+	w = leaveRequest(r, 4, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today, "check_out_at": at(18), "reason": "forgot to check out",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "check_in_at is required")
+
+	w = leaveRequest(r, 3, http.MethodGet, "/attendance-corrections/pending", nil)
+	var pending dto.SuccessResponse[[]dto.AttendanceCorrectionResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	assert.Len(t, pending.Data, 1)
+
+	// only the manager reviews
+	w = leaveRequest(r, 4, http.MethodPost, path+"/approve", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = leaveRequest(r, 2, http.MethodPost, path+"/approve", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = leaveRequest(r, 3, http.MethodPost, path+"/approve", gin.H{"note": "ok, remember next time"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var approved dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &approved))
+	assert.Equal(t, models.CorrectionStatusApproved, approved.Data.Status)
+	assert.Equal(t, "ok, remember next time", approved.Data.ReviewNote)
+
+	var attendance models.Attendance
+	d.Where("user_id = ? AND date = ?", 2, today).First(&attendance)
+	assert.Equal(t, attendance.ID, *approved.Data.AttendanceID)
+	assert.Equal(t, 10.0, attendance.HoursWorked)
+
+	// applied like an admin correction
+	w = leaveRequest(r, 1, http.MethodGet, "/attendances/"+strconv.Itoa(int(attendance.ID))+"/audits", nil)
+	var audits dto.SuccessResponse[[]dto.AttendanceAuditResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &audits))
+	if assert.Len(t, audits.Data, 1) {
+		assert.Equal(t, models.AttendanceAuditUpdate, audits.Data[0].Action)
+		assert.Equal(t, created.Data.ID, *audits.Data[0].CorrectionRequestID)
+		assert.Equal(t, uint(3), audits.Data[0].CreatedBy)
+		assert.Equal(t, "forgot to check out", audits.Data[0].Reason)
+	}
+
+	w = leaveRequest(r, 3, http.MethodPost, path+"/reject", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already approved")
+
+	// once the payroll covering the date runs, pending requests can't be approved and new ones not made
+	w = leaveRequest(r, 2, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today, "check_in_at": at(7), "reason": "came in early",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var second dto.SuccessResponse[dto.AttendanceCorrectionResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &second))
+	secondPath := "/attendance-corrections/" + strconv.Itoa(int(second.Data.ID))
+
+	d.Create(&models.Payroll{
+		Month: int(today.Month()), Year: today.Year(), Status: models.PayrollStatusProcessed,
+		PeriodStart: today.AddDate(0, 0, -today.Day()+1),
+		PeriodEnd:   today,
+	})
+
+	w = leaveRequest(r, 3, http.MethodPost, secondPath+"/approve", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
+
+	w = leaveRequest(r, 4, http.MethodPost, "/attendance-corrections", gin.H{
+		"date": today, "check_in_at": at(8), "reason": "server down",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "can only be requested")
+
+	w = leaveRequest(r, 4, http.MethodPost, secondPath+"/cancel", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = leaveRequest(r, 2, http.MethodPost, secondPath+"/cancel", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), models.CorrectionStatusCancelled)
+
+	w = leaveRequest(r, 2, http.MethodGet, "/attendance-corrections", nil)
+	var own dto.SuccessResponse[[]dto.AttendanceCorrectionResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &own))
+	assert.Len(t, own.Data, 2)
+}
+
+func TestAttendanceCorrectionRequests_ConcurrentApproval(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "UTC")
+	r := setupTestRouterForAttendanceCorrectionRequests()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	today := utils.CalendarDate(time.Now(), time.UTC)
+	checkIn, checkOut := today.Add(8*time.Hour), today.Add(17*time.Hour)
+	attendance := models.Attendance{UserID: 2, Date: today, CheckInAt: &checkIn}
+	d.Create(&attendance)
+	request := models.AttendanceCorrectionRequest{UserID: 2, Date: today, CheckOutAt: &checkOut, Reason: "forgot to check out", Status: models.CorrectionStatusPending}
+	d.Create(&request)
+
+	// a second pending request for the date is refused by the database too
+	err = d.Create(&models.AttendanceCorrectionRequest{UserID: 2, Date: today, CheckOutAt: &checkOut, Reason: "again", Status: models.CorrectionStatusPending}).Error
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	// two approvals at the same time apply the correction once
+	path := "/attendance-corrections/" + strconv.Itoa(int(request.ID)) + "/approve"
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = leaveRequest(r, 3, http.MethodPost, path, nil).Code
+		}()
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusBadRequest}, codes)
+	var audits int64
+	d.Model(&models.AttendanceAudit{}).Where("attendance_id = ?", attendance.ID).Count(&audits)
+	assert.Equal(t, int64(1), audits)
+}
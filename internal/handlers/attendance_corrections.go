@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errAttendanceCorrectionInvalid wraps errors that are reported to the client as a bad request.
+var errAttendanceCorrectionInvalid = errors.New("invalid attendance correction")
+
+// attendanceCorrection is a change to an attendance. Creates name the user, updates and voids the attendance.
+type attendanceCorrection struct {
+	Action       string
+	AttendanceID uint
+	UserID       uint
+	Date         time.Time
+	CheckInAt    *time.Time
+	CheckOutAt   *time.Time
+	Reason       string
+	// RequestID is the employee's correction request being approved, if any
+	RequestID *uint
+}
+
+// CreateAttendance godoc
+// @Summary      Create attendance
+// @Description  Creates an attendance for any employee and date, e.g. when the server was down at check-in.
+// @Description  The check-in must fall on the date in the employee's timezone. The reason is kept in the audit trail.
+// @Description  Not allowed in a period whose payroll is pending or processed, unless that payroll is reopened.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.CreateAttendanceRequest true "Attendance"
+// @Success      201    {object}  dto.SuccessResponse[dto.AttendanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances [post]
+func CreateAttendance(c *gin.Context) {
+	var req dto.CreateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var attendance models.Attendance
+	err := db.DB.Transaction(func(tx *gorm.DB) (err error) {
+		attendance, err = correctAttendance(tx, attendanceCorrection{
+			Action:     models.AttendanceAuditCreate,
+			UserID:     req.UserID,
+			Date:       req.Date,
+			CheckInAt:  req.CheckInAt,
+			CheckOutAt: req.CheckOutAt,
+			Reason:     req.Reason,
+		}, c.GetUint("user_id"))
+		return err
+	})
+	if err != nil {
+		respondAttendanceCorrectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toAttendanceResponse(attendance)))
+}
+
+// UpdateAttendance godoc
+// @Summary      Correct attendance
+// @Description  Replaces the check-in and check-out of an attendance, a null check_out_at removes the check-out.
+// @Description  The reason and the values before and after are kept in the audit trail.
+// @Description  Not allowed in a period whose payroll is pending or processed, unless that payroll is reopened.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Attendance ID"
+// @Param        request body     dto.UpdateAttendanceRequest true "Correction"
+// @Success      200    {object}  dto.SuccessResponse[dto.AttendanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances/{id} [put]
+func UpdateAttendance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance id"})
+		return
+	}
+
+	var req dto.UpdateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	correction := attendanceCorrection{
+		Action:       models.AttendanceAuditUpdate,
+		AttendanceID: uint(id),
+		CheckInAt:    req.CheckInAt,
+		CheckOutAt:   req.CheckOutAt,
+		Reason:       req.Reason,
+	}
+	if req.Date != nil {
+		correction.Date = *req.Date
+	}
+
+	var attendance models.Attendance
+	err = db.DB.Transaction(func(tx *gorm.DB) (err error) {
+		attendance, err = correctAttendance(tx, correction, c.GetUint("user_id"))
+		return err
+	})
+	if err != nil {
+		respondAttendanceCorrectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceResponse(attendance)))
+}
+
+// VoidAttendance godoc
+// @Summary      Void attendance
+// @Description  Removes an attendance recorded by mistake. Its values and the reason are kept in the audit trail.
+// @Description  Not allowed in a period whose payroll is pending or processed, unless that payroll is reopened.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Attendance ID"
+// @Param        request body     dto.VoidAttendanceRequest true "Reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.AttendanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances/{id}/void [post]
+func VoidAttendance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance id"})
+		return
+	}
+
+	var req dto.VoidAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var attendance models.Attendance
+	err = db.DB.Transaction(func(tx *gorm.DB) (err error) {
+		attendance, err = correctAttendance(tx, attendanceCorrection{
+			Action:       models.AttendanceAuditVoid,
+			AttendanceID: uint(id),
+			Reason:       req.Reason,
+		}, c.GetUint("user_id"))
+		return err
+	})
+	if err != nil {
+		respondAttendanceCorrectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toAttendanceResponse(attendance)))
+}
+
+// ListAttendanceAudits godoc
+// @Summary      List attendance corrections
+// @Description  Lists the corrections of an attendance, oldest first, also of a voided one.
+// @Tags         Attendance
+// @Produce      json
+// @Param        id     path      int  true  "Attendance ID"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.AttendanceAuditResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances/{id}/audits [get]
+func ListAttendanceAudits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance id"})
+		return
+	}
+
+	var audits []models.AttendanceAudit
+	if err := db.DB.Where("attendance_id = ?", id).Order("id").Find(&audits).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list attendance corrections"})
+		return
+	}
+
+	resp := make([]dto.AttendanceAuditResponse, 0, len(audits))
+	for _, audit := range audits {
+		resp = append(resp, toAttendanceAuditResponse(audit))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// correctAttendance creates, updates or voids an attendance and records the change in the audit trail.
+// Admin corrections and approved correction requests both go through it. A missing attendance is
+// gorm.ErrRecordNotFound.
+func correctAttendance(tx *gorm.DB, correction attendanceCorrection, actorID uint) (models.Attendance, error) {
+	reason := strings.TrimSpace(correction.Reason)
+	if reason == "" {
+		return models.Attendance{}, fmt.Errorf("%w: a reason is required", errAttendanceCorrectionInvalid)
+	}
+
+	var attendance models.Attendance
+	var before *models.Attendance
+	if correction.Action == models.AttendanceAuditCreate {
+		var user models.User
+		if err := tx.Select("id").First(&user, correction.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return attendance, fmt.Errorf("%w: user not found", errAttendanceCorrectionInvalid)
+			}
+			return attendance, err
+		}
+		attendance = models.Attendance{UserID: user.ID, CreatedBy: actorID}
+	} else {
+		if err := tx.First(&attendance, correction.AttendanceID).Error; err != nil {
+			return attendance, err
+		}
+		previous := attendance
+		before = &previous
+		if err := checkPayrollNotLocked(tx, before.Date, before.Date); err != nil {
+			return attendance, err
+		}
+	}
+
+	if correction.Action != models.AttendanceAuditVoid {
+		date := attendance.Date
+		if !correction.Date.IsZero() {
+			date = dateOnly(correction.Date)
+		}
+		if err := validateAttendanceTimes(tx, attendance.UserID, date, correction.CheckInAt, correction.CheckOutAt); err != nil {
+			return attendance, err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Attendance{}).
+			Where("user_id = ? AND date = ? AND id <> ?", attendance.UserID, date, attendance.ID).
+			Count(&existing).Error; err != nil {
+			return attendance, err
+		}
+		if existing > 0 {
+			return attendance, fmt.Errorf("%w: the employee already has an attendance on %s", errAttendanceCorrectionInvalid, date.Format("2006-01-02"))
+		}
+		if err := checkPayrollNotLocked(tx, date, date); err != nil {
+			return attendance, err
+		}
+
+		attendance.Date = date
+		attendance.CheckInAt = correction.CheckInAt
+		attendance.CheckOutAt = correction.CheckOutAt
+		attendance.UpdatedBy = actorID
+		// the corrected attendance is what it should be, nothing left to review
+		attendance.AutoClosedAt = nil
+		attendance.Anomaly = ""
+	}
+
+	var err error
+	switch correction.Action {
+	case models.AttendanceAuditCreate:
+		err = tx.Create(&attendance).Error
+	case models.AttendanceAuditUpdate:
+		err = tx.Omit("User").Save(&attendance).Error
+	case models.AttendanceAuditVoid:
+		err = tx.Delete(&attendance).Error
+	default:
+		err = fmt.Errorf("unknown attendance correction %q", correction.Action)
+	}
+	if err != nil {
+		return attendance, err
+	}
+
+	audit := models.AttendanceAudit{
+		AttendanceID: attendance.ID,
+		UserID:       attendance.UserID,
+		Action:       correction.Action,
+		Reason:       reason,
+		CreatedBy:    actorID,
+
+		CorrectionRequestID: correction.RequestID,
+	}
+	if before != nil {
+		audit.BeforeDate, audit.BeforeCheckInAt, audit.BeforeCheckOutAt = &before.Date, before.CheckInAt, before.CheckOutAt
+	}
+	if correction.Action != models.AttendanceAuditVoid {
+		audit.AfterDate, audit.AfterCheckInAt, audit.AfterCheckOutAt = &attendance.Date, attendance.CheckInAt, attendance.CheckOutAt
+	}
+	return attendance, tx.Create(&audit).Error
+}
+
+// validateAttendanceTimes checks that the check-in falls on the date in the employee's timezone
+// and the check-out, if any, after it.
+func validateAttendanceTimes(tx *gorm.DB, userID uint, date time.Time, checkIn, checkOut *time.Time) error {
+	if checkIn == nil {
+		return fmt.Errorf("%w: check_in_at is required", errAttendanceCorrectionInvalid)
+	}
+	loc, err := userLocation(tx, userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CalendarDate(*checkIn, loc).Equal(date) {
+		return fmt.Errorf("%w: check_in_at is not on %s in the employee's timezone (%s)", errAttendanceCorrectionInvalid, date.Format("2006-01-02"), loc)
+	}
+	if checkOut != nil && !checkOut.After(*checkIn) {
+		return fmt.Errorf("%w: check_out_at must be after check_in_at", errAttendanceCorrectionInvalid)
+	}
+	return nil
+}
+
+func respondAttendanceCorrectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+	case errors.Is(err, errAttendanceCorrectionInvalid) || errors.Is(err, errPayrollLocked):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to correct attendance"})
+	}
+}
+
+func toAttendanceAuditResponse(audit models.AttendanceAudit) dto.AttendanceAuditResponse {
+	resp := dto.AttendanceAuditResponse{
+		ID:           audit.ID,
+		AttendanceID: audit.AttendanceID,
+		UserID:       audit.UserID,
+		Action:       audit.Action,
+		Reason:       audit.Reason,
+		CreatedBy:    audit.CreatedBy,
+		CreatedAt:    audit.CreatedAt,
+
+		CorrectionRequestID: audit.CorrectionRequestID,
+	}
+	if audit.BeforeDate != nil {
+		resp.Before = &dto.AttendanceValues{
+			Date:       audit.BeforeDate.Format("2006-01-02"),
+			CheckInAt:  audit.BeforeCheckInAt,
+			CheckOutAt: audit.BeforeCheckOutAt,
+		}
+	}
+	if audit.AfterDate != nil {
+		resp.After = &dto.AttendanceValues{
+			Date:       audit.AfterDate.Format("2006-01-02"),
+			CheckInAt:  audit.AfterCheckInAt,
+			CheckOutAt: audit.AfterCheckOutAt,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestRouterForAttendanceCorrections() *gin.Engine {
+	r := gin.Default()
+	r.Use(AuthStubMiddlewareForLeaves())
+	r.POST("/attendances", handlers.CreateAttendance)
+	r.PUT("/attendances/:id", handlers.UpdateAttendance)
+	r.POST("/attendances/:id/void", handlers.VoidAttendance)
+	r.GET("/attendances/:id/audits", handlers.ListAttendanceAudits)
+	return r
+}
+
+func TestAttendanceCorrections(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	r := setupTestRouterForAttendanceCorrections()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// a reason is mandatory
+	w := leaveRequest(r, 1, http.MethodPost, "/attendances", gin.H{
+		"user_id": 2, "date": "2025-06-05T00:00:00Z", "check_in_at": "2025-06-05T08:00:00+07:00",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 20:00 UTC on the 6th is already the 7th in Jakarta
+	w = leaveRequest(r, 1, http.MethodPost, "/attendances", gin.H{
+		"user_id": 2, "date": "2025-06-06T00:00:00Z", "check_in_at": "2025-06-06T20:00:00Z", "reason": "server down",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "not on 2025-06-06")
+
+	w = leaveRequest(r, 1, http.MethodPost, "/attendances", gin.H{
+		"user_id": 2, "date": "2025-06-05T00:00:00Z", "check_in_at": "2025-06-05T08:00:00+07:00", "reason": "server down",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created dto.SuccessResponse[dto.AttendanceResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, uint(2), created.Data.UserID)
+	id := created.Data.ID
+
+	w = leaveRequest(r, 1, http.MethodPost, "/attendances", gin.H{
+		"user_id": 2, "date": "2025-06-05T00:00:00Z", "check_in_at": "2025-06-05T09:00:00+07:00", "reason": "again",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already has an attendance")
+
+	// the employee forgot to check out
+	path := "/attendances/" + strconv.Itoa(int(id))
+	w = leaveRequest(r, 1, http.MethodPut, path, gin.H{
+		"check_in_at": "2025-06-05T08:00:00+07:00", "check_out_at": "2025-06-05T08:00:00+07:00", "reason": "forgot to check out",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "check_out_at must be after check_in_at")
+
+	w = leaveRequest(r, 1, http.MethodPut, path, gin.H{
+		"check_in_at": "2025-06-05T08:00:00+07:00", "check_out_at": "2025-06-05T17:00:00+07:00", "reason": "forgot to check out",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var updated dto.SuccessResponse[dto.AttendanceResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 9.0, updated.Data.HoursWorked)
+
+	w = leaveRequest(r, 1, http.MethodPut, "/attendances/999", gin.H{
+		"check_in_at": "2025-06-05T08:00:00+07:00", "reason": "missing",
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// no corrections once the payroll of the period is processed, until it is reopened
+	payroll := models.Payroll{
+		Month: 6, Year: 2025, Status: models.PayrollStatusProcessed,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+
+	w = leaveRequest(r, 1, http.MethodPost, path+"/void", gin.H{"reason": "was on leave"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
+
+	w = leaveRequest(r, 1, http.MethodPost, "/attendances", gin.H{
+		"user_id": 4, "date": "2025-06-05T00:00:00Z", "check_in_at": "2025-06-05T08:00:00+07:00", "reason": "server down",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
+
+	d.Model(&payroll).Update("status", models.PayrollStatusDraft)
+
+	w = leaveRequest(r, 1, http.MethodPost, path+"/void", gin.H{"reason": "was on leave"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	d.Model(&models.Attendance{}).Where("id = ?", id).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	w = leaveRequest(r, 1, http.MethodGet, path+"/audits", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var audits dto.SuccessResponse[[]dto.AttendanceAuditResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &audits))
+	if assert.Len(t, audits.Data, 3) {
+		create, update, void := audits.Data[0], audits.Data[1], audits.Data[2]
+
+		assert.Equal(t, models.AttendanceAuditCreate, create.Action)
+		assert.Nil(t, create.Before)
+		assert.Equal(t, "2025-06-05", create.After.Date)
+		assert.Equal(t, "server down", create.Reason)
+
+		assert.Equal(t, models.AttendanceAuditUpdate, update.Action)
+		assert.Nil(t, update.Before.CheckOutAt)
+		assert.NotNil(t, update.After.CheckOutAt)
+		assert.Equal(t, uint(1), update.CreatedBy)
+
+		assert.Equal(t, models.AttendanceAuditVoid, void.Action)
+		assert.NotNil(t, void.Before.CheckOutAt)
+		assert.Nil(t, void.After)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"dealls-case-study/internal/dto"
 	"dealls-case-study/internal/handlers"
 	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -50,7 +51,7 @@ func setupTestRouterforAtt() *gin.Engine {
 func TestCheckInAttendance(t *testing.T) {
 	r := setupTestRouterforAtt()
 
-	_, cleanup, err := setupTestDBforAtt()
+	d, cleanup, err := setupTestDBforAtt()
 	if err != nil {
 		t.Fatalf("failed to set up test DB: %v", err)
 	}
@@ -68,10 +69,20 @@ func TestCheckInAttendance(t *testing.T) {
 	err1 := json.Unmarshal(w.Body.Bytes(), &att)
 	assert.Nil(t, err1)
 	assert.Equal(t, att.Data.ID, uint(1))
-	assert.Equal(t, att.Data.Date.Day(), time.Now().Day())
-	assert.Equal(t, att.Data.Date.Month(), time.Now().Month())
-	assert.Equal(t, att.Data.Date.Year(), time.Now().Year())
+	today := utils.CalendarDate(time.Now(), utils.CompanyLocation())
+	assert.Equal(t, att.Data.Date.Day(), today.Day())
+	assert.Equal(t, att.Data.Date.Month(), today.Month())
+	assert.Equal(t, att.Data.Date.Year(), today.Year())
 	assert.NotNil(t, att.Data.CheckInAt)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/attendances/check-in", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Already checked in today")
+	// the database backs the check, for check-ins racing each other
+	err = d.Create(&models.Attendance{UserID: 1, Date: today, CheckInAt: &today}).Error
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
 }
 
 func TestCheckOutAttendance(t *testing.T) {
@@ -85,7 +96,7 @@ func TestCheckOutAttendance(t *testing.T) {
 	checkInTime := now.Add(-9 * time.Hour)
 	att := models.Attendance{
 		UserID:    1,
-		Date:      now,
+		Date:      utils.CalendarDate(now, utils.CompanyLocation()),
 		CheckInAt: &checkInTime,
 		CreatedBy: 1,
 	}
@@ -105,3 +116,57 @@ func TestCheckOutAttendance(t *testing.T) {
 	assert.Nil(t, err1)
 	assert.NotNil(t, updated.Data.CheckOutAt)
 }
+
+func TestCheckInAttendance_Holiday(t *testing.T) {
+	r := setupTestRouterforAtt()
+
+	d, cleanup, err := setupTestDBforAtt()
+	if err != nil {
+		t.Fatalf("failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	today := utils.CalendarDate(time.Now(), utils.CompanyLocation())
+	d.Create(&models.Holiday{
+		Date: today,
+		Name: "Hari Kemerdekaan",
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/attendances/check-in", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if today.Weekday() != time.Saturday && today.Weekday() != time.Sunday {
+		assert.Contains(t, w.Body.String(), "public holiday")
+	}
+}
+
+func TestCheckInAttendance_EmployeeTimezone(t *testing.T) {
+	r := setupTestRouterforAtt()
+
+	d, cleanup, err := setupTestDBforAtt()
+	if err != nil {
+		t.Fatalf("failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	// the day is taken in Jayapura, two hours ahead of Jakarta
+	d.Model(&models.User{}).Where("id = ?", 1).Update("timezone", "Asia/Jayapura")
+	jayapura, _ := time.LoadLocation("Asia/Jayapura")
+	today := utils.CalendarDate(time.Now(), jayapura)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/attendances/check-in", nil)
+	r.ServeHTTP(w, req)
+
+	if today.Weekday() == time.Saturday || today.Weekday() == time.Sunday {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		return
+	}
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var att models.Attendance
+	d.First(&att, "user_id = ?", 1)
+	assert.True(t, today.Equal(att.Date), "attendance on %s, want %s", att.Date, today)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var bpjsProgramNames = map[string]string{
+	models.BPJSProgramJHT:       "BPJS JHT",
+	models.BPJSProgramJP:        "BPJS JP",
+	models.BPJSProgramJKK:       "BPJS JKK",
+	models.BPJSProgramJKM:       "BPJS JKM",
+	models.BPJSProgramKesehatan: "BPJS Kesehatan",
+}
+
+type bpjsContributions struct {
+	Employee decimal.Decimal
+	Employer decimal.Decimal
+	// employer premiums (JKK, JKM, Kesehatan) that count as taxable income
+	EmployerTaxable decimal.Decimal
+	// employee pension contributions (JHT, JP) deductible from annual income
+	TaxDeductible decimal.Decimal
+	Breakdown     []dto.BPJSBreakdownItem
+}
+
+// findBPJSRateTable returns the latest BPJS rate table effective on the given date.
+func findBPJSRateTable(tx *gorm.DB, date time.Time) (*models.BPJSRateTable, error) {
+	var table models.BPJSRateTable
+	err := tx.
+		Preload("Rates").
+		Where("effective_from <= ?", date).
+		Order("effective_from DESC").
+		First(&table).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no BPJS rate table effective on %s", errRateTableMissing, date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// computeBPJS computes the BPJS contributions on an employee's monthly salary,
+// every program's amount is rounded as a line item.
+func computeBPJS(tx *gorm.DB, payroll *models.Payroll, salary decimal.Decimal, rounding utils.MoneyRounding) (bpjsContributions, error) {
+	table, err := findBPJSRateTable(tx, payroll.PeriodEnd)
+	if err != nil {
+		return bpjsContributions{}, err
+	}
+
+	result := bpjsContributions{
+		Breakdown: []dto.BPJSBreakdownItem{},
+	}
+	for _, c := range table.Contributions(salary) {
+		employee := rounding.RoundLine(c.EmployeeAmount)
+		employer := rounding.RoundLine(c.EmployerAmount)
+
+		result.Employee = result.Employee.Add(employee)
+		result.Employer = result.Employer.Add(employer)
+		if c.EmployerTaxable {
+			result.EmployerTaxable = result.EmployerTaxable.Add(employer)
+		}
+		if c.EmployeeTaxDeductible {
+			result.TaxDeductible = result.TaxDeductible.Add(employee)
+		}
+
+		result.Breakdown = append(result.Breakdown, dto.BPJSBreakdownItem{
+			Program:        c.Program,
+			Base:           c.Base,
+			EmployeeRate:   c.EmployeeRate,
+			EmployerRate:   c.EmployerRate,
+			EmployeeAmount: employee,
+			EmployerAmount: employer,
+		})
+	}
+	return result, nil
+}
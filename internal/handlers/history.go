@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errHistoryQueryInvalid = errors.New("invalid query")
+
+// historyList is what the records of a history list can be sorted and filtered by.
+type historyList struct {
+	// sorts are sorted by "date" by default
+	sorts map[string]string
+	// statuses are the values of the status filter, none when the records have no status
+	statuses []string
+	// date is the column start and end filter on, "date" when empty
+	date string
+	// scope is a condition every record of the list meets, none when empty
+	scope string
+}
+
+var (
+	attendanceHistory = historyList{
+		sorts: map[string]string{"date": "date", "created_at": "created_at", "hours_worked": "hours_worked"},
+	}
+	overtimeHistory = historyList{
+		sorts:    map[string]string{"date": "date", "created_at": "created_at", "hours_worked": "hours_worked"},
+		statuses: []string{models.OvertimeStatusSubmitted, models.OvertimeStatusApproved, models.OvertimeStatusRejected},
+	}
+	reimbursementHistory = historyList{
+		sorts: map[string]string{"date": "date", "created_at": "created_at", "amount": "amount"},
+		statuses: []string{models.ReimbursementStatusSubmitted, models.ReimbursementStatusApproved,
+			models.ReimbursementStatusRejected, models.ReimbursementStatusPaid},
+	}
+	// payslips are dated by the end of their payroll's period, superseded versions are not listed
+	payslipHistory = historyList{
+		sorts: map[string]string{"date": payslipPeriodEnd, "created_at": "created_at", "net_salary": "net_salary"},
+		date:  payslipPeriodEnd,
+		scope: "superseded_at IS NULL",
+	}
+)
+
+const payslipPeriodEnd = "(SELECT period_end FROM payrolls WHERE payrolls.id = payslips.payroll_id)"
+
+// ListMyAttendances godoc
+// @Summary      List own attendances
+// @Description  Lists the current user's attendances, most recent date first unless sorted otherwise.
+// @Tags         Attendance
+// @Produce      json
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or hours_worked"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.AttendanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/attendances [get]
+func ListMyAttendances(c *gin.Context) {
+	listAttendances(c, true)
+}
+
+// ListAttendances godoc
+// @Summary      List attendances
+// @Description  Lists the attendances of all employees, or of one with user_id, most recent date first unless sorted otherwise.
+// @Tags         Attendance
+// @Produce      json
+// @Param        user_id   query     int     false  "Employee ID"
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or hours_worked"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.AttendanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances [get]
+func ListAttendances(c *gin.Context) {
+	listAttendances(c, false)
+}
+
+func listAttendances(c *gin.Context, self bool) {
+	var attendances []models.Attendance
+	page, total, err := findHistoryPage(c, self, attendanceHistory, db.DB, &attendances)
+	if errors.Is(err, errHistoryQueryInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list attendances"})
+		return
+	}
+
+	resp := make([]dto.AttendanceResponse, 0, len(attendances))
+	for _, attendance := range attendances {
+		resp = append(resp, toAttendanceResponse(attendance))
+	}
+	c.JSON(http.StatusOK, utils.WrapPageResponse(resp, page, total))
+}
+
+// ListMyOvertimes godoc
+// @Summary      List own overtime
+// @Description  Lists the current user's overtime submissions with their review status, most recent date first unless sorted otherwise.
+// @Tags         Attendance
+// @Produce      json
+// @Param        status    query     string  false  "submitted, approved or rejected"
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or hours_worked"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.OvertimeResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/overtimes [get]
+func ListMyOvertimes(c *gin.Context) {
+	listOvertimes(c, true)
+}
+
+// ListOvertimes godoc
+// @Summary      List overtime
+// @Description  Lists the overtime submissions of all employees, or of one with user_id, most recent date first unless sorted otherwise.
+// @Tags         Attendance
+// @Produce      json
+// @Param        user_id   query     int     false  "Employee ID"
+// @Param        status    query     string  false  "submitted, approved or rejected"
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or hours_worked"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.OvertimeResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtimes [get]
+func ListOvertimes(c *gin.Context) {
+	listOvertimes(c, false)
+}
+
+func listOvertimes(c *gin.Context, self bool) {
+	var overtimes []models.Overtime
+	page, total, err := findHistoryPage(c, self, overtimeHistory, db.DB, &overtimes)
+	if errors.Is(err, errHistoryQueryInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list overtime"})
+		return
+	}
+
+	resp := make([]dto.OvertimeResponse, 0, len(overtimes))
+	for _, overtime := range overtimes {
+		resp = append(resp, toOvertimeResponse(overtime))
+	}
+	c.JSON(http.StatusOK, utils.WrapPageResponse(resp, page, total))
+}
+
+// ListMyReimbursements godoc
+// @Summary      List own reimbursements, paginated
+// @Description  Lists the current user's reimbursement claims and their status, most recent date first unless sorted otherwise.
+// @Tags         Reimbursements
+// @Produce      json
+// @Param        status    query     string  false  "submitted, approved, rejected or paid"
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or amount"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.ReimbursementResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/reimbursements [get]
+func ListMyReimbursements(c *gin.Context) {
+	listReimbursements(c, true)
+}
+
+// ListReimbursements godoc
+// @Summary      List reimbursements of all employees
+// @Description  Lists the reimbursement claims of all employees, or of one with user_id, most recent date first unless sorted otherwise.
+// @Tags         Reimbursements
+// @Produce      json
+// @Param        user_id   query     int     false  "Employee ID"
+// @Param        status    query     string  false  "submitted, approved, rejected or paid"
+// @Param        start     query     string  false  "First date, e.g. 2025-06-01"
+// @Param        end       query     string  false  "Last date, e.g. 2025-06-30"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date (default), created_at or amount"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.ReimbursementResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements [get]
+func ListReimbursements(c *gin.Context) {
+	listReimbursements(c, false)
+}
+
+func listReimbursements(c *gin.Context, self bool) {
+	var reimbursements []models.Reimbursement
+	page, total, err := findHistoryPage(c, self, reimbursementHistory, db.DB.Preload("Category").Preload("Attachments"), &reimbursements)
+	if errors.Is(err, errHistoryQueryInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list reimbursements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapPageResponse(toReimbursementResponses(reimbursements), page, total))
+}
+
+// findHistoryPage finds a page of the records of the current user, or of all users (or the one of the
+// user_id parameter) for admins, filtered by the start, end and status parameters.
+func findHistoryPage[T any](c *gin.Context, self bool, list historyList, query *gorm.DB, records *[]T) (utils.Page, int64, error) {
+	page, err := utils.ParsePage(c, list.sorts, "date")
+	if err != nil {
+		return utils.Page{}, 0, fmt.Errorf("%w: %s", errHistoryQueryInvalid, err.Error())
+	}
+
+	filter := db.DB.Model(new(T))
+	if list.scope != "" {
+		filter = filter.Where(list.scope)
+	}
+	date := list.date
+	if date == "" {
+		date = "date"
+	}
+	if self {
+		filter = filter.Where("user_id = ?", c.GetUint("user_id"))
+	} else if v := c.Query("user_id"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil || userID <= 0 {
+			return utils.Page{}, 0, fmt.Errorf("%w: invalid user_id", errHistoryQueryInvalid)
+		}
+		filter = filter.Where("user_id = ?", userID)
+	}
+	if v := c.Query("start"); v != "" {
+		start, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return utils.Page{}, 0, fmt.Errorf("%w: start must be a date such as 2025-06-01", errHistoryQueryInvalid)
+		}
+		filter = filter.Where(date+" >= ?", start)
+	}
+	if v := c.Query("end"); v != "" {
+		end, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return utils.Page{}, 0, fmt.Errorf("%w: end must be a date such as 2025-06-30", errHistoryQueryInvalid)
+		}
+		filter = filter.Where(date+" <= ?", end)
+	}
+	if v := c.Query("status"); v != "" {
+		if !slices.Contains(list.statuses, v) {
+			return utils.Page{}, 0, fmt.Errorf("%w: status must be one of %s", errHistoryQueryInvalid, strings.Join(list.statuses, ", "))
+		}
+		filter = filter.Where("status = ?", v)
+	}
+
+	var total int64
+	if err := filter.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return utils.Page{}, 0, err
+	}
+	if err := query.Where(filter).
+		Order(page.OrderBy()).
+		Limit(page.PerPage).
+		Offset(page.Offset()).
+		Find(records).Error; err != nil {
+		return utils.Page{}, 0, err
+	}
+	return page, total, nil
+}
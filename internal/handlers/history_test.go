@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory_Lists(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	r.GET("/me/attendances", handlers.ListMyAttendances)
+	r.GET("/me/overtimes", handlers.ListMyOvertimes)
+	r.GET("/me/reimbursements", handlers.ListMyReimbursements)
+	r.GET("/attendances", handlers.ListAttendances)
+	r.GET("/overtimes", handlers.ListOvertimes)
+	r.GET("/reimbursements", handlers.ListReimbursements)
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	for _, userID := range []uint{2, 4} {
+		for day := 2; day <= 6; day++ {
+			date := time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC)
+			d.Create(&models.Attendance{
+				UserID:     userID,
+				Date:       date,
+				CheckInAt:  timePtr(date.Add(9 * time.Hour)),
+				CheckOutAt: timePtr(date.Add(17 * time.Hour)),
+			})
+		}
+	}
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), HoursWorked: 1, Status: models.OvertimeStatusApproved})
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), HoursWorked: 2, Status: models.OvertimeStatusSubmitted})
+	d.Create(&models.Overtime{UserID: 4, Date: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), HoursWorked: 3, Status: models.OvertimeStatusSubmitted})
+	d.Create(&models.Reimbursement{UserID: 2, Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromInt(50000), Status: models.ReimbursementStatusApproved})
+	d.Create(&models.Reimbursement{UserID: 2, Date: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromInt(20000), Status: models.ReimbursementStatusSubmitted})
+
+	// only the employee's own, most recent first
+	w := leaveRequest(r, 2, http.MethodGet, "/me/attendances?per_page=2&page=2&user_id=4", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var attendances dto.PageResponse[[]dto.AttendanceResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &attendances))
+	assert.Equal(t, dto.PageMeta{Page: 2, PerPage: 2, Total: 5, TotalPages: 3}, attendances.Meta)
+	if assert.Len(t, attendances.Data, 2) {
+		assert.Equal(t, uint(2), attendances.Data[0].UserID)
+		assert.Equal(t, 4, attendances.Data[0].Date.Day())
+		assert.Equal(t, 3, attendances.Data[1].Date.Day())
+	}
+
+	w = leaveRequest(r, 2, http.MethodGet, "/me/attendances?start=2025-06-05&end=2025-06-30&order=asc", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &attendances))
+	assert.Equal(t, int64(2), attendances.Meta.Total)
+	assert.Equal(t, 5, attendances.Data[0].Date.Day())
+
+	for _, query := range []string{"sort=user_id", "per_page=1000", "start=June", "status=approved"} {
+		w = leaveRequest(r, 2, http.MethodGet, "/me/attendances?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	w = leaveRequest(r, 2, http.MethodGet, "/me/overtimes?status=submitted", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var overtimes dto.PageResponse[[]dto.OvertimeResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &overtimes))
+	if assert.Len(t, overtimes.Data, 1) {
+		assert.Equal(t, 2.0, overtimes.Data[0].HoursWorked)
+	}
+
+	w = leaveRequest(r, 2, http.MethodGet, "/me/reimbursements?sort=amount&order=asc", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var reimbursements dto.PageResponse[[]dto.ReimbursementResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &reimbursements))
+	if assert.Len(t, reimbursements.Data, 2) {
+		assert.Equal(t, "20000", reimbursements.Data[0].Amount.String())
+	}
+
+	// admins list everyone's, or one employee's
+	w = leaveRequest(r, 1, http.MethodGet, "/attendances", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &attendances))
+	assert.Equal(t, int64(10), attendances.Meta.Total)
+
+	w = leaveRequest(r, 1, http.MethodGet, "/overtimes?user_id=4", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &overtimes))
+	if assert.Len(t, overtimes.Data, 1) {
+		assert.Equal(t, uint(4), overtimes.Data[0].UserID)
+	}
+
+	w = leaveRequest(r, 1, http.MethodGet, "/reimbursements?user_id=2&status=approved", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &reimbursements))
+	assert.Equal(t, int64(1), reimbursements.Meta.Total)
+
+	w = leaveRequest(r, 1, http.MethodGet, "/overtimes?user_id=me", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp dto.ErrorResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid query: invalid user_id", errResp.Error)
+}
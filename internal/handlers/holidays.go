@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateHoliday godoc
+// @Summary      Create public holiday
+// @Description  Adds a public holiday. Holidays are not working days, check-ins on them are rejected
+// @Description  and overtime worked on them is paid at the holiday overtime rate.
+// @Tags         Holidays
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.HolidayRequest true "Holiday"
+// @Success      201    {object}  dto.SuccessResponse[dto.HolidayResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /holidays [post]
+func CreateHoliday(c *gin.Context) {
+	var req dto.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date := dateOnly(req.Date)
+	var count int64
+	db.DB.Model(&models.Holiday{}).Where("date = ?", date).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a holiday already exists on this date"})
+		return
+	}
+
+	holiday := models.Holiday{
+		Date:      date,
+		Name:      req.Name,
+		Source:    models.HolidaySourceManual,
+		CreatedBy: c.GetUint("user_id"),
+	}
+	if err := db.DB.Create(&holiday).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create holiday"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toHolidayResponse(holiday)))
+}
+
+// ListHolidays godoc
+// @Summary      List public holidays
+// @Tags         Holidays
+// @Produce      json
+// @Param        year   query     int  false "Only holidays of this year"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.HolidayResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /holidays [get]
+func ListHolidays(c *gin.Context) {
+	query := db.DB.Order("date")
+	if y := c.Query("year"); y != "" {
+		year, err := strconv.Atoi(y)
+		if err != nil || year < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		query = query.Where("date >= ? AND date < ?",
+			time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	var holidays []models.Holiday
+	if err := query.Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list holidays"})
+		return
+	}
+
+	resp := make([]dto.HolidayResponse, 0, len(holidays))
+	for _, holiday := range holidays {
+		resp = append(resp, toHolidayResponse(holiday))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// DeleteHoliday godoc
+// @Summary      Delete public holiday
+// @Description  Removes a holiday. Payslips already generated keep their working-day counts.
+// @Tags         Holidays
+// @Produce      json
+// @Param        id     path      int  true  "Holiday ID"
+// @Success      200    {object}  dto.SuccessResponse[dto.HolidayResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /holidays/{id} [delete]
+func DeleteHoliday(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday id"})
+		return
+	}
+
+	var holiday models.Holiday
+	if err := db.DB.First(&holiday, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&holiday).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete holiday"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toHolidayResponse(holiday)))
+}
+
+// ImportHolidays godoc
+// @Summary      Import public holidays from an iCalendar file
+// @Description  Creates a holiday for every date covered by the events of an .ics file.
+// @Description  Holidays imported before are updated, holidays created manually are kept as they are.
+// @Tags         Holidays
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file   formData  file true  "iCalendar (.ics) file"
+// @Success      200    {object}  dto.SuccessResponse[dto.HolidayImportResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /holidays/import [post]
+func ImportHolidays(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	events, err := utils.ParseICS(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid iCalendar file: " + err.Error()})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	resp := dto.HolidayImportResponse{Holidays: make([]dto.HolidayResponse, 0)}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		seen := map[string]bool{}
+		for _, event := range events {
+			for _, date := range event.Dates() {
+				key := date.Format("2006-01-02")
+				// the first event of a date wins
+				if seen[key] {
+					resp.Skipped++
+					continue
+				}
+				seen[key] = true
+
+				var holiday models.Holiday
+				err := tx.Where("date = ?", date).First(&holiday).Error
+				switch {
+				case errors.Is(err, gorm.ErrRecordNotFound):
+					holiday = models.Holiday{
+						Date:        date,
+						Name:        event.Summary,
+						Source:      models.HolidaySourceICS,
+						ExternalUID: event.UID,
+						CreatedBy:   adminID,
+					}
+					if err := tx.Create(&holiday).Error; err != nil {
+						return err
+					}
+					resp.Created++
+				case err != nil:
+					return err
+				case holiday.Source == models.HolidaySourceManual:
+					resp.Skipped++
+					continue
+				default:
+					holiday.Name = event.Summary
+					holiday.ExternalUID = event.UID
+					holiday.UpdatedBy = adminID
+					if err := tx.Save(&holiday).Error; err != nil {
+						return err
+					}
+					resp.Updated++
+				}
+				resp.Holidays = append(resp.Holidays, toHolidayResponse(holiday))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import holidays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// findHolidayDates returns the holidays from start to end, keyed by date.
+func findHolidayDates(tx *gorm.DB, start, end time.Time) (map[string]bool, error) {
+	var holidays []models.Holiday
+	if err := tx.Where("date BETWEEN ? AND ?", start, end).Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	dates := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		dates[holiday.DateOnlyString()] = true
+	}
+	return dates, nil
+}
+
+// findHolidayOn returns the holiday on the given day, if any.
+func findHolidayOn(tx *gorm.DB, day time.Time) (*models.Holiday, bool) {
+	var holiday models.Holiday
+	if err := tx.Where("date = ?", dateOnly(day)).First(&holiday).Error; err != nil {
+		return nil, false
+	}
+	return &holiday, true
+}
+
+func toHolidayResponse(holiday models.Holiday) dto.HolidayResponse {
+	return dto.HolidayResponse{
+		ID:     holiday.ID,
+		Date:   holiday.DateOnlyString(),
+		Name:   holiday.Name,
+		Source: holiday.Source,
+	}
+}
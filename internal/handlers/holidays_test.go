@@ -0,0 +1,185 @@
+package handlers_test
+
+import (
+	"bytes"
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestRouterForHolidays() *gin.Engine {
+	r := gin.Default()
+	r.POST("/holidays", AuthStubMiddlewareForPayroll(), handlers.CreateHoliday)
+	r.GET("/holidays", AuthStubMiddlewareForPayroll(), handlers.ListHolidays)
+	r.POST("/holidays/import", AuthStubMiddlewareForPayroll(), handlers.ImportHolidays)
+	r.DELETE("/holidays/:id", AuthStubMiddlewareForPayroll(), handlers.DeleteHoliday)
+	r.GET("/payrolls/:year/:month/preview", AuthStubMiddlewareForPayroll(), handlers.PreviewPayroll)
+	return r
+}
+
+func setupTestDBForHolidays() (*gorm.DB, func(), error) {
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	employee := models.User{
+		ID:       2,
+		Username: "employee",
+		Password: "password",
+		RoleID:   2,
+		Salary:   decimal.NewFromInt(1900000),
+	}
+	if err := d.Create(&employee).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return d, cleanup, nil
+}
+
+func uploadICS(r *gin.Engine, ics string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "holidays.ics")
+	part.Write([]byte(ics))
+	writer.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/holidays/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateHoliday_Duplicate(t *testing.T) {
+	r := setupTestRouterForHolidays()
+	_, cleanup, err := setupTestDBForHolidays()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := postJSON(r, http.MethodPost, "/holidays", map[string]any{"date": "2025-06-06T00:00:00Z", "name": "Idul Adha"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON(r, http.MethodPost, "/holidays", map[string]any{"date": "2025-06-06T00:00:00Z", "name": "Idul Adha"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already exists")
+}
+
+func TestImportHolidays(t *testing.T) {
+	r := setupTestRouterForHolidays()
+	_, cleanup, err := setupTestDBForHolidays()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// a manual holiday is kept as it is
+	w := postJSON(r, http.MethodPost, "/holidays", map[string]any{"date": "2025-04-01T00:00:00Z", "name": "Cuti Bersama"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250331\r\nDTEND;VALUE=DATE:20250402\r\nUID:idulfitri\r\nSUMMARY:Idul Fitri\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250329\r\nUID:nyepi\r\nSUMMARY:Nyepi\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	w = uploadICS(r, ics)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.HolidayImportResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resp.Data.Created)
+	assert.Equal(t, 0, resp.Data.Updated)
+	assert.Equal(t, 1, resp.Data.Skipped)
+
+	// importing again updates the imported holidays
+	w = uploadICS(r, ics)
+	assert.Equal(t, http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, resp.Data.Created)
+	assert.Equal(t, 2, resp.Data.Updated)
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/holidays?year=2025", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var list dto.SuccessResponse[[]dto.HolidayResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &list)
+	assert.Nil(t, err)
+	assert.Equal(t, []dto.HolidayResponse{
+		{ID: list.Data[0].ID, Date: "2025-03-29", Name: "Nyepi", Source: models.HolidaySourceICS},
+		{ID: list.Data[1].ID, Date: "2025-03-31", Name: "Idul Fitri", Source: models.HolidaySourceICS},
+		{ID: list.Data[2].ID, Date: "2025-04-01", Name: "Cuti Bersama", Source: models.HolidaySourceManual},
+	}, list.Data)
+
+	w = uploadICS(r, "not a calendar")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHolidays_InPayslipPreview(t *testing.T) {
+	r := setupTestRouterForHolidays()
+	d, cleanup, err := setupTestDBForHolidays()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	for day := 2; day <= 3; day++ {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 17, 0, 0, 0, time.UTC)),
+		})
+	}
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), HoursWorked: 1, Status: models.OvertimeStatusApproved})
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), HoursWorked: 2, Status: models.OvertimeStatusApproved})
+
+	w := postJSON(r, http.MethodPost, "/holidays", map[string]any{"date": "2025-06-06T00:00:00Z", "name": "Idul Adha"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(r, http.MethodPost, "/holidays", map[string]any{"date": "2025-06-27T00:00:00Z", "name": "Tahun Baru Islam"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Len(t, resp.Data.Payslips, 1)
+
+	p := resp.Data.Payslips[0]
+	// 21 weekdays minus two holidays
+	assert.Equal(t, 19, p.ExpectedWorkingDays)
+	// 1,900,000 * 2 / 19
+	assert.Equal(t, "200000", p.BaseSalary.String())
+	// 1 hour at 2x and 2 holiday hours at 3x of 12,500
+	assert.Equal(t, "25000", p.OvertimeRatePerHour.String())
+	assert.Equal(t, "37500", p.HolidayOvertimeRatePerHour.String())
+	assert.Equal(t, float64(2), p.HolidayOvertimeHours)
+	assert.Equal(t, "100000", p.OvertimePay.String())
+}
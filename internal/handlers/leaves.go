@@ -0,0 +1,578 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errLeaveRequestInvalid wraps errors that are reported to the client as a bad request.
+var errLeaveRequestInvalid = errors.New("invalid leave request")
+
+// errLeaveReviewForbidden is reported to the client as forbidden.
+var errLeaveReviewForbidden = errors.New("only the employee's manager or an admin can review this leave request")
+
+// ListLeaveTypes godoc
+// @Summary      List leave types
+// @Tags         Leave
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.LeaveTypeResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-types [get]
+func ListLeaveTypes(c *gin.Context) {
+	var leaveTypes []models.LeaveType
+	if err := db.DB.Order("id").Find(&leaveTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list leave types"})
+		return
+	}
+
+	resp := make([]dto.LeaveTypeResponse, 0, len(leaveTypes))
+	for _, t := range leaveTypes {
+		resp = append(resp, dto.LeaveTypeResponse{
+			ID:                t.ID,
+			Code:              t.Code,
+			Name:              t.Name,
+			Paid:              t.Paid,
+			AnnualEntitlement: t.AnnualEntitlement,
+		})
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// CreateLeaveRequest godoc
+// @Summary      Request leave
+// @Description  Requests leave for the current user from start_date to end_date, inclusive. Only working days count.
+// @Description  Leave with a balance (annual, maternity) must not exceed the days available in the year.
+// @Description  The request is reviewed by the user's manager or an admin.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.CreateLeaveRequest true "Leave request"
+// @Success      201    {object}  dto.SuccessResponse[dto.LeaveRequestResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves [post]
+func CreateLeaveRequest(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req dto.CreateLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start, end := dateOnly(req.StartDate), dateOnly(req.EndDate)
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+	if start.Year() != end.Year() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "leave must not span two years, split it into one request per year"})
+		return
+	}
+
+	var leaveType models.LeaveType
+	if err := db.DB.Where("code = ?", req.LeaveType).First(&leaveType).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown leave type"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	leave := models.LeaveRequest{
+		UserID:      userID,
+		LeaveTypeID: leaveType.ID,
+		LeaveType:   leaveType,
+		StartDate:   start,
+		EndDate:     end,
+		Reason:      req.Reason,
+		Status:      models.LeaveStatusPending,
+		CreatedBy:   userID,
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// concurrent requests of the employee wait here, so the overlap and balance checks see each other
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		holidays, err := findHolidayDates(tx, start, end)
+		if err != nil {
+			return err
+		}
+		schedule, err := findSchedule(tx, userID)
+		if err != nil {
+			return err
+		}
+		leave.Days = schedule.WorkingDays(start, end, holidays)
+		if leave.Days == 0 {
+			return fmt.Errorf("%w: the leave covers no working days", errLeaveRequestInvalid)
+		}
+
+		var overlapping int64
+		if err := tx.Model(&models.LeaveRequest{}).
+			Where("user_id = ? AND status IN ?", userID, []string{models.LeaveStatusPending, models.LeaveStatusApproved}).
+			Where("start_date <= ? AND end_date >= ?", end, start).
+			Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return fmt.Errorf("%w: overlaps another leave request", errLeaveRequestInvalid)
+		}
+
+		if err := checkPayrollNotLocked(tx, start, end); err != nil {
+			return err
+		}
+
+		if leaveType.HasBalance() {
+			balance, err := findLeaveBalance(tx, user, leaveType, start.Year())
+			if err != nil {
+				return err
+			}
+			if leave.Days > balance.Available {
+				return fmt.Errorf("%w: insufficient leave balance, %d days available", errLeaveRequestInvalid, balance.Available)
+			}
+		}
+
+		return tx.Create(&leave).Error
+	})
+	if errors.Is(err, errLeaveRequestInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to request leave"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toLeaveRequestResponse(leave)))
+}
+
+// ListLeaveRequests godoc
+// @Summary      List own leave requests
+// @Description  Lists the current user's leave requests, most recent first.
+// @Tags         Leave
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.LeaveRequestResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves [get]
+func ListLeaveRequests(c *gin.Context) {
+	var leaves []models.LeaveRequest
+	if err := db.DB.Preload("LeaveType").
+		Where("user_id = ?", c.GetUint("user_id")).
+		Order("start_date DESC").
+		Find(&leaves).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list leave requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toLeaveRequestResponses(leaves)))
+}
+
+// ListPendingLeaveRequests godoc
+// @Summary      List leave requests to review
+// @Description  Lists the pending leave requests the current user can review: those of their reports, or all for admins.
+// @Tags         Leave
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.LeaveRequestResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves/pending [get]
+func ListPendingLeaveRequests(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	query := db.DB.Preload("LeaveType").
+		Where("status = ?", models.LeaveStatusPending).
+		Where("user_id <> ?", userID).
+		Order("start_date")
+	if c.GetString("role") != "Admin" {
+		query = query.Where("user_id IN (?)", db.DB.Model(&models.User{}).Select("id").Where("manager_id = ?", userID))
+	}
+
+	var leaves []models.LeaveRequest
+	if err := query.Find(&leaves).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list leave requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toLeaveRequestResponses(leaves)))
+}
+
+// ApproveLeaveRequest godoc
+// @Summary      Approve leave request
+// @Description  Approves a pending leave request. Only the requester's manager or an admin can approve,
+// @Description  and not once the payroll covering the leave is processed.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Leave request ID"
+// @Param        request body     dto.ReviewLeaveRequest false "Review note"
+// @Success      200    {object}  dto.SuccessResponse[dto.LeaveRequestResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves/{id}/approve [post]
+func ApproveLeaveRequest(c *gin.Context) {
+	reviewLeaveRequest(c, models.LeaveStatusApproved)
+}
+
+// RejectLeaveRequest godoc
+// @Summary      Reject leave request
+// @Description  Rejects a pending leave request. Only the requester's manager or an admin can reject.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Leave request ID"
+// @Param        request body     dto.ReviewLeaveRequest false "Review note"
+// @Success      200    {object}  dto.SuccessResponse[dto.LeaveRequestResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves/{id}/reject [post]
+func RejectLeaveRequest(c *gin.Context) {
+	reviewLeaveRequest(c, models.LeaveStatusRejected)
+}
+
+func reviewLeaveRequest(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid leave request id"})
+		return
+	}
+
+	var req dto.ReviewLeaveRequest
+	// the note is optional, so is the body
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	reviewerID := c.GetUint("user_id")
+	var leave models.LeaveRequest
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// a concurrent review or cancellation waits here, and the status is checked after it
+		if err := lockLeaveRequest(tx, &leave, id); err != nil {
+			return err
+		}
+		if !canReview(c, leave.User) {
+			return errLeaveReviewForbidden
+		}
+		if leave.Status != models.LeaveStatusPending {
+			return fmt.Errorf("%w: leave request is already %s", errLeaveRequestInvalid, leave.Status)
+		}
+		if status == models.LeaveStatusApproved {
+			if err := checkPayrollNotLocked(tx, leave.StartDate, leave.EndDate); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		leave.Status = status
+		leave.ReviewedBy = &reviewerID
+		leave.ReviewedAt = &now
+		leave.ReviewNote = req.Note
+		leave.UpdatedBy = reviewerID
+		return tx.Omit("User", "LeaveType").Save(&leave).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return
+	}
+	if errors.Is(err, errLeaveReviewForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, errLeaveRequestInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review leave request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toLeaveRequestResponse(leave)))
+}
+
+// CancelLeaveRequest godoc
+// @Summary      Cancel leave request
+// @Description  Cancels one of the current user's pending or approved leave requests, returning its days to the balance.
+// @Description  Approved leave can't be cancelled once the payroll covering it is processed.
+// @Tags         Leave
+// @Produce      json
+// @Param        id     path      int  true  "Leave request ID"
+// @Success      200    {object}  dto.SuccessResponse[dto.LeaveRequestResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves/{id}/cancel [post]
+func CancelLeaveRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid leave request id"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var leave models.LeaveRequest
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// a concurrent review waits here, and the status is checked after it
+		if err := lockLeaveRequest(tx, &leave, id); err != nil {
+			return err
+		}
+		if leave.UserID != userID {
+			return gorm.ErrRecordNotFound
+		}
+
+		switch leave.Status {
+		case models.LeaveStatusPending:
+		case models.LeaveStatusApproved:
+			if err := checkPayrollNotLocked(tx, leave.StartDate, leave.EndDate); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: leave request is already %s", errLeaveRequestInvalid, leave.Status)
+		}
+
+		leave.Status = models.LeaveStatusCancelled
+		leave.UpdatedBy = userID
+		return tx.Omit("User", "LeaveType").Save(&leave).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return
+	}
+	if errors.Is(err, errLeaveRequestInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel leave request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toLeaveRequestResponse(leave)))
+}
+
+// GetLeaveBalances godoc
+// @Summary      Get own leave balances
+// @Description  Lists the current user's balance of every leave type limited by one.
+// @Tags         Leave
+// @Produce      json
+// @Param        year   query     int  false "Year, defaults to the current year"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.LeaveBalanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaves/balances [get]
+func GetLeaveBalances(c *gin.Context) {
+	respondLeaveBalances(c, c.GetUint("user_id"))
+}
+
+// ListUserLeaveBalances godoc
+// @Summary      Get employee leave balances
+// @Description  Lists the employee's balance of every leave type limited by one.
+// @Tags         Users
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        year   query     int  false "Year, defaults to the current year"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.LeaveBalanceResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/leave-balances [get]
+func ListUserLeaveBalances(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	respondLeaveBalances(c, uint(id))
+}
+
+func respondLeaveBalances(c *gin.Context, userID uint) {
+	year := time.Now().Year()
+	if y := c.Query("year"); y != "" {
+		parsed, err := strconv.Atoi(y)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		year = parsed
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var leaveTypes []models.LeaveType
+	if err := db.DB.Where("annual_entitlement > 0").Order("id").Find(&leaveTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get leave balances"})
+		return
+	}
+
+	resp := make([]dto.LeaveBalanceResponse, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		balance, err := findLeaveBalance(db.DB, user, leaveType, year)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get leave balances"})
+			return
+		}
+		resp = append(resp, balance)
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// lockLeaveRequest loads the leave request with its employee and leave type, locking it until the
+// transaction ends.
+func lockLeaveRequest(tx *gorm.DB, leave *models.LeaveRequest, id int) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(leave, id).Error; err != nil {
+		return err
+	}
+	if err := tx.First(&leave.User, leave.UserID).Error; err != nil {
+		return err
+	}
+	return tx.First(&leave.LeaveType, leave.LeaveTypeID).Error
+}
+
+// findLeaveBalance returns the user's balance of a leave type for a year,
+// accruing the year's entitlement on first use.
+func findLeaveBalance(tx *gorm.DB, user models.User, leaveType models.LeaveType, year int) (dto.LeaveBalanceResponse, error) {
+	balance := models.LeaveBalance{
+		UserID:      user.ID,
+		LeaveTypeID: leaveType.ID,
+		Year:        year,
+		Entitled:    leaveType.Entitlement(year, user.HireDate),
+		CreatedBy:   user.ID,
+	}
+	if err := tx.
+		Where(models.LeaveBalance{UserID: user.ID, LeaveTypeID: leaveType.ID, Year: year}).
+		FirstOrCreate(&balance).Error; err != nil {
+		return dto.LeaveBalanceResponse{}, err
+	}
+
+	var taken []struct {
+		Status string
+		Days   int
+	}
+	if err := tx.Model(&models.LeaveRequest{}).
+		Select("status, SUM(days) AS days").
+		Where("user_id = ? AND leave_type_id = ?", user.ID, leaveType.ID).
+		Where("start_date >= ? AND start_date < ?", time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)).
+		Where("status IN ?", []string{models.LeaveStatusPending, models.LeaveStatusApproved}).
+		Group("status").
+		Scan(&taken).Error; err != nil {
+		return dto.LeaveBalanceResponse{}, err
+	}
+
+	resp := dto.LeaveBalanceResponse{
+		LeaveType: leaveType.Code,
+		Year:      year,
+		Entitled:  balance.Entitled,
+	}
+	for _, t := range taken {
+		if t.Status == models.LeaveStatusApproved {
+			resp.Used = t.Days
+		} else {
+			resp.Pending = t.Days
+		}
+	}
+	// pending requests are reserved so they can't be overbooked
+	resp.Available = resp.Entitled - resp.Used - resp.Pending
+	return resp, nil
+}
+
+// findApprovedLeaves returns the user's approved leave overlapping start to end.
+func findApprovedLeaves(tx *gorm.DB, userID uint, start, end time.Time) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	if err := tx.Preload("LeaveType").
+		Where("user_id = ? AND status = ?", userID, models.LeaveStatusApproved).
+		Where("start_date <= ? AND end_date >= ?", end, start).
+		Order("start_date").
+		Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// leaveDays lists the working days from start to end covered by leave. Days the employee
+// attended anyway are not leave.
+func leaveDays(leaves []models.LeaveRequest, start, end time.Time, schedule models.Schedule, holidays, attended map[string]bool) []dto.LeaveBreakdownItem {
+	days := make([]dto.LeaveBreakdownItem, 0)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		if !schedule.IsWorkingDay(d, holidays) || attended[date] {
+			continue
+		}
+		for _, leave := range leaves {
+			if leave.Covers(d) {
+				days = append(days, dto.LeaveBreakdownItem{Date: date, LeaveType: leave.LeaveType.Code, Paid: leave.LeaveType.Paid})
+				break
+			}
+		}
+	}
+	return days
+}
+
+func toLeaveRequestResponse(leave models.LeaveRequest) dto.LeaveRequestResponse {
+	return dto.LeaveRequestResponse{
+		ID:         leave.ID,
+		UserID:     leave.UserID,
+		LeaveType:  leave.LeaveType.Code,
+		StartDate:  leave.StartDate.Format("2006-01-02"),
+		EndDate:    leave.EndDate.Format("2006-01-02"),
+		Days:       leave.Days,
+		Reason:     leave.Reason,
+		Status:     leave.Status,
+		ReviewedBy: leave.ReviewedBy,
+		ReviewedAt: leave.ReviewedAt,
+		ReviewNote: leave.ReviewNote,
+	}
+}
+
+func toLeaveRequestResponses(leaves []models.LeaveRequest) []dto.LeaveRequestResponse {
+	resp := make([]dto.LeaveRequestResponse, 0, len(leaves))
+	for _, leave := range leaves {
+		resp = append(resp, toLeaveRequestResponse(leave))
+	}
+	return resp
+}
@@ -0,0 +1,269 @@
+package handlers_test
+
+import (
+	"bytes"
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// AuthStubMiddlewareForLeaves authenticates as the user in the X-User-ID header with the role in X-Role.
+func AuthStubMiddlewareForLeaves() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.GetHeader("X-User-ID"))
+		c.Set("user_id", uint(id))
+		role := c.GetHeader("X-Role")
+		if role == "" {
+			role = "Employee"
+		}
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+func setupTestRouterForLeaves() *gin.Engine {
+	r := gin.Default()
+	r.Use(AuthStubMiddlewareForLeaves())
+	r.POST("/leaves", handlers.CreateLeaveRequest)
+	r.GET("/leaves", handlers.ListLeaveRequests)
+	r.GET("/leaves/balances", handlers.GetLeaveBalances)
+	r.GET("/leaves/pending", handlers.ListPendingLeaveRequests)
+	r.POST("/leaves/:id/approve", handlers.ApproveLeaveRequest)
+	r.POST("/leaves/:id/reject", handlers.RejectLeaveRequest)
+	r.POST("/leaves/:id/cancel", handlers.CancelLeaveRequest)
+	r.GET("/payrolls/:year/:month/preview", handlers.PreviewPayroll)
+	return r
+}
+
+func setupTestDBForLeaves() (*gorm.DB, func(), error) {
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	managerID := uint(3)
+	users := []models.User{
+		{ID: 2, Username: "employee", Password: "password", RoleID: 2, Salary: decimal.NewFromInt(2100000), ManagerID: &managerID},
+		{ID: 3, Username: "manager", Password: "password", RoleID: 2, Salary: decimal.NewFromInt(4200000)},
+		{ID: 4, Username: "colleague", Password: "password", RoleID: 2, Salary: decimal.NewFromInt(2100000)},
+	}
+	if err := d.Create(&users).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return d, cleanup, nil
+}
+
+func leaveRequest(r *gin.Engine, userID uint, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", strconv.Itoa(int(userID)))
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLeave_ApprovalAndPayroll(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	d.Create(&models.Attendance{
+		UserID:     2,
+		Date:       time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+		CheckInAt:  timePtr(time.Date(2025, 6, 5, 9, 0, 0, 0, time.UTC)),
+		CheckOutAt: timePtr(time.Date(2025, 6, 5, 17, 0, 0, 0, time.UTC)),
+	})
+
+	// Tuesday to Thursday
+	w := leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "annual", "start_date": "2025-06-03T00:00:00Z", "end_date": "2025-06-05T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var annual dto.SuccessResponse[dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &annual))
+	assert.Equal(t, 3, annual.Data.Days)
+	assert.Equal(t, models.LeaveStatusPending, annual.Data.Status)
+
+	// Monday to Tuesday of the next week
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "unpaid", "start_date": "2025-06-09T00:00:00Z", "end_date": "2025-06-10T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var unpaid dto.SuccessResponse[dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &unpaid))
+
+	// pending days are reserved
+	w = leaveRequest(r, 2, http.MethodGet, "/leaves/balances?year=2025", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var balances dto.SuccessResponse[[]dto.LeaveBalanceResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &balances))
+	assert.Contains(t, balances.Data, dto.LeaveBalanceResponse{LeaveType: "annual", Year: 2025, Entitled: 12, Pending: 3, Available: 9})
+
+	// only the manager sees and reviews the requests
+	w = leaveRequest(r, 4, http.MethodGet, "/leaves/pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"success","data":[]}`, w.Body.String())
+
+	w = leaveRequest(r, 4, http.MethodPost, "/leaves/"+strconv.Itoa(int(annual.Data.ID))+"/approve", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves/"+strconv.Itoa(int(annual.Data.ID))+"/approve", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = leaveRequest(r, 3, http.MethodGet, "/leaves/pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var pending dto.SuccessResponse[[]dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	assert.Len(t, pending.Data, 2)
+
+	for _, id := range []uint{annual.Data.ID, unpaid.Data.ID} {
+		w = leaveRequest(r, 3, http.MethodPost, "/leaves/"+strconv.Itoa(int(id))+"/approve", map[string]any{"note": "enjoy"})
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	w = leaveRequest(r, 3, http.MethodPost, "/leaves/"+strconv.Itoa(int(annual.Data.ID))+"/reject", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = leaveRequest(r, 2, http.MethodGet, "/leaves/balances?year=2025", nil)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &balances))
+	assert.Contains(t, balances.Data, dto.LeaveBalanceResponse{LeaveType: "annual", Year: 2025, Entitled: 12, Used: 3, Available: 9})
+
+	w = leaveRequest(r, 999, http.MethodGet, "/payrolls/2025/6/preview", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var p dto.EmployeePayslipPreview
+	for _, payslip := range resp.Data.Payslips {
+		if payslip.UserID == 2 {
+			p = payslip
+		}
+	}
+	assert.Equal(t, 1, p.DaysAttended)
+	// the attended Thursday is not leave
+	assert.Equal(t, 2, p.PaidLeaveDays)
+	assert.Equal(t, 2, p.UnpaidLeaveDays)
+	// 2,100,000 * (1 attended + 2 paid + 2 unpaid) / 21
+	assert.Equal(t, "500000", p.BaseSalary.String())
+	assert.Equal(t, "200000", p.OtherDeductions.String())
+
+	amounts := map[string]string{}
+	for _, line := range p.Lines {
+		amounts[line.Code] = line.Amount.String()
+	}
+	assert.Equal(t, "200000", amounts["unpaid_leave"])
+}
+
+func TestLeave_CancelWhileApproving(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "annual", "start_date": "2025-06-03T00:00:00Z", "end_date": "2025-06-05T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var leave dto.SuccessResponse[dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &leave))
+
+	// the employee cancels as the manager approves: either the approval comes first and is cancelled,
+	// or it finds the request cancelled, the cancellation is never overwritten
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		codes[0] = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/leaves/%d/approve", leave.Data.ID), nil).Code
+	}()
+	go func() {
+		defer wg.Done()
+		codes[1] = leaveRequest(r, 2, http.MethodPost, fmt.Sprintf("/leaves/%d/cancel", leave.Data.ID), nil).Code
+	}()
+	wg.Wait()
+
+	assert.Contains(t, []int{http.StatusOK, http.StatusBadRequest}, codes[0])
+	assert.Equal(t, http.StatusOK, codes[1])
+	var current models.LeaveRequest
+	d.First(&current, leave.Data.ID)
+	assert.Equal(t, models.LeaveStatusCancelled, current.Status)
+}
+
+func TestCreateLeaveRequest_InsufficientBalance(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	_, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// 13 working days
+	w := leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "annual", "start_date": "2025-06-02T00:00:00Z", "end_date": "2025-06-18T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "12 days available")
+
+	// sick leave has no balance
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "sick", "start_date": "2025-06-02T00:00:00Z", "end_date": "2025-06-18T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "annual", "start_date": "2025-06-18T00:00:00Z", "end_date": "2025-06-19T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "overlaps")
+}
+
+func TestCreateLeaveRequest_ProcessedPayroll(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		Status:      models.PayrollStatusProcessed,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+
+	w := leaveRequest(r, 2, http.MethodPost, "/leaves", map[string]any{
+		"leave_type": "annual", "start_date": "2025-06-30T00:00:00Z", "end_date": "2025-07-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
+}
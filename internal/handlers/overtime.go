@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"dealls-case-study/internal/db"
@@ -10,12 +14,20 @@ import (
 	"dealls-case-study/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var errOvertimeInvalid = errors.New("invalid overtime")
+
 // SubmitOvertime godoc
 // @Summary      Submit Overtime for current user
-// @Description  Allows an employee to submit overtime for the current day.
-// @Description  Overtime must be submitted after check-out, and cannot exceed 3 hours per day.
+// @Description  Allows an employee to submit overtime for a day, today in the employee's timezone unless a date is given.
+// @Description  Past days can be submitted up to OVERTIME_SUBMISSION_DAYS back (7 by default), never in a processed payroll period.
+// @Description  On a working day overtime must be submitted after check-out. On a rest day or a public holiday no attendance is needed,
+// @Description  the hours are paid at the rest day or holiday overtime rate.
+// @Description  Overtime cannot exceed 3 hours per day, nor OVERTIME_MONTHLY_CAP hours (40 by default) per calendar month.
+// @Description  Overtime is only paid once approved by the employee's manager or an admin.
 // @Tags         Attendance
 // @Accept       json
 // @Produce      json
@@ -35,41 +47,275 @@ func SubmitOvertime(c *gin.Context) {
 		return
 	}
 
-	today := time.Now()
+	var overtime models.Overtime
+	var dayType string
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := userLocation(tx, userID)
+		if err != nil {
+			return err
+		}
+		today := utils.CalendarDate(time.Now(), loc)
+		date := today
+		if req.Date != nil {
+			date = dateOnly(*req.Date)
+		}
+
+		limits := utils.OvertimeLimitsFromEnv()
+		earliest := today.AddDate(0, 0, -limits.SubmissionDays)
+		if date.After(today) || date.Before(earliest) {
+			return fmt.Errorf("%w: overtime can only be submitted from %s to %s", errOvertimeInvalid,
+				earliest.Format("2006-01-02"), today.Format("2006-01-02"))
+		}
+		if err := checkPayrollNotLocked(tx, date, date); err != nil {
+			return err
+		}
+
+		schedule, err := findSchedule(tx, userID)
+		if err != nil {
+			return err
+		}
+		holidays, err := findHolidayDates(tx, date, date)
+		if err != nil {
+			return err
+		}
+		// there is no check-in on a rest day or a public holiday, all work on it is overtime
+		dayType = overtimeDayType(date, schedule, holidays)
+		if dayType == models.OvertimeDayWeekday {
+			var attendance models.Attendance
+			if err := tx.
+				Where("user_id = ? AND date = ?", userID, date).
+				First(&attendance).Error; err != nil {
+				return fmt.Errorf("%w: attendance record not found", errOvertimeInvalid)
+			}
+			if attendance.CheckOutAt == nil {
+				return fmt.Errorf("%w: you must check out before submitting overtime", errOvertimeInvalid)
+			}
+		}
+
+		// concurrent submissions of the employee wait here, so the duplicate and cap checks see each other
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		limits = limits.WithMonthlyCap(user.OvertimeMonthlyCap)
+		var existing int64
+		if err := tx.Model(&models.Overtime{}).
+			Where("user_id = ? AND date = ?", userID, date).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return fmt.Errorf("%w: overtime already submitted for %s", errOvertimeInvalid, date.Format("2006-01-02"))
+		}
+
+		// rejected overtime does not count towards the cap
+		if limits.MonthlyCap > 0 {
+			monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+			var submitted float64
+			if err := tx.Model(&models.Overtime{}).
+				Select("COALESCE(SUM(hours_worked), 0)").
+				Where("user_id = ? AND date BETWEEN ? AND ?", userID, monthStart, monthStart.AddDate(0, 1, -1)).
+				Where("status <> ?", models.OvertimeStatusRejected).
+				Scan(&submitted).Error; err != nil {
+				return err
+			}
+			if submitted+req.HoursWorked > limits.MonthlyCap {
+				return fmt.Errorf("%w: overtime is capped at %g hours a month, %g hours are already submitted for %s",
+					errOvertimeInvalid, limits.MonthlyCap, submitted, date.Format("January 2006"))
+			}
+		}
 
-	var attendance models.Attendance
-	if err := db.DB.
-		Where("user_id = ? AND DATE(date) = ?", userID, today).
-		First(&attendance).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "attendance record not found"})
+		overtime = models.Overtime{
+			UserID:      userID,
+			Date:        date,
+			HoursWorked: req.HoursWorked,
+			Status:      models.OvertimeStatusSubmitted,
+			CreatedBy:   userID,
+		}
+		if err := tx.Create(&overtime).Error; errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: overtime already submitted for %s", errOvertimeInvalid, date.Format("2006-01-02"))
+		} else if err != nil {
+			return err
+		}
+		return nil
+	})
+	if errors.Is(err, errOvertimeInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if attendance.CheckOutAt == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "you must check out before submitting overtime"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit overtime"})
 		return
 	}
 
-	var existing models.Overtime
-	if err := db.DB.
-		Where("user_id = ? AND DATE(date) = ?", userID, today).
-		First(&existing).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "overtime already submitted for today"})
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.SubmitOvertimeResponse{
+		ID:          overtime.ID,
+		Date:        overtime.DateOnlyString(),
+		DayType:     dayType,
+		HoursWorked: overtime.HoursWorked,
+		Status:      overtime.Status,
+	}))
+}
+
+// ListPendingOvertimes godoc
+// @Summary      List overtime to review
+// @Description  Lists the submitted overtime the current user can review: that of their reports, or all for admins.
+// @Tags         Attendance
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.OvertimeResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtimes/pending [get]
+func ListPendingOvertimes(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	query := db.DB.
+		Where("status = ?", models.OvertimeStatusSubmitted).
+		Where("user_id <> ?", userID).
+		Order("date")
+	if c.GetString("role") != "Admin" {
+		query = query.Where("user_id IN (?)", db.DB.Model(&models.User{}).Select("id").Where("manager_id = ?", userID))
+	}
+
+	var overtimes []models.Overtime
+	if err := query.Find(&overtimes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list overtime"})
 		return
 	}
 
-	overtime := models.Overtime{
-		UserID:      userID,
-		Date:        today,
-		HoursWorked: req.HoursWorked,
-		CreatedBy:   userID,
+	resp := make([]dto.OvertimeResponse, 0, len(overtimes))
+	for _, overtime := range overtimes {
+		resp = append(resp, toOvertimeResponse(overtime))
 	}
-	if err := db.DB.Create(&overtime).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit overtime"})
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// ApproveOvertime godoc
+// @Summary      Approve overtime
+// @Description  Approves submitted overtime so the next payroll pays it. Only the employee's manager or an admin
+// @Description  can approve, and not once the payroll covering the overtime is processed.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Overtime ID"
+// @Param        request body     dto.ReviewOvertimeRequest false "Reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.OvertimeResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtimes/{id}/approve [post]
+func ApproveOvertime(c *gin.Context) {
+	reviewOvertime(c, models.OvertimeStatusApproved)
+}
+
+// RejectOvertime godoc
+// @Summary      Reject overtime
+// @Description  Rejects submitted overtime, it is never paid. A reason is required.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Overtime ID"
+// @Param        request body     dto.ReviewOvertimeRequest true "Reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.OvertimeResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtimes/{id}/reject [post]
+func RejectOvertime(c *gin.Context) {
+	reviewOvertime(c, models.OvertimeStatusRejected)
+}
+
+func reviewOvertime(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid overtime id"})
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.SubmitOvertimeResponse{
-		ID:          overtime.ID,
-		HoursWorked: overtime.HoursWorked,
-	}))
+	var req dto.ReviewOvertimeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if status == models.OvertimeStatusRejected && strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required to reject overtime"})
+		return
+	}
+
+	var overtime models.Overtime
+	if err := db.DB.Preload("User").First(&overtime, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
+		return
+	}
+
+	reviewerID := c.GetUint("user_id")
+	if !canReview(c, overtime.User) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the employee's manager or an admin can review this overtime"})
+		return
+	}
+	if overtime.Status != models.OvertimeStatusSubmitted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "overtime is already " + overtime.Status})
+		return
+	}
+	if status == models.OvertimeStatusApproved {
+		if err := checkPayrollNotLocked(db.DB, overtime.Date, overtime.Date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// only a submission still submitted is reviewed, a concurrent review of it wins
+	now := time.Now()
+	result := db.DB.Model(&models.Overtime{}).
+		Where("id = ? AND status = ?", overtime.ID, models.OvertimeStatusSubmitted).
+		Updates(map[string]any{
+			"status":        status,
+			"reviewed_by":   reviewerID,
+			"reviewed_at":   now,
+			"review_reason": req.Reason,
+			"updated_by":    reviewerID,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review overtime"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		var current models.Overtime
+		if err := db.DB.Select("status").First(&current, overtime.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review overtime"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "overtime is already " + current.Status})
+		return
+	}
+	overtime.Status = status
+	overtime.ReviewedBy = &reviewerID
+	overtime.ReviewedAt = &now
+	overtime.ReviewReason = req.Reason
+	overtime.UpdatedBy = reviewerID
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toOvertimeResponse(overtime)))
+}
+
+func toOvertimeResponse(overtime models.Overtime) dto.OvertimeResponse {
+	return dto.OvertimeResponse{
+		ID:           overtime.ID,
+		UserID:       overtime.UserID,
+		Date:         overtime.DateOnlyString(),
+		HoursWorked:  overtime.HoursWorked,
+		Status:       overtime.Status,
+		ReviewedBy:   overtime.ReviewedBy,
+		ReviewedAt:   overtime.ReviewedAt,
+		ReviewReason: overtime.ReviewReason,
+	}
 }
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// errOvertimeRulesInvalid wraps errors that are reported to the client as a bad request.
+var errOvertimeRulesInvalid = errors.New("invalid overtime rules")
+
+var overtimeDayTypes = []string{models.OvertimeDayWeekday, models.OvertimeDayRestDay, models.OvertimeDayHoliday}
+
+// ListOvertimeRuleSets godoc
+// @Summary      List overtime rule sets
+// @Description  Lists every version of the overtime multipliers, latest effective first.
+// @Tags         Overtime Rules
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.OvertimeRuleSetResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtime-rules [get]
+func ListOvertimeRuleSets(c *gin.Context) {
+	var sets []models.OvertimeRuleSet
+	if err := db.DB.Preload("Rules").Order("effective_from DESC").Find(&sets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list overtime rules"})
+		return
+	}
+
+	resp := make([]dto.OvertimeRuleSetResponse, 0, len(sets))
+	for _, set := range sets {
+		resp = append(resp, toOvertimeRuleSetResponse(set))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// CreateOvertimeRuleSet godoc
+// @Summary      Create overtime rule set
+// @Description  Adds a version of the overtime multipliers, used for overtime dated from effective_from on.
+// @Description  Every day type (weekday, rest_day, holiday) needs tiers starting at hour 0, each tier starting where
+// @Description  the previous one ends and only the last one without to_hour. It cannot take effect within a processed payroll.
+// @Tags         Overtime Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.OvertimeRuleSetRequest true "Overtime rule set"
+// @Success      201    {object}  dto.SuccessResponse[dto.OvertimeRuleSetResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /overtime-rules [post]
+func CreateOvertimeRuleSet(c *gin.Context) {
+	var req dto.OvertimeRuleSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	set := models.OvertimeRuleSet{
+		Version:       req.Version,
+		EffectiveFrom: dateOnly(req.EffectiveFrom),
+		CreatedBy:     c.GetUint("user_id"),
+	}
+	for _, r := range req.Rules {
+		set.Rules = append(set.Rules, models.OvertimeRule{
+			DayType:    r.DayType,
+			FromHour:   r.FromHour,
+			ToHour:     r.ToHour,
+			Multiplier: r.Multiplier,
+		})
+	}
+	if err := validateOvertimeRules(set.Rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.OvertimeRuleSet{}).Where("version = ?", set.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: version %s already exists", errOvertimeRulesInvalid, set.Version)
+		}
+
+		// the set applies to every later payroll as well
+		if err := checkPayrollNotLocked(tx, set.EffectiveFrom, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)); err != nil {
+			return err
+		}
+
+		return tx.Create(&set).Error
+	})
+	if errors.Is(err, errOvertimeRulesInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create overtime rules"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toOvertimeRuleSetResponse(set)))
+}
+
+// validateOvertimeRules checks that the tiers of every day type cover all hours without gaps.
+func validateOvertimeRules(rules []models.OvertimeRule) error {
+	for _, dayType := range overtimeDayTypes {
+		var tiers []models.OvertimeRule
+		for _, r := range rules {
+			if r.DayType == dayType {
+				tiers = append(tiers, r)
+			}
+		}
+		if len(tiers) == 0 {
+			return fmt.Errorf("%w: no rule for %s", errOvertimeRulesInvalid, dayType)
+		}
+		sort.Slice(tiers, func(i, j int) bool { return tiers[i].FromHour.LessThan(tiers[j].FromHour) })
+
+		next := decimal.Zero
+		for i, r := range tiers {
+			if !r.Multiplier.IsPositive() {
+				return fmt.Errorf("%w: multipliers must be greater than 0", errOvertimeRulesInvalid)
+			}
+			if !r.FromHour.Equal(next) {
+				return fmt.Errorf("%w: the %s tier from hour %s should start at hour %s", errOvertimeRulesInvalid, dayType, r.FromHour, next)
+			}
+			last := i == len(tiers)-1
+			if last != !r.ToHour.Valid {
+				return fmt.Errorf("%w: only the last %s tier has no to_hour", errOvertimeRulesInvalid, dayType)
+			}
+			if !last {
+				if !r.ToHour.Decimal.GreaterThan(r.FromHour) {
+					return fmt.Errorf("%w: to_hour must be after from_hour", errOvertimeRulesInvalid)
+				}
+				next = r.ToHour.Decimal
+			}
+		}
+	}
+	return nil
+}
+
+// findOvertimeRuleSets returns the rule sets that can apply to overtime up to date, latest
+// effective first.
+func findOvertimeRuleSets(tx *gorm.DB, date time.Time) ([]models.OvertimeRuleSet, error) {
+	var sets []models.OvertimeRuleSet
+	if err := tx.Preload("Rules").
+		Where("effective_from <= ?", date).
+		Order("effective_from DESC").
+		Find(&sets).Error; err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// overtimeRuleSetOn picks the set effective on a date from sets ordered latest first.
+func overtimeRuleSetOn(sets []models.OvertimeRuleSet, date time.Time) (*models.OvertimeRuleSet, error) {
+	for i := range sets {
+		if !sets[i].EffectiveFrom.After(date) {
+			return &sets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no overtime rules effective on %s", date.Format("2006-01-02"))
+}
+
+// overtimeDayType classifies the day overtime was worked on, a rest day is one without a shift.
+func overtimeDayType(date time.Time, schedule models.Schedule, holidays map[string]bool) string {
+	switch {
+	case holidays[date.Format("2006-01-02")]:
+		return models.OvertimeDayHoliday
+	case schedule.ShiftOn(date) == nil:
+		return models.OvertimeDayRestDay
+	default:
+		return models.OvertimeDayWeekday
+	}
+}
+
+func toOvertimeRuleSetResponse(set models.OvertimeRuleSet) dto.OvertimeRuleSetResponse {
+	rules := make([]dto.OvertimeRuleResponse, 0, len(set.Rules))
+	for _, r := range set.Rules {
+		rules = append(rules, dto.OvertimeRuleResponse{
+			DayType:    r.DayType,
+			FromHour:   r.FromHour,
+			ToHour:     r.ToHour,
+			Multiplier: r.Multiplier,
+		})
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].DayType != rules[j].DayType {
+			return dayTypeOrder(rules[i].DayType) < dayTypeOrder(rules[j].DayType)
+		}
+		return rules[i].FromHour.LessThan(rules[j].FromHour)
+	})
+
+	return dto.OvertimeRuleSetResponse{
+		ID:            set.ID,
+		Version:       set.Version,
+		EffectiveFrom: set.EffectiveFrom.Format("2006-01-02"),
+		Rules:         rules,
+	}
+}
+
+func dayTypeOrder(dayType string) int {
+	for i, t := range overtimeDayTypes {
+		if t == dayType {
+			return i
+		}
+	}
+	return len(overtimeDayTypes)
+}
@@ -0,0 +1,121 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestRouterForOvertimeRules() *gin.Engine {
+	r := gin.Default()
+	r.GET("/overtime-rules", AuthStubMiddlewareForPayroll(), handlers.ListOvertimeRuleSets)
+	r.POST("/overtime-rules", AuthStubMiddlewareForPayroll(), handlers.CreateOvertimeRuleSet)
+	return r
+}
+
+func overtimeRuleSet(version, effectiveFrom string, rules ...map[string]any) map[string]any {
+	return map[string]any{"version": version, "effective_from": effectiveFrom, "rules": rules}
+}
+
+func overtimeRule(dayType, from string, to any, multiplier string) map[string]any {
+	return map[string]any{"day_type": dayType, "from_hour": from, "to_hour": to, "multiplier": multiplier}
+}
+
+func TestOvertimeRules_TieredPay(t *testing.T) {
+	r := setupTestRouterForOvertimeRules()
+	d, cleanup, err := setupTestDBForHolidays()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	weekday := []map[string]any{overtimeRule("weekday", "0", "1", "1.5"), overtimeRule("weekday", "1", nil, "2")}
+	restDay := overtimeRule("rest_day", "0", nil, "2")
+	holiday := overtimeRule("holiday", "0", nil, "3")
+
+	w := postJSON(r, http.MethodPost, "/overtime-rules", overtimeRuleSet("2025-07", "2025-07-01T00:00:00Z", weekday[0], weekday[1], restDay))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "no rule for holiday")
+
+	w = postJSON(r, http.MethodPost, "/overtime-rules", overtimeRuleSet("2025-07", "2025-07-01T00:00:00Z",
+		overtimeRule("weekday", "0", "1", "1.5"), overtimeRule("weekday", "2", nil, "2"), restDay, holiday))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "should start at hour 1")
+
+	w = postJSON(r, http.MethodPost, "/overtime-rules", overtimeRuleSet("2025-07", "2025-07-01T00:00:00Z", weekday[0], weekday[1], restDay, holiday))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON(r, http.MethodPost, "/overtime-rules", overtimeRuleSet("2025-07", "2025-07-01T00:00:00Z", weekday[0], weekday[1], restDay, holiday))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "version 2025-07 already exists")
+
+	// rules cannot change a processed payroll
+	d.Create(&models.Payroll{
+		Month:       8,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+		Status:      models.PayrollStatusProcessed,
+	})
+	w = postJSON(r, http.MethodPost, "/overtime-rules", overtimeRuleSet("2025-06", "2025-06-01T00:00:00Z", weekday[0], weekday[1], restDay, holiday))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
+
+	w = postJSON(r, http.MethodGet, "/overtime-rules", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":"2025-07"`)
+	assert.Contains(t, w.Body.String(), `"version":"flat"`)
+
+	// 23 working days in July 2025, an hourly rate of 10,000
+	var employee models.User
+	d.First(&employee, 2)
+	employee.Salary = decimal.NewFromInt(1840000)
+	payroll := models.Payroll{
+		Month:       7,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), HoursWorked: 3, Status: models.OvertimeStatusApproved})
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC), HoursWorked: 2, Status: models.OvertimeStatusApproved})
+	d.Create(&models.Overtime{UserID: 2, Date: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC), HoursWorked: 1, Status: models.OvertimeStatusSubmitted})
+
+	payslip, err := handlers.GeneratePayslip(d, 1, employee, &payroll)
+	assert.NoError(t, err)
+	// 1 hour at 1.5x and 2 at 2x on Tuesday, 2 hours at 2x on Saturday
+	assert.Equal(t, "95000", payslip.OvertimePay.String())
+	assert.Equal(t, "15000", payslip.OvertimeRatePerHour.String())
+	assert.Equal(t, "30000", payslip.HolidayOvertimeRatePerHour.String())
+
+	var breakdown []dto.OvertimeBreakdownItem
+	assert.NoError(t, json.Unmarshal([]byte(payslip.OvertimeBreakdown), &breakdown))
+	if assert.Len(t, breakdown, 3) {
+		assert.Equal(t, models.OvertimeDayWeekday, breakdown[0].DayType)
+		assert.Equal(t, "2025-07", breakdown[0].RuleVersion)
+		assert.Equal(t, "1.8333", breakdown[0].Multiplier.String())
+		tiers := []string{}
+		for _, tier := range breakdown[0].Tiers {
+			tiers = append(tiers, tier.Hours.String()+"h x"+tier.Multiplier.String())
+		}
+		assert.Equal(t, []string{"1h x1.5", "2h x2"}, tiers)
+		assert.Equal(t, "55000", breakdown[0].Amount.String())
+
+		assert.Equal(t, models.OvertimeDayRestDay, breakdown[1].DayType)
+		assert.Equal(t, "2", breakdown[1].Multiplier.String())
+		assert.Equal(t, "40000", breakdown[1].Amount.String())
+
+		// awaiting approval, not paid
+		assert.Equal(t, "1.5", breakdown[2].Multiplier.String())
+		assert.Equal(t, "0", breakdown[2].Amount.String())
+	}
+}
@@ -8,13 +8,16 @@ import (
 	"dealls-case-study/internal/models"
 	"dealls-case-study/internal/utils"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func AuthStubMiddlewareForOvertime() gin.HandlerFunc {
@@ -31,6 +34,18 @@ func setupTestRouterForOvertime() *gin.Engine {
 	return r
 }
 
+// recentDay is the latest day up to today, in the company timezone, that is a weekend or a weekday.
+func recentDay(weekend bool) time.Time {
+	day := utils.CalendarDate(time.Now(), utils.CompanyLocation())
+	for {
+		w := day.Weekday()
+		if (w == time.Saturday || w == time.Sunday) == weekend {
+			return day
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
 func setupDBWithAttendanceAndCheckOut() (*models.User, func(), error) {
 	d, cleanup, err := db.InitTestDB()
 	if err != nil {
@@ -46,11 +61,12 @@ func setupDBWithAttendanceAndCheckOut() (*models.User, func(), error) {
 	}
 	d.Create(&user)
 
-	checkIn := time.Now().Add(-10 * time.Hour)
-	checkOut := time.Now().Add(-2 * time.Hour)
+	date := recentDay(false)
+	checkIn := date.Add(1 * time.Hour)
+	checkOut := date.Add(9 * time.Hour)
 	attendance := models.Attendance{
 		UserID:     user.ID,
-		Date:       time.Now(),
+		Date:       date,
 		CheckInAt:  &checkIn,
 		CheckOutAt: &checkOut,
 		CreatedBy:  user.ID,
@@ -75,10 +91,11 @@ func setupDBWithCheckInOnly() (*models.User, func(), error) {
 	}
 	d.Create(&user)
 
-	checkIn := time.Now().Add(-6 * time.Hour)
+	date := recentDay(false)
+	checkIn := date.Add(1 * time.Hour)
 	attendance := models.Attendance{
 		UserID:    user.ID,
-		Date:      time.Now(),
+		Date:      date,
 		CheckInAt: &checkIn,
 		CreatedBy: user.ID,
 	}
@@ -95,7 +112,8 @@ func TestSubmitOvertime_Success(t *testing.T) {
 	}
 	defer cleanup()
 
-	payload := dto.SubmitOvertimeRequest{HoursWorked: 2}
+	date := recentDay(false)
+	payload := dto.SubmitOvertimeRequest{HoursWorked: 2, Date: &date}
 	body, _ := json.Marshal(payload)
 
 	req := httptest.NewRequest(http.MethodPost, "/attendances/overtime", bytes.NewReader(body))
@@ -109,6 +127,120 @@ func TestSubmitOvertime_Success(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Nil(t, err)
 	assert.Equal(t, 2.0, resp.Data.HoursWorked)
+	assert.Equal(t, models.OvertimeDayWeekday, resp.Data.DayType)
+}
+
+func TestSubmitOvertime_UserMonthlyCap(t *testing.T) {
+	t.Setenv("OVERTIME_MONTHLY_CAP", "4")
+
+	r := setupTestRouterForLeaves()
+	r.POST("/attendances/overtime", handlers.SubmitOvertime)
+	r.PUT("/users/:id/overtime-cap", handlers.UpdateUserOvertimeCap)
+	_, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := leaveRequest(r, 1, http.MethodPut, "/users/2/overtime-cap", gin.H{"overtime_monthly_cap": -1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = leaveRequest(r, 1, http.MethodPut, "/users/2/overtime-cap", gin.H{"overtime_monthly_cap": 1})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var user dto.SuccessResponse[dto.UserResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &user))
+	if assert.NotNil(t, user.Data.OvertimeMonthlyCap) {
+		assert.Equal(t, 1.0, *user.Data.OvertimeMonthlyCap)
+	}
+
+	restDay := recentDay(true)
+	submit := func() *httptest.ResponseRecorder {
+		return leaveRequest(r, 2, http.MethodPost, "/attendances/overtime", map[string]any{
+			"hours_worked": 2, "date": restDay.Format("2006-01-02") + "T00:00:00Z",
+		})
+	}
+	w = submit()
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "capped at 1 hours a month")
+
+	// without an override the company cap applies
+	w = leaveRequest(r, 1, http.MethodPut, "/users/2/overtime-cap", gin.H{"overtime_monthly_cap": nil})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = submit()
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSubmitOvertime_Backdated(t *testing.T) {
+	t.Setenv("OVERTIME_SUBMISSION_DAYS", "7")
+	t.Setenv("OVERTIME_MONTHLY_CAP", "4")
+
+	r := setupTestRouterForLeaves()
+	r.POST("/attendances/overtime", handlers.SubmitOvertime)
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	submit := func(date time.Time, hours float64) *httptest.ResponseRecorder {
+		return leaveRequest(r, 2, http.MethodPost, "/attendances/overtime", map[string]any{
+			"hours_worked": hours, "date": date.Format("2006-01-02") + "T00:00:00Z",
+		})
+	}
+	today := utils.CalendarDate(time.Now(), utils.CompanyLocation())
+
+	w := submit(today.AddDate(0, 0, -8), 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "can only be submitted from")
+	w = submit(today.AddDate(0, 0, 1), 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// a working day needs a checked-out attendance
+	w = submit(recentDay(false), 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "attendance record not found")
+
+	// a rest day does not, but the hours count towards the monthly cap
+	restDay := recentDay(true)
+	other := time.Date(restDay.Year(), restDay.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if other.Equal(restDay) {
+		other = other.AddDate(0, 0, 1)
+	}
+	d.Create(&models.Overtime{UserID: 2, Date: other, HoursWorked: 3, Status: models.OvertimeStatusApproved})
+	w = submit(restDay, 2)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "capped at 4 hours a month")
+
+	w = submit(restDay, 1)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.SuccessResponse[dto.SubmitOvertimeResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, restDay.Format("2006-01-02"), resp.Data.Date)
+	assert.Equal(t, models.OvertimeDayRestDay, resp.Data.DayType)
+
+	w = submit(restDay, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already submitted")
+	// the database backs the check, for submissions racing each other
+	err = d.Create(&models.Overtime{UserID: 2, Date: restDay, HoursWorked: 1, Status: models.OvertimeStatusSubmitted}).Error
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	// nothing can be added to a processed period
+	d.Create(&models.Payroll{
+		Month:       int(today.Month()),
+		Year:        today.Year(),
+		PeriodStart: today.AddDate(0, 0, -7),
+		PeriodEnd:   today,
+		Status:      models.PayrollStatusProcessed,
+	})
+	d.Create(&models.Attendance{
+		UserID:     2,
+		Date:       recentDay(false),
+		CheckInAt:  timePtr(recentDay(false).Add(1 * time.Hour)),
+		CheckOutAt: timePtr(recentDay(false).Add(9 * time.Hour)),
+	})
+	w = submit(recentDay(false), 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already processed")
 }
 
 func TestSubmitOvertime_AttendanceNotFound(t *testing.T) {
@@ -119,7 +251,8 @@ func TestSubmitOvertime_AttendanceNotFound(t *testing.T) {
 	}
 	defer cleanup()
 
-	payload := dto.SubmitOvertimeRequest{HoursWorked: 2}
+	date := recentDay(false)
+	payload := dto.SubmitOvertimeRequest{HoursWorked: 2, Date: &date}
 	body, _ := json.Marshal(payload)
 
 	req := httptest.NewRequest(http.MethodPost, "/attendances/overtime", bytes.NewReader(body))
@@ -145,7 +278,8 @@ func TestSubmitOvertime_MissingCheckOut(t *testing.T) {
 	}
 	defer cleanup()
 
-	payload := dto.SubmitOvertimeRequest{HoursWorked: 1.5}
+	date := recentDay(false)
+	payload := dto.SubmitOvertimeRequest{HoursWorked: 1.5, Date: &date}
 	body, _ := json.Marshal(payload)
 
 	req := httptest.NewRequest(http.MethodPost, "/attendances/overtime", bytes.NewReader(body))
@@ -156,3 +290,113 @@ func TestSubmitOvertime_MissingCheckOut(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "you must check out before submitting overtime")
 }
+
+func TestReviewOvertime(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	r.GET("/overtimes/pending", handlers.ListPendingOvertimes)
+	r.POST("/overtimes/:id/approve", handlers.ApproveOvertime)
+	r.POST("/overtimes/:id/reject", handlers.RejectOvertime)
+
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	for day := 2; day <= 4; day++ {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 17, 0, 0, 0, time.UTC)),
+		})
+		d.Create(&models.Overtime{
+			UserID:      2,
+			Date:        time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			HoursWorked: 1,
+			Status:      models.OvertimeStatusSubmitted,
+		})
+	}
+
+	w := leaveRequest(r, 3, http.MethodGet, "/overtimes/pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var pending dto.SuccessResponse[[]dto.OvertimeResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	assert.Len(t, pending.Data, 3)
+	approveID, rejectID := pending.Data[0].ID, pending.Data[1].ID
+
+	// not the employee's manager
+	w = leaveRequest(r, 4, http.MethodPost, fmt.Sprintf("/overtimes/%d/approve", approveID), nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/approve", approveID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/approve", approveID), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/reject", rejectID), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "reason is required")
+	w = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/reject", rejectID), map[string]any{"reason": "not pre-approved"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = leaveRequest(r, 999, http.MethodGet, "/payrolls/2025/6/preview", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var p dto.EmployeePayslipPreview
+	for _, payslip := range resp.Data.Payslips {
+		if payslip.UserID == 2 {
+			p = payslip
+		}
+	}
+	// only the approved hour is paid, at 2x of 2,100,000 / (21 * 8)
+	assert.Equal(t, float64(1), p.TotalOvertimeHours)
+	assert.Equal(t, "25000", p.OvertimePay.String())
+	assert.Contains(t, p.Warnings, "1 overtime submissions awaiting approval are not paid")
+}
+
+func TestReviewOvertime_Concurrent(t *testing.T) {
+	r := setupTestRouterForLeaves()
+	r.POST("/overtimes/:id/approve", handlers.ApproveOvertime)
+	r.POST("/overtimes/:id/reject", handlers.RejectOvertime)
+
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	overtime := models.Overtime{UserID: 2, Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), HoursWorked: 1, Status: models.OvertimeStatusSubmitted}
+	d.Create(&overtime)
+
+	// an approval and a rejection at the same time, only one of them is applied
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		codes[0] = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/approve", overtime.ID), nil).Code
+	}()
+	go func() {
+		defer wg.Done()
+		codes[1] = leaveRequest(r, 3, http.MethodPost, fmt.Sprintf("/overtimes/%d/reject", overtime.ID), map[string]any{"reason": "not pre-approved"}).Code
+	}()
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusBadRequest}, codes)
+	var current models.Overtime
+	d.First(&current, overtime.ID)
+	if codes[0] == http.StatusOK {
+		assert.Equal(t, models.OvertimeStatusApproved, current.Status)
+	} else {
+		assert.Equal(t, models.OvertimeStatusRejected, current.Status)
+	}
+}
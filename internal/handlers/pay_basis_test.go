@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayBasis_Payroll(t *testing.T) {
+	t.Setenv("WORK_HOURS_ROUNDING", "15m")
+	t.Setenv("WORK_HOURS_BREAK", "1h")
+	t.Setenv("WORK_HOURS_BREAK_AFTER", "6h")
+
+	r := setupTestRouterForLeaves()
+	r.PUT("/users/:id/pay-basis", handlers.UpdateUserPayBasis)
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := leaveRequest(r, 1, http.MethodPut, "/users/2/pay-basis", gin.H{"pay_basis": "weekly"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = leaveRequest(r, 1, http.MethodPut, "/users/2/pay-basis", gin.H{"pay_basis": "hourly"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var user dto.SuccessResponse[dto.UserResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Equal(t, models.PayBasisHourly, user.Data.PayBasis)
+	w = leaveRequest(r, 1, http.MethodPut, "/users/4/pay-basis", gin.H{"pay_basis": "daily"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the salary is the rate of the pay basis
+	d.Model(&models.User{}).Where("id = ?", 2).Update("salary", 20000)
+	d.Model(&models.User{}).Where("id = ?", 4).Update("salary", 100000)
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	attend := func(userID uint, day int, checkIn, checkOut time.Duration) {
+		date := time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC)
+		attendance := models.Attendance{UserID: userID, Date: date, CheckInAt: timePtr(date.Add(checkIn))}
+		if checkOut > 0 {
+			attendance.CheckOutAt = timePtr(date.Add(checkOut))
+		}
+		d.Create(&attendance)
+	}
+	// 9h10m less the break, rounded to 8h15m
+	attend(2, 2, 8*time.Hour, 17*time.Hour+10*time.Minute)
+	// 4h07m without a break, rounded to 4h
+	attend(2, 3, 9*time.Hour, 13*time.Hour+7*time.Minute)
+	// no check-out, no hours
+	attend(2, 4, 9*time.Hour, 0)
+	attend(4, 2, 8*time.Hour, 17*time.Hour)
+	attend(4, 3, 8*time.Hour, 12*time.Hour)
+
+	// paid leave is paid at the shift's hours
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves", gin.H{
+		"leave_type": "annual", "start_date": "2025-06-05T00:00:00Z", "end_date": "2025-06-05T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var leave dto.SuccessResponse[dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &leave))
+	w = leaveRequest(r, 3, http.MethodPost, "/leaves/"+strconv.Itoa(int(leave.Data.ID))+"/approve", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = leaveRequest(r, 1, http.MethodGet, "/payrolls/2025/6/preview", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var preview dto.SuccessResponse[dto.PayrollPreviewResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &preview))
+
+	var hourly, daily dto.EmployeePayslipPreview
+	for _, payslip := range preview.Data.Payslips {
+		switch payslip.UserID {
+		case 2:
+			hourly = payslip
+		case 4:
+			daily = payslip
+		}
+	}
+
+	assert.Equal(t, models.PayBasisHourly, hourly.PayBasis)
+	assert.Equal(t, 12.25, hourly.TotalHoursWorked)
+	assert.Equal(t, "20000", hourly.HourlyRate.String())
+	// 20,000 * (8.25 + 4 hours worked + 8 hours of leave)
+	assert.Equal(t, "405000", hourly.BaseSalary.String())
+	if assert.Len(t, hourly.AttendanceBreakdown, 3) {
+		day := hourly.AttendanceBreakdown[0]
+		assert.Equal(t, "2025-06-02", day.Date)
+		assert.InDelta(t, 9.1667, day.HoursWorked, 0.001)
+		assert.Equal(t, "1", day.BreakHours.String())
+		assert.Equal(t, "8.25", day.PaidHours.String())
+		assert.Equal(t, "165000", day.Amount.String())
+		assert.Equal(t, "4", hourly.AttendanceBreakdown[1].PaidHours.String())
+		assert.Nil(t, hourly.AttendanceBreakdown[2].CheckOutAt)
+		assert.True(t, hourly.AttendanceBreakdown[2].Amount.IsZero())
+	}
+
+	assert.Equal(t, models.PayBasisDaily, daily.PayBasis)
+	// 100,000 a day for 2 days, whatever their hours
+	assert.Equal(t, "200000", daily.BaseSalary.String())
+	// 100,000 * 21 days / 168 hours
+	assert.Equal(t, "12500", daily.HourlyRate.String())
+	if assert.Len(t, daily.AttendanceBreakdown, 2) {
+		assert.Equal(t, "8", daily.AttendanceBreakdown[1].PaidHours.String())
+		assert.Equal(t, "100000", daily.AttendanceBreakdown[1].Amount.String())
+	}
+}
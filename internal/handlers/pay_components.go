@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// payComponentVariables are the variables a pay component formula can refer to.
+var payComponentVariables = []string{
+	"amount",
+	"monthly_salary",
+	"base_salary",
+	"hourly_rate",
+	"days_attended",
+	"expected_working_days",
+	"overtime_hours",
+	"overtime_pay",
+}
+
+// CreatePayComponent godoc
+// @Summary      Create pay component
+// @Description  Creates an earning or deduction that can be assigned to employees or roles.
+// @Description  A formula may use the variables amount, monthly_salary, base_salary, hourly_rate, days_attended,
+// @Description  expected_working_days, overtime_hours and overtime_pay, the operators + - * / and the functions min and max.
+// @Tags         Pay Components
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.PayComponentRequest true "Pay component"
+// @Success      201    {object}  dto.SuccessResponse[dto.PayComponentResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components [post]
+func CreatePayComponent(c *gin.Context) {
+	var req dto.PayComponentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePayComponent(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.PayComponent{}).Where("code = ?", req.Code).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pay component code already exists"})
+		return
+	}
+
+	component := models.PayComponent{
+		Recurring: true,
+		Active:    true,
+		CreatedBy: c.GetUint("user_id"),
+	}
+	applyPayComponentRequest(&component, req)
+
+	if err := db.DB.Create(&component).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create pay component"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toPayComponentResponse(component)))
+}
+
+// ListPayComponents godoc
+// @Summary      List pay components
+// @Tags         Pay Components
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.PayComponentResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components [get]
+func ListPayComponents(c *gin.Context) {
+	var components []models.PayComponent
+	if err := db.DB.Order("code").Find(&components).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list pay components"})
+		return
+	}
+
+	resp := make([]dto.PayComponentResponse, 0, len(components))
+	for _, component := range components {
+		resp = append(resp, toPayComponentResponse(component))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// UpdatePayComponent godoc
+// @Summary      Update pay component
+// @Description  Replaces the definition of a pay component. Payslips already generated keep their amounts.
+// @Tags         Pay Components
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Pay component ID"
+// @Param        request body     dto.PayComponentRequest true "Pay component"
+// @Success      200    {object}  dto.SuccessResponse[dto.PayComponentResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components/{id} [put]
+func UpdatePayComponent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay component id"})
+		return
+	}
+
+	var req dto.PayComponentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePayComponent(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var component models.PayComponent
+	if err := db.DB.First(&component, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pay component not found"})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.PayComponent{}).Where("code = ? AND id <> ?", req.Code, component.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pay component code already exists"})
+		return
+	}
+
+	applyPayComponentRequest(&component, req)
+	component.UpdatedBy = c.GetUint("user_id")
+
+	if err := db.DB.Save(&component).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update pay component"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayComponentResponse(component)))
+}
+
+// CreatePayComponentAssignment godoc
+// @Summary      Assign pay component
+// @Description  Assigns a pay component to a single employee (user_id) or to every employee of a role (role_id).
+// @Description  An employee assignment takes precedence over a role assignment of the same component.
+// @Tags         Pay Components
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Pay component ID"
+// @Param        request body     dto.PayComponentAssignmentRequest true "Assignment"
+// @Success      201    {object}  dto.SuccessResponse[dto.PayComponentAssignmentResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components/{id}/assignments [post]
+func CreatePayComponentAssignment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay component id"})
+		return
+	}
+
+	var req dto.PayComponentAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if (req.UserID == nil) == (req.RoleID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of user_id or role_id is required"})
+		return
+	}
+	if req.Amount.Valid && req.Amount.Decimal.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must not be negative"})
+		return
+	}
+	if req.EffectiveTo != nil && req.EffectiveTo.Before(req.EffectiveFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_to must not be before effective_from"})
+		return
+	}
+
+	var component models.PayComponent
+	if err := db.DB.First(&component, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pay component not found"})
+		return
+	}
+
+	if req.UserID != nil {
+		if err := db.DB.First(&models.User{}, *req.UserID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user not found"})
+			return
+		}
+	}
+	if req.RoleID != nil {
+		if err := db.DB.First(&models.Role{}, *req.RoleID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role not found"})
+			return
+		}
+	}
+
+	assignment := models.PayComponentAssignment{
+		PayComponentID: component.ID,
+		UserID:         req.UserID,
+		RoleID:         req.RoleID,
+		Amount:         req.Amount,
+		EffectiveFrom:  req.EffectiveFrom,
+		EffectiveTo:    req.EffectiveTo,
+		CreatedBy:      c.GetUint("user_id"),
+	}
+	if err := db.DB.Create(&assignment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign pay component"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toPayComponentAssignmentResponse(assignment)))
+}
+
+// ListPayComponentAssignments godoc
+// @Summary      List pay component assignments
+// @Tags         Pay Components
+// @Produce      json
+// @Param        id     path      int  true  "Pay component ID"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.PayComponentAssignmentResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components/{id}/assignments [get]
+func ListPayComponentAssignments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay component id"})
+		return
+	}
+
+	var assignments []models.PayComponentAssignment
+	if err := db.DB.Where("pay_component_id = ?", id).Order("effective_from, id").Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list pay component assignments"})
+		return
+	}
+
+	resp := make([]dto.PayComponentAssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		resp = append(resp, toPayComponentAssignmentResponse(a))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// DeletePayComponentAssignment godoc
+// @Summary      Remove pay component assignment
+// @Description  Future payrolls no longer pay the component to the assignee. Payslips already generated keep their lines.
+// @Tags         Pay Components
+// @Produce      json
+// @Param        id             path      int  true  "Pay component ID"
+// @Param        assignmentId   path      int  true  "Assignment ID"
+// @Success      200    {object}  dto.SuccessResponse[dto.PayComponentAssignmentResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /pay-components/{id}/assignments/{assignmentId} [delete]
+func DeletePayComponentAssignment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pay component id"})
+		return
+	}
+	assignmentID, err := strconv.Atoi(c.Param("assignmentId"))
+	if err != nil || assignmentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment id"})
+		return
+	}
+
+	var assignment models.PayComponentAssignment
+	if err := db.DB.Where("id = ? AND pay_component_id = ?", assignmentID, id).First(&assignment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&assignment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove pay component assignment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayComponentAssignmentResponse(assignment)))
+}
+
+func validatePayComponent(req dto.PayComponentRequest) error {
+	if req.Amount.IsNegative() {
+		return errors.New("amount must not be negative")
+	}
+	if req.CalculationType != models.PayComponentCalculationFormula {
+		return nil
+	}
+
+	if req.Formula == "" {
+		return errors.New("formula is required for formula components")
+	}
+	formula, err := utils.ParseFormula(req.Formula)
+	if err != nil {
+		return fmt.Errorf("invalid formula: %w", err)
+	}
+	for _, name := range formula.Variables() {
+		if !containsString(payComponentVariables, name) {
+			return fmt.Errorf("invalid formula: unknown variable %q", name)
+		}
+	}
+	return nil
+}
+
+func applyPayComponentRequest(component *models.PayComponent, req dto.PayComponentRequest) {
+	component.Code = req.Code
+	component.Name = req.Name
+	component.Type = req.Type
+	component.CalculationType = req.CalculationType
+	component.Amount = req.Amount
+	component.Formula = ""
+	if req.CalculationType == models.PayComponentCalculationFormula {
+		component.Formula = req.Formula
+	}
+	component.Taxable = req.Taxable
+	if req.Recurring != nil {
+		component.Recurring = *req.Recurring
+	}
+	if req.Active != nil {
+		component.Active = *req.Active
+	}
+}
+
+// findPayComponentAssignments returns the active component assignments that pay out to a
+// user in a payroll period, one per component, preferring user over role assignments.
+func findPayComponentAssignments(tx *gorm.DB, user models.User, payroll *models.Payroll) ([]models.PayComponentAssignment, error) {
+	var assignments []models.PayComponentAssignment
+	if err := tx.
+		Preload("PayComponent").
+		Joins("JOIN pay_components ON pay_components.id = pay_component_assignments.pay_component_id").
+		Where("pay_components.active = ?", true).
+		Where("pay_component_assignments.user_id = ? OR pay_component_assignments.role_id = ?", user.ID, user.RoleID).
+		Order("pay_components.code, pay_component_assignments.id").
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	var result []models.PayComponentAssignment
+	index := map[uint]int{}
+	for _, a := range assignments {
+		if !a.AppliesTo(payroll.PeriodStart, payroll.PeriodEnd) {
+			continue
+		}
+		i, seen := index[a.PayComponentID]
+		if !seen {
+			index[a.PayComponentID] = len(result)
+			result = append(result, a)
+			continue
+		}
+		// a user assignment overrides a role assignment, a later assignment an earlier one
+		if a.UserID != nil || result[i].UserID == nil {
+			result[i] = a
+		}
+	}
+	return result, nil
+}
+
+// computePayComponents evaluates the pay components assigned to a user into payslip lines.
+// vars are the formula variables of the payslip, "amount" is set per assignment.
+func computePayComponents(tx *gorm.DB, user models.User, payroll *models.Payroll, vars map[string]decimal.Decimal, rounding utils.MoneyRounding) ([]models.PayslipLine, error) {
+	assignments, err := findPayComponentAssignments(tx, user, payroll)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []models.PayslipLine
+	for _, a := range assignments {
+		component := a.PayComponent
+
+		amount := component.Amount
+		if a.Amount.Valid {
+			amount = a.Amount.Decimal
+		}
+
+		if component.CalculationType == models.PayComponentCalculationFormula {
+			formula, err := utils.ParseFormula(component.Formula)
+			if err != nil {
+				return nil, fmt.Errorf("pay component %s: invalid formula: %w", component.Code, err)
+			}
+			vars["amount"] = amount
+			amount, err = formula.Evaluate(vars)
+			if err != nil {
+				return nil, fmt.Errorf("pay component %s: %w", component.Code, err)
+			}
+		}
+
+		if amount.IsNegative() {
+			return nil, fmt.Errorf("pay component %s evaluated to a negative amount", component.Code)
+		}
+
+		componentID := component.ID
+		lines = append(lines, models.PayslipLine{
+			PayComponentID: &componentID,
+			Code:           component.Code,
+			Name:           component.Name,
+			Type:           component.Type,
+			Amount:         rounding.RoundLine(amount),
+			Taxable:        component.Taxable,
+		})
+	}
+	return lines, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toPayComponentResponse(component models.PayComponent) dto.PayComponentResponse {
+	return dto.PayComponentResponse{
+		ID:              component.ID,
+		Code:            component.Code,
+		Name:            component.Name,
+		Type:            component.Type,
+		CalculationType: component.CalculationType,
+		Amount:          component.Amount,
+		Formula:         component.Formula,
+		Taxable:         component.Taxable,
+		Recurring:       component.Recurring,
+		Active:          component.Active,
+	}
+}
+
+func toPayComponentAssignmentResponse(a models.PayComponentAssignment) dto.PayComponentAssignmentResponse {
+	return dto.PayComponentAssignmentResponse{
+		ID:             a.ID,
+		PayComponentID: a.PayComponentID,
+		UserID:         a.UserID,
+		RoleID:         a.RoleID,
+		Amount:         a.Amount,
+		EffectiveFrom:  a.EffectiveFrom,
+		EffectiveTo:    a.EffectiveTo,
+	}
+}
@@ -0,0 +1,229 @@
+package handlers_test
+
+import (
+	"bytes"
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestRouterForPayComponents() *gin.Engine {
+	r := gin.Default()
+	r.POST("/pay-components", AuthStubMiddlewareForPayroll(), handlers.CreatePayComponent)
+	r.GET("/pay-components", AuthStubMiddlewareForPayroll(), handlers.ListPayComponents)
+	r.PUT("/pay-components/:id", AuthStubMiddlewareForPayroll(), handlers.UpdatePayComponent)
+	r.POST("/pay-components/:id/assignments", AuthStubMiddlewareForPayroll(), handlers.CreatePayComponentAssignment)
+	r.GET("/pay-components/:id/assignments", AuthStubMiddlewareForPayroll(), handlers.ListPayComponentAssignments)
+	r.DELETE("/pay-components/:id/assignments/:assignmentId", AuthStubMiddlewareForPayroll(), handlers.DeletePayComponentAssignment)
+	r.GET("/payrolls/:year/:month/preview", AuthStubMiddlewareForPayroll(), handlers.PreviewPayroll)
+	return r
+}
+
+func setupTestDBForPayComponents() (*gorm.DB, func(), error) {
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	employee := models.User{
+		ID:       2,
+		Username: "employee",
+		Password: "password",
+		RoleID:   2,
+		Salary:   decimal.NewFromInt(2100000),
+	}
+	if err := d.Create(&employee).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return d, cleanup, nil
+}
+
+func postJSON(r *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreatePayComponent_Success(t *testing.T) {
+	r := setupTestRouterForPayComponents()
+	_, cleanup, err := setupTestDBForPayComponents()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code":             "meal",
+		"name":             "Meal Allowance",
+		"type":             "earning",
+		"calculation_type": "formula",
+		"amount":           "25000",
+		"formula":          "amount * days_attended",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayComponentResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "meal", resp.Data.Code)
+	assert.True(t, resp.Data.Recurring)
+	assert.True(t, resp.Data.Active)
+	assert.False(t, resp.Data.Taxable)
+
+	// codes are unique
+	w = postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code":             "meal",
+		"name":             "Meal Allowance",
+		"type":             "earning",
+		"calculation_type": "fixed",
+		"amount":           "25000",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreatePayComponent_InvalidFormula(t *testing.T) {
+	r := setupTestRouterForPayComponents()
+	_, cleanup, err := setupTestDBForPayComponents()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code":             "bonus",
+		"name":             "Bonus",
+		"type":             "earning",
+		"calculation_type": "formula",
+		"formula":          "sales * 0.1",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown variable \"sales\"`)
+}
+
+func TestCreatePayComponentAssignment_RequiresOneAssignee(t *testing.T) {
+	r := setupTestRouterForPayComponents()
+	d, cleanup, err := setupTestDBForPayComponents()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	component := models.PayComponent{Code: "transport", Name: "Transport", Type: models.PayComponentTypeEarning, CalculationType: models.PayComponentCalculationFixed, Amount: decimal.NewFromInt(500000), Recurring: true, Active: true}
+	d.Create(&component)
+
+	w := postJSON(r, http.MethodPost, "/pay-components/1/assignments", map[string]any{
+		"user_id":        2,
+		"role_id":        2,
+		"effective_from": "2025-01-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "exactly one of user_id or role_id is required")
+}
+
+func TestPayComponents_InPayslipPreview(t *testing.T) {
+	r := setupTestRouterForPayComponents()
+	d, cleanup, err := setupTestDBForPayComponents()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+
+	for day := 2; day <= 3; day++ {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 17, 0, 0, 0, time.UTC)),
+		})
+	}
+
+	// transport for every employee, overridden for this one
+	w := postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code": "transport", "name": "Transport Allowance", "type": "earning",
+		"calculation_type": "fixed", "amount": "500000", "taxable": true,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(r, http.MethodPost, "/pay-components/1/assignments", map[string]any{
+		"role_id": 2, "effective_from": "2025-01-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(r, http.MethodPost, "/pay-components/1/assignments", map[string]any{
+		"user_id": 2, "amount": "300000", "effective_from": "2025-01-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code": "meal", "name": "Meal Allowance", "type": "earning",
+		"calculation_type": "formula", "amount": "25000", "formula": "amount * days_attended",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(r, http.MethodPost, "/pay-components/2/assignments", map[string]any{
+		"user_id": 2, "effective_from": "2025-01-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON(r, http.MethodPost, "/pay-components", map[string]any{
+		"code": "loan", "name": "Loan Repayment", "type": "deduction",
+		"calculation_type": "fixed", "amount": "100000", "recurring": false,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	// one-off in May, not paid in June
+	w = postJSON(r, http.MethodPost, "/pay-components/3/assignments", map[string]any{
+		"user_id": 2, "effective_from": "2025-05-15T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(r, http.MethodPost, "/pay-components/3/assignments", map[string]any{
+		"user_id": 2, "effective_from": "2025-06-15T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Len(t, resp.Data.Payslips, 1)
+
+	p := resp.Data.Payslips[0]
+	// 2,100,000 * 2 / 21
+	assert.Equal(t, "200000", p.BaseSalary.String())
+	assert.Equal(t, "350000", p.OtherEarnings.String())
+	assert.Equal(t, "100000", p.OtherDeductions.String())
+	assert.Equal(t, "550000", p.TotalPay.String())
+
+	amounts := map[string]string{}
+	for _, line := range p.Lines {
+		amounts[line.Code] = line.Amount.String()
+	}
+	assert.Equal(t, "200000", amounts["base_salary"])
+	assert.Equal(t, "300000", amounts["transport"])
+	assert.Equal(t, "50000", amounts["meal"])
+	assert.Equal(t, "100000", amounts["loan"])
+	assert.Equal(t, "42000", amounts["bpjs_jht"])
+}
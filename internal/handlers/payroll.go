@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -14,16 +15,24 @@ import (
 	"dealls-case-study/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+var (
+	errPayrollNotFound     = errors.New("payroll record not found")
+	errPayrollNotProcessed = errors.New("payroll has not been processed")
+	errPayrollLocked       = errors.New("the payroll covering these dates is already processed")
+)
+
 // @BasePath /api/v1
 
 // UpsertPayroll godoc
 // @Summary      Upsert payroll
 // @Description  Creates or updates a payroll record for the given month and year.
-// @Description  Only updates payrolls with 'draft' status.
+// @Description  Only updates payrolls with 'draft' or 'failed' status, a failed run leaves no payslips behind.
+// @Description  period_start and period_end are taken as calendar dates in the company timezone, the period covers both days in full.
 // @Tags         Payroll
 // @Accept       json
 // @Produce      json
@@ -66,16 +75,20 @@ func UpsertPayroll(c *gin.Context) {
 			Month: month,
 			Year:  year,
 		}
+	} else if payroll.Status != models.PayrollStatusDraft && payroll.Status != models.PayrollStatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only draft or failed payrolls can be updated"})
+		return
 	}
 
 	if req.Name != nil {
 		payroll.Name = *req.Name
 	}
+	// periods are whole days, attendance and other dates are compared by calendar date
 	if req.PeriodStart != nil {
-		payroll.PeriodStart = *req.PeriodStart
+		payroll.PeriodStart = periodDate(*req.PeriodStart)
 	}
 	if req.PeriodEnd != nil {
-		payroll.PeriodEnd = *req.PeriodEnd
+		payroll.PeriodEnd = periodDate(*req.PeriodEnd)
 	}
 
 	if err := db.DB.Save(&payroll).Error; err != nil {
@@ -83,20 +96,24 @@ func UpsertPayroll(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.PayrollResponse{
-		ID:          payroll.ID,
-		Name:        payroll.Name,
-		PeriodStart: &payroll.PeriodStart,
-		PeriodEnd:   &payroll.PeriodEnd,
-		Status:      payroll.Status,
-	}))
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayrollResponse(payroll)))
+}
+
+// periodDate is the calendar date a period boundary falls on in the company timezone.
+// A bare date, midnight in whatever offset it was sent with, is taken as is.
+func periodDate(t time.Time) time.Time {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return dateOnly(t)
+	}
+	return utils.CalendarDate(t, utils.CompanyLocation())
 }
 
 // RunPayroll godoc
 // @Summary      Run payroll
-// @Description  Processes the payroll for the given month and year.
-// @Description  Generates payslips for all employees.
-// @Description  Can only be run once per period. Once run, the payroll status changes to 'pending' or 'completed'.
+// @Description  Queues the payroll for the given month and year for processing.
+// @Description  The payroll worker generates payslips for all employees in the background, retrying with backoff on failure.
+// @Description  Can only be run once per period. Once run, the payroll status changes to 'pending' until the worker marks it 'processed'.
+// @Description  Rejected when no tax table or BPJS rate table is effective on the period end.
 // @Tags         Payroll
 // @Accept       json
 // @Produce      json
@@ -131,33 +148,215 @@ func RunPayroll(c *gin.Context) {
 	case models.PayrollStatusPending:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll is currently being processed"})
 		return
+	case models.PayrollStatusFailed:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll processing has failed, use the retry endpoint"})
+		return
+	}
+	if err := checkPayrollRates(db.DB, payroll); errors.Is(err, errRateTableMissing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run payroll"})
+		return
 	}
 
 	payroll.Status = models.PayrollStatusPending
 	payroll.ProcessedAt = time.Now()
 	payroll.UpdatedBy = userID
+	openAttendances, err := countOpenAttendances(db.DB, payroll.PeriodStart, payroll.PeriodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run payroll"})
+		return
+	}
+	payroll.OpenAttendances = openAttendances
 
-	if err := db.DB.Save(&payroll).Error; err != nil {
+	// the payroll itself is processed by the payroll worker (see internal/worker),
+	// which picks up queued jobs and retries them with backoff on failure
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&payroll).Error; err != nil {
+			return err
+		}
+		return EnqueuePayrollJob(tx, payroll.ID, userID)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run payroll"})
 		return
 	}
 
-	// we use goroutine here for simplicity sake, just to demonstrate the async logic
-	// in real world application this should be processed using workers or some job queue solutions
-	// for example the simplest implementation would be a separate worker that processes any `pending` payroll
-	go ProcessPayroll(payroll.ID, userID)
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayrollResponse(payroll)))
+}
+
+// RetryPayroll godoc
+// @Summary      Retry failed payroll
+// @Description  Moves a failed payroll back into processing by queueing a new job for the payroll worker.
+// @Description  Only payrolls with 'failed' status can be retried, and only when a tax table and a BPJS rate table are effective on the period end.
+// @Tags         Payroll
+// @Accept       json
+// @Produce      json
+// @Param        year   path      int  true  "Year"
+// @Param        month  path      int  true  "Month (1-12)"
+// @Success      200    {object}  dto.SuccessResponse[dto.PayrollResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /payrolls/{year}/{month}/retry [post]
+func RetryPayroll(c *gin.Context) {
+	year, err1 := strconv.Atoi(c.Param("year"))
+	month, err2 := strconv.Atoi(c.Param("month"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year or month"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var payroll models.Payroll
+
+	if err := db.DB.Where("year = ? AND month = ?", year, month).First(&payroll).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll record not found"})
+		return
+	}
+
+	if payroll.Status != models.PayrollStatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only failed payrolls can be retried"})
+		return
+	}
+	if err := checkPayrollRates(db.DB, payroll); errors.Is(err, errRateTableMissing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retry payroll"})
+		return
+	}
+
+	payroll.Status = models.PayrollStatusPending
+	payroll.FailureReason = ""
+	payroll.FailedAt = nil
+	payroll.UpdatedBy = userID
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&payroll).Error; err != nil {
+			return err
+		}
+		return EnqueuePayrollJob(tx, payroll.ID, userID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retry payroll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayrollResponse(payroll)))
+}
+
+// ReopenPayroll godoc
+// @Summary      Reopen processed payroll
+// @Description  Reverses a processed payroll so it can be corrected and run again.
+// @Description  Existing payslips are marked as superseded (kept for audit) and the payroll goes back to 'draft' with its version incremented.
+// @Description  Reimbursements it paid go back to 'approved' and are paid by the next run.
+// @Tags         Payroll
+// @Accept       json
+// @Produce      json
+// @Param        year   path      int  true  "Year"
+// @Param        month  path      int  true  "Month (1-12)"
+// @Param        request body     dto.ReopenPayrollRequest true "Reopen reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.PayrollResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /payrolls/{year}/{month}/reopen [post]
+func ReopenPayroll(c *gin.Context) {
+	year, err1 := strconv.Atoi(c.Param("year"))
+	month, err2 := strconv.Atoi(c.Param("month"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year or month"})
+		return
+	}
+
+	var req dto.ReopenPayrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var payroll models.Payroll
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("year = ? AND month = ?", year, month).
+			First(&payroll).Error; err != nil {
+			return errPayrollNotFound
+		}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.PayrollResponse{
-		ID:          payroll.ID,
-		Name:        payroll.Name,
-		PeriodStart: &payroll.PeriodStart,
-		PeriodEnd:   &payroll.PeriodEnd,
-		Status:      payroll.Status,
-	}))
+		if payroll.Status != models.PayrollStatusProcessed {
+			return errPayrollNotProcessed
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Payslip{}).
+			Where("payroll_id = ? AND superseded_at IS NULL", payroll.ID).
+			Updates(map[string]interface{}{
+				"superseded_at": now,
+				"superseded_by": userID,
+			}).Error; err != nil {
+			return err
+		}
+
+		// the claims are paid again when the payroll is processed again
+		if err := tx.Model(&models.Reimbursement{}).
+			Where("payroll_id = ? AND status = ?", payroll.ID, models.ReimbursementStatusPaid).
+			Updates(map[string]interface{}{
+				"status":     models.ReimbursementStatusApproved,
+				"payroll_id": nil,
+				"paid_at":    nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		payroll.Status = models.PayrollStatusDraft
+		payroll.Version++
+		payroll.ReopenedAt = &now
+		payroll.ReopenedBy = userID
+		payroll.ReopenReason = req.Reason
+		payroll.UpdatedBy = userID
+
+		return tx.Save(&payroll).Error
+	})
+
+	switch {
+	case errors.Is(err, errPayrollNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll record not found"})
+		return
+	case errors.Is(err, errPayrollNotProcessed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only processed payrolls can be reopened"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reopen payroll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toPayrollResponse(payroll)))
+}
+
+// EnqueuePayrollJob queues a payroll for processing by the payroll worker.
+func EnqueuePayrollJob(tx *gorm.DB, payrollID uint, adminID uint) error {
+	job := models.PayrollJob{
+		PayrollID:   payrollID,
+		Status:      models.PayrollJobStatusQueued,
+		MaxAttempts: models.DefaultPayrollJobMaxAttempts,
+		RunAt:       time.Now(),
+		CreatedBy:   adminID,
+	}
+	return tx.Create(&job).Error
 }
 
-func ProcessPayroll(payrollID uint, adminID uint) error {
-	return db.DB.Transaction(func(tx *gorm.DB) error {
+// ProcessPayroll generates payslips for a pending payroll and marks it as processed.
+// It runs in its own (nested) transaction so a failure leaves no partial payslips behind.
+func ProcessPayroll(d *gorm.DB, payrollID uint, adminID uint) error {
+	return d.Transaction(func(tx *gorm.DB) error {
 		var payroll models.Payroll
 
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
@@ -171,8 +370,8 @@ func ProcessPayroll(payrollID uint, adminID uint) error {
 		}
 
 		var payslips []models.Payslip
-		var users []models.User
-		if err := tx.Find(&users).Error; err != nil {
+		users, err := findPayrollEmployees(tx, payroll)
+		if err != nil {
 			return err
 		}
 		for _, user := range users {
@@ -189,8 +388,17 @@ func ProcessPayroll(payrollID uint, adminID uint) error {
 				return err
 			}
 		}
+		for _, payslip := range payslips {
+			if err := markReimbursementsPaid(tx, payslip, payroll.ID); err != nil {
+				return err
+			}
+		}
 
 		payroll.Status = models.PayrollStatusProcessed
+		payroll.OpenAttendances, err = countOpenAttendances(tx, payroll.PeriodStart, payroll.PeriodEnd)
+		if err != nil {
+			return err
+		}
 		if err := tx.Save(&payroll).Error; err != nil {
 			return err
 		}
@@ -201,45 +409,305 @@ func ProcessPayroll(payrollID uint, adminID uint) error {
 }
 
 func GeneratePayslip(tx *gorm.DB, adminID uint, user models.User, payroll *models.Payroll) (models.Payslip, error) {
+	// joiners and leavers are only paid for the days they are employed
+	employedFrom, employedTo, employed := user.EmploymentWindow(payroll.PeriodStart, payroll.PeriodEnd)
+	if !employed {
+		return models.Payslip{}, fmt.Errorf("user %s is not employed in the payroll period", user.Username)
+	}
+
+	// working days and hours follow the employee's shifts
+	schedule, err := findSchedule(tx, user.ID)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
 	var attendances []models.Attendance
-	tx.Where("user_id = ? AND date BETWEEN ? AND ?", user.ID, payroll.PeriodStart, payroll.PeriodEnd).Find(&attendances)
+	tx.Where("user_id = ? AND date BETWEEN ? AND ?", user.ID, employedFrom, employedTo).Order("date").Find(&attendances)
 
+	// overtime awaiting approval or rejected is listed in the breakdown but not paid
 	var overtimes []models.Overtime
-	tx.Where("user_id = ? AND date BETWEEN ? AND ?", user.ID, payroll.PeriodStart, payroll.PeriodEnd).Find(&overtimes)
+	tx.Preload("Reviewer").Where("user_id = ? AND date BETWEEN ? AND ?", user.ID, employedFrom, employedTo).Order("date").Find(&overtimes)
 
-	var reimbursements []models.Reimbursement
-	tx.Where("user_id = ? AND date BETWEEN ? AND ?", user.ID, payroll.PeriodStart, payroll.PeriodEnd).Find(&reimbursements)
+	// only approved claims are paid, including those approved after their period was processed
+	reimbursements, err := findPayableReimbursements(tx, user.ID, payroll.PeriodEnd)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
+	payBasis := user.PayBasis
+	if payBasis == "" {
+		payBasis = models.PayBasisMonthly
+	}
 
 	daysWorked := len(attendances)
-	// flat 8 hours per days worked instead of using HoursWorked field
+	attended := make(map[string]bool, len(attendances))
+	// salaried employees are paid the hours of the shift per days worked instead of using HoursWorked field
 	// based on this requirements:
 	// No rules for late or early check-ins or check-outs; check-in at any time that day counts.
-	totalHours := float64(daysWorked * 8)
-	totalOvertime := 0.0
+	// Hourly employees are paid the time they worked, less breaks and rounded.
+	workHours := utils.WorkHoursFromEnv()
+	hoursWorked := decimal.Zero
+	attendanceBreakdown := make([]dto.AttendanceBreakdownItem, 0, len(attendances))
+	for _, a := range attendances {
+		attended[a.DateOnlyString()] = true
+		item := dto.AttendanceBreakdownItem{
+			Date:        a.DateOnlyString(),
+			CheckInAt:   a.CheckInAt,
+			CheckOutAt:  a.CheckOutAt,
+			HoursWorked: a.HoursWorked,
+		}
+		if payBasis == models.PayBasisHourly {
+			// a day without a check-out has no hours to pay until it is corrected
+			if a.CheckInAt != nil && a.CheckOutAt != nil {
+				worked := a.CheckOutAt.Sub(*a.CheckInAt)
+				item.BreakHours = decimal.NewFromFloat(workHours.BreakFor(worked).Hours()).Round(4)
+				item.PaidHours = workHours.Paid(worked)
+			}
+		} else if shift := schedule.ShiftOn(a.Date); shift != nil {
+			item.PaidHours = shift.Hours
+		}
+		hoursWorked = hoursWorked.Add(item.PaidHours)
+		attendanceBreakdown = append(attendanceBreakdown, item)
+	}
+	totalHours := hoursWorked.InexactFloat64()
+
+	holidays, err := findHolidayDates(tx, payroll.PeriodStart, payroll.PeriodEnd)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
+	// paid leave is paid like an attended day, unpaid leave is paid and then deducted
+	// so it shows on the payslip
+	leaves, err := findApprovedLeaves(tx, user.ID, employedFrom, employedTo)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	leaveBreakdown := leaveDays(leaves, employedFrom, employedTo, schedule, holidays, attended)
+	paidLeaveDays, unpaidLeaveDays := 0, 0
+	for _, l := range leaveBreakdown {
+		if l.Paid {
+			paidLeaveDays++
+		} else {
+			unpaidLeaveDays++
+		}
+	}
+
+	// overtime is paid by the rules effective on its date, tiered by the hours worked that day
+	ruleSets, err := findOvertimeRuleSets(tx, payroll.PeriodEnd)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	totalOvertime, holidayOvertime := 0.0, 0.0
+	// overtime hours weighted by their multiplier, paid at the hourly rate
+	overtimeUnits := decimal.Zero
+	overtimeBreakdown := make([]dto.OvertimeBreakdownItem, 0, len(overtimes))
+	overtimeItemUnits := make([]decimal.Decimal, 0, len(overtimes))
 	for _, o := range overtimes {
-		totalOvertime += o.HoursWorked
+		holiday := holidays[o.DateOnlyString()]
+		item := dto.OvertimeBreakdownItem{
+			Date:        o.DateOnlyString(),
+			HoursWorked: o.HoursWorked,
+			Holiday:     holiday,
+			Status:      o.Status,
+			ReviewedBy:  o.ReviewedBy,
+			DayType:     overtimeDayType(o.Date, schedule, holidays),
+		}
+		if o.Reviewer != nil {
+			item.Reviewer = o.Reviewer.Username
+		}
+
+		ruleSet, err := overtimeRuleSetOn(ruleSets, o.Date)
+		if err != nil {
+			return models.Payslip{}, err
+		}
+		item.RuleVersion = ruleSet.Version
+		hours := decimal.NewFromFloat(o.HoursWorked)
+		units := decimal.Zero
+		for _, tier := range ruleSet.Tiers(item.DayType, hours) {
+			item.Tiers = append(item.Tiers, dto.OvertimeTierItem{Hours: tier.Hours, Multiplier: tier.Multiplier})
+			units = units.Add(tier.Hours.Mul(tier.Multiplier))
+		}
+		if hours.IsPositive() {
+			item.Multiplier = units.Div(hours).Round(4)
+		}
+
+		if o.Status != models.OvertimeStatusApproved {
+			units = decimal.Zero
+		} else {
+			totalOvertime += o.HoursWorked
+			if holiday {
+				holidayOvertime += o.HoursWorked
+			}
+		}
+		overtimeUnits = overtimeUnits.Add(units)
+		overtimeItemUnits = append(overtimeItemUnits, units)
+		overtimeBreakdown = append(overtimeBreakdown, item)
 	}
 
-	totalReimbursement := 0.0
+	rounding := utils.MoneyRoundingFromEnv()
+
+	totalReimbursement := decimal.Zero
 	for _, r := range reimbursements {
-		totalReimbursement += r.Amount
+		totalReimbursement = totalReimbursement.Add(r.Amount)
+	}
+
+	expectedWorkingDays := schedule.WorkingDays(payroll.PeriodStart, payroll.PeriodEnd, holidays)
+
+	// a raise within the period only applies from its effective date
+	segments, err := findSalarySegments(tx, user, employedFrom, employedTo)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	// the monthly salary, or the hourly or daily rate
+	rate := proratedSalary(segments, schedule, holidays)
+	monthlySalary := rate
+	employedWorkingDays := schedule.WorkingDays(employedFrom, employedTo, holidays)
+
+	kind := models.PayslipKindRegular
+	if user.TerminatedWithin(payroll.PeriodStart, payroll.PeriodEnd) {
+		kind = models.PayslipKindFinalSettlement
+	}
+
+	hourlyRate := decimal.Zero
+	basePay := decimal.Zero
+	unpaidLeave := decimal.Zero
+	salaryBreakdown := make([]dto.SalaryBreakdownItem, 0, len(segments))
+	for _, segment := range segments {
+		attended := daysWorked
+		onLeave, unpaid := len(leaveBreakdown), unpaidLeaveDays
+		if len(segments) > 1 {
+			attended = countAttendancesBetween(attendances, segment.Start, segment.End)
+			onLeave, unpaid = countLeaveDaysBetween(leaveBreakdown, segment.Start, segment.End)
+		}
+
+		amount := decimal.Zero
+		switch payBasis {
+		case models.PayBasisHourly, models.PayBasisDaily:
+			// unpaid leave is simply not paid
+			amount = paidByTheDay(payBasis, segment, attendanceBreakdown, leaveBreakdown, schedule, rounding)
+		default:
+			// a period without working days (or without dates at all) would otherwise divide by zero
+			if expectedWorkingDays > 0 {
+				amount = segment.Salary.Mul(decimal.NewFromInt(int64(attended + onLeave))).Div(decimal.NewFromInt(int64(expectedWorkingDays)))
+				unpaidLeave = unpaidLeave.Add(segment.Salary.Mul(decimal.NewFromInt(int64(unpaid))).Div(decimal.NewFromInt(int64(expectedWorkingDays))))
+			}
+		}
+		basePay = basePay.Add(amount)
+
+		item := dto.SalaryBreakdownItem{
+			Start:               segment.Start.Format("2006-01-02"),
+			End:                 segment.End.Format("2006-01-02"),
+			Salary:              segment.Salary,
+			ExpectedWorkingDays: schedule.WorkingDays(segment.Start, segment.End, holidays),
+			DaysAttended:        attended,
+			LeaveDays:           onLeave,
+			Amount:              rounding.RoundLine(amount),
+		}
+		if !segment.EffectiveFrom.IsZero() {
+			item.EffectiveFrom = segment.EffectiveFrom.Format("2006-01-02")
+		}
+		salaryBreakdown = append(salaryBreakdown, item)
+	}
+	basePay = rounding.RoundLine(basePay)
+
+	// the salary pays the hours of the shifts in the period
+	expectedHours := schedule.WorkingHours(payroll.PeriodStart, payroll.PeriodEnd, holidays)
+	switch payBasis {
+	case models.PayBasisHourly:
+		hourlyRate = rate
+	case models.PayBasisDaily:
+		if expectedHours.IsPositive() {
+			hourlyRate = rate.Mul(decimal.NewFromInt(int64(expectedWorkingDays))).Div(expectedHours)
+		}
+	default:
+		if expectedHours.IsPositive() {
+			hourlyRate = monthlySalary.Div(expectedHours)
+		}
+	}
+	// what hourly and daily employees earn in the period stands in for the monthly salary
+	// in pay components and BPJS
+	if payBasis != models.PayBasisMonthly {
+		monthlySalary = basePay
+	}
+	// the rate of the first overtime hour on a working day and on a holiday, for reference
+	overtimeRatePerHour, holidayOvertimeRatePerHour := decimal.Zero, decimal.Zero
+	if ruleSet, err := overtimeRuleSetOn(ruleSets, payroll.PeriodEnd); err == nil {
+		overtimeRatePerHour = hourlyRate.Mul(ruleSet.FirstMultiplier(models.OvertimeDayWeekday))
+		holidayOvertimeRatePerHour = hourlyRate.Mul(ruleSet.FirstMultiplier(models.OvertimeDayHoliday))
+	}
+	for i := range overtimeBreakdown {
+		overtimeBreakdown[i].Amount = rounding.RoundLine(hourlyRate.Mul(overtimeItemUnits[i]))
 	}
 
-	expectedWorkingDays := utils.CountWeekdays(payroll.PeriodStart, payroll.PeriodEnd)
-	// flat 8 hours per days worked
-	hourlyRate := user.Salary / float64(expectedWorkingDays) / 8
-	overtimeRatePerHour := hourlyRate * 2
-	proratedDaysWorked := float64(daysWorked) / float64(expectedWorkingDays)
+	overtimePay := rounding.RoundLine(hourlyRate.Mul(overtimeUnits))
 
-	basePay := proratedDaysWorked * user.Salary
-	overtimePay := totalOvertime * hourlyRate * 2
-	totalPay := basePay + overtimePay + totalReimbursement
+	components, err := computePayComponents(tx, user, payroll, map[string]decimal.Decimal{
+		"monthly_salary":        monthlySalary,
+		"base_salary":           basePay,
+		"hourly_rate":           hourlyRate,
+		"days_attended":         decimal.NewFromInt(int64(daysWorked)),
+		"expected_working_days": decimal.NewFromInt(int64(expectedWorkingDays)),
+		"overtime_hours":        decimal.NewFromFloat(totalOvertime),
+		"overtime_pay":          overtimePay,
+	}, rounding)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	if unpaidLeaveDays > 0 && payBasis == models.PayBasisMonthly {
+		components = append([]models.PayslipLine{{
+			Code:    "unpaid_leave",
+			Name:    fmt.Sprintf("Unpaid Leave (%d days)", unpaidLeaveDays),
+			Type:    models.PayComponentTypeDeduction,
+			Amount:  rounding.RoundLine(unpaidLeave),
+			Taxable: true,
+		}}, components...)
+	}
+
+	otherEarnings, otherDeductions := decimal.Zero, decimal.Zero
+	taxableComponents := decimal.Zero
+	for _, line := range components {
+		if line.Type == models.PayComponentTypeDeduction {
+			otherDeductions = otherDeductions.Add(line.Amount)
+			if line.Taxable {
+				taxableComponents = taxableComponents.Sub(line.Amount)
+			}
+			continue
+		}
+		otherEarnings = otherEarnings.Add(line.Amount)
+		if line.Taxable {
+			taxableComponents = taxableComponents.Add(line.Amount)
+		}
+	}
+
+	totalPay := rounding.Round(basePay.Add(overtimePay).Add(otherEarnings).Add(totalReimbursement))
+
+	bpjs, err := computeBPJS(tx, payroll, monthlySalary, rounding)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
+	// reimbursements are not income, employer-paid insurance premiums are
+	taxableIncome := basePay.Add(overtimePay).Add(taxableComponents).Add(bpjs.EmployerTaxable)
+	if taxableIncome.IsNegative() {
+		taxableIncome = decimal.Zero
+	}
+	tax, err := withholdIncomeTax(tx, user, payroll, taxableIncome, bpjs.TaxDeductible)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+	taxAmount := rounding.RoundLine(tax.Amount)
+	netPay := rounding.Round(totalPay.Sub(otherDeductions).Sub(taxAmount).Sub(bpjs.Employee))
+
+	lines := payslipLines(basePay, overtimePay, totalReimbursement, components, taxAmount, bpjs)
 
 	payslip := models.Payslip{
 		Month:     payroll.Month,
 		Year:      payroll.Year,
+		Kind:      kind,
+		PayBasis:  payBasis,
 		UserID:    user.ID,
 		PayrollID: payroll.ID,
+		Version:   payroll.Version,
 
 		// summary totals
 		BaseSalary:    basePay,
@@ -247,19 +715,47 @@ func GeneratePayslip(tx *gorm.DB, adminID uint, user models.User, payroll *model
 		Reimbursement: totalReimbursement,
 		TotalSalary:   totalPay,
 
+		// pay components
+		OtherEarnings:   otherEarnings,
+		OtherDeductions: otherDeductions,
+		Lines:           lines,
+
+		// PPh 21 withholding
+		TaxableIncome:   taxableIncome,
+		TaxRate:         tax.Rate,
+		Tax:             taxAmount,
+		NetSalary:       netPay,
+		TaxMethod:       tax.Method,
+		TaxTableVersion: tax.TableVersion,
+		PTKPStatus:      tax.PTKPStatus,
+
+		// BPJS contributions
+		BPJSEmployee:      bpjs.Employee,
+		BPJSEmployer:      bpjs.Employer,
+		BPJSTaxDeductible: bpjs.TaxDeductible,
+		BPJSBreakdown:     toJSON(bpjs.Breakdown),
+
 		// calculation context
-		MonthlySalary:       user.Salary,
+		MonthlySalary:       monthlySalary,
 		ExpectedWorkingDays: expectedWorkingDays,
+		EmployedWorkingDays: employedWorkingDays,
 		DaysAttended:        daysWorked,
+		PaidLeaveDays:       paidLeaveDays,
+		UnpaidLeaveDays:     unpaidLeaveDays,
 		HourlyRate:          hourlyRate,
 		OvertimeRatePerHour: overtimeRatePerHour,
 
+		HolidayOvertimeRatePerHour: holidayOvertimeRatePerHour,
+
 		// breakdowns
 		TotalHoursWorked:       totalHours,
 		TotalOvertimeHours:     totalOvertime,
-		AttendanceBreakdown:    toJSON(attendances),
-		OvertimeBreakdown:      toJSON(overtimes),
-		ReimbursementBreakdown: toJSON(reimbursements),
+		HolidayOvertimeHours:   holidayOvertime,
+		SalaryBreakdown:        toJSON(salaryBreakdown),
+		AttendanceBreakdown:    toJSON(attendanceBreakdown),
+		OvertimeBreakdown:      toJSON(overtimeBreakdown),
+		LeaveBreakdown:         toJSON(leaveBreakdown),
+		ReimbursementBreakdown: toJSON(toReimbursementBreakdown(reimbursements)),
 
 		CreatedBy: adminID,
 	}
@@ -267,14 +763,365 @@ func GeneratePayslip(tx *gorm.DB, adminID uint, user models.User, payroll *model
 	return payslip, nil
 }
 
+// countAttendancesBetween counts the attendances dated from start to end, inclusive.
+func countAttendancesBetween(attendances []models.Attendance, start, end time.Time) int {
+	count := 0
+	for _, a := range attendances {
+		if !a.Date.Before(start) && a.Date.Before(end.AddDate(0, 0, 1)) {
+			count++
+		}
+	}
+	return count
+}
+
+// paidByTheDay pays an hourly or daily rate for the days of the segment: the paid hours or the day
+// of every day attended, and the shift's hours or the day of every paid leave day. Each attended day
+// is given its amount.
+func paidByTheDay(payBasis string, segment salarySegment, days []dto.AttendanceBreakdownItem, leaves []dto.LeaveBreakdownItem, schedule models.Schedule, rounding utils.MoneyRounding) decimal.Decimal {
+	from, to := segment.Start.Format("2006-01-02"), segment.End.Format("2006-01-02")
+	total := decimal.Zero
+	for i := range days {
+		if days[i].Date < from || days[i].Date > to {
+			continue
+		}
+		amount := segment.Salary
+		if payBasis == models.PayBasisHourly {
+			amount = segment.Salary.Mul(days[i].PaidHours)
+		}
+		days[i].Amount = rounding.RoundLine(amount)
+		total = total.Add(amount)
+	}
+	for _, l := range leaves {
+		if !l.Paid || l.Date < from || l.Date > to {
+			continue
+		}
+		if payBasis == models.PayBasisDaily {
+			total = total.Add(segment.Salary)
+			continue
+		}
+		date, err := time.Parse("2006-01-02", l.Date)
+		if err != nil {
+			continue
+		}
+		if shift := schedule.ShiftOn(date); shift != nil {
+			total = total.Add(segment.Salary.Mul(shift.Hours))
+		}
+	}
+	return total
+}
+
+// countLeaveDaysBetween counts the leave days dated from start to end, inclusive, and how many of them are unpaid.
+func countLeaveDaysBetween(days []dto.LeaveBreakdownItem, start, end time.Time) (total int, unpaid int) {
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+	for _, d := range days {
+		if d.Date >= from && d.Date <= to {
+			total++
+			if !d.Paid {
+				unpaid++
+			}
+		}
+	}
+	return total, unpaid
+}
+
+// payslipLines itemizes a payslip: earnings first, then deductions.
+func payslipLines(basePay, overtimePay, reimbursement decimal.Decimal, components []models.PayslipLine, tax decimal.Decimal, bpjs bpjsContributions) []models.PayslipLine {
+	lines := []models.PayslipLine{
+		{Code: "base_salary", Name: "Base Salary", Type: models.PayComponentTypeEarning, Amount: basePay, Taxable: true},
+	}
+	if !overtimePay.IsZero() {
+		lines = append(lines, models.PayslipLine{Code: "overtime", Name: "Overtime", Type: models.PayComponentTypeEarning, Amount: overtimePay, Taxable: true})
+	}
+	if !reimbursement.IsZero() {
+		lines = append(lines, models.PayslipLine{Code: "reimbursement", Name: "Reimbursement", Type: models.PayComponentTypeEarning, Amount: reimbursement})
+	}
+	for _, line := range components {
+		if line.Type == models.PayComponentTypeEarning {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, line := range components {
+		if line.Type == models.PayComponentTypeDeduction {
+			lines = append(lines, line)
+		}
+	}
+	if !tax.IsZero() {
+		lines = append(lines, models.PayslipLine{Code: "pph21", Name: "PPh 21", Type: models.PayComponentTypeDeduction, Amount: tax})
+	}
+	for _, b := range bpjs.Breakdown {
+		if b.EmployeeAmount.IsZero() {
+			continue
+		}
+		lines = append(lines, models.PayslipLine{
+			Code:   "bpjs_" + b.Program,
+			Name:   bpjsProgramNames[b.Program],
+			Type:   models.PayComponentTypeDeduction,
+			Amount: b.EmployeeAmount,
+		})
+	}
+
+	for i := range lines {
+		lines[i].Sequence = i + 1
+	}
+	return lines
+}
+
+// checkPayrollNotLocked returns errPayrollLocked when a payroll overlapping start to end
+// is being run or processed, its payslips must not change underneath it.
+func checkPayrollNotLocked(tx *gorm.DB, start, end time.Time) error {
+	var count int64
+	if err := tx.Model(&models.Payroll{}).
+		Where("status IN ?", []string{models.PayrollStatusPending, models.PayrollStatusProcessed}).
+		Where("period_start <= ? AND period_end >= ?", end, start).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errPayrollLocked
+	}
+	return nil
+}
+
+// checkPayrollRates makes sure the rate tables the payslips of the period are computed with exist,
+// so such a payroll is rejected when it is run instead of failing in the worker. A period without
+// an end date is reported by the payroll period warnings instead.
+func checkPayrollRates(tx *gorm.DB, payroll models.Payroll) error {
+	if payroll.PeriodEnd.IsZero() {
+		return nil
+	}
+	if _, err := findTaxTable(tx, payroll.PeriodEnd); err != nil {
+		return err
+	}
+	if _, err := findBPJSRateTable(tx, payroll.PeriodEnd); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findPayrollEmployees returns the users that get a payslip when a payroll is run:
+// everyone but inactive users, employed for at least part of the period.
+func findPayrollEmployees(tx *gorm.DB, payroll models.Payroll) ([]models.User, error) {
+	var users []models.User
+	if err := tx.
+		Where("employment_status <> ?", models.EmploymentStatusInactive).
+		Where("hire_date IS NULL OR hire_date <= ?", payroll.PeriodEnd).
+		Where("termination_date IS NULL OR termination_date >= ?", payroll.PeriodStart).
+		Order("id").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// PreviewPayroll godoc
+// @Summary      Preview payroll
+// @Description  Computes the payslips the payroll would produce for every employee without persisting anything.
+// @Description  Includes the calculation context per employee and warnings about the period or employee data.
+// @Tags         Payroll
+// @Security     BearerAuth
+// @Produce      json
+// @Param        year   path      int  true  "Year"
+// @Param        month  path      int  true  "Month"
+// @Success      200    {object}  dto.SuccessResponse[dto.PayrollPreviewResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /payrolls/{year}/{month}/preview [get]
+func PreviewPayroll(c *gin.Context) {
+	year, err1 := strconv.Atoi(c.Param("year"))
+	month, err2 := strconv.Atoi(c.Param("month"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year or month"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var payroll models.Payroll
+
+	if err := db.DB.Where("year = ? AND month = ?", year, month).First(&payroll).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll record not found"})
+		return
+	}
+
+	// the calculation runs inside a transaction that is always rolled back,
+	// so nothing it touches is ever persisted
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to preview payroll"})
+		return
+	}
+	defer tx.Rollback()
+
+	users, err := findPayrollEmployees(tx, payroll)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch employees"})
+		return
+	}
+	holidays, err := findHolidayDates(tx, payroll.PeriodStart, payroll.PeriodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch holidays"})
+		return
+	}
+
+	preview := dto.PayrollPreviewResponse{
+		PayrollID: payroll.ID,
+		Year:      payroll.Year,
+		Month:     payroll.Month,
+		Status:    payroll.Status,
+		Warnings:  payrollPeriodWarnings(payroll, holidays),
+		Payslips:  make([]dto.EmployeePayslipPreview, 0),
+	}
+	openAttendances, err := countOpenAttendances(tx, payroll.PeriodStart, payroll.PeriodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count open attendances"})
+		return
+	}
+	if openAttendances > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d attendances in the period have no check-out", openAttendances))
+	}
+	// the payroll could not be run, there is nothing to preview
+	if err := checkPayrollRates(tx, payroll); errors.Is(err, errRateTableMissing) {
+		preview.Warnings = append(preview.Warnings, err.Error())
+		c.JSON(http.StatusOK, utils.WrapSuccessResponse(preview))
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to preview payroll"})
+		return
+	}
+
+	for _, user := range users {
+		p, err := GeneratePayslip(tx, userID, user, &payroll)
+		if err != nil {
+			// the same error would fail the payroll run, so report it instead of the payslip
+			preview.Warnings = appendUnique(preview.Warnings, err.Error())
+			continue
+		}
+		bpjsBreakdown, err := parseBreakdown[dto.BPJSBreakdownItem](p.BPJSBreakdown)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate payslip preview"})
+			return
+		}
+		salaryBreakdown, err := parseBreakdown[dto.SalaryBreakdownItem](p.SalaryBreakdown)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate payslip preview"})
+			return
+		}
+		attendanceBreakdown, err := parseBreakdown[dto.AttendanceBreakdownItem](p.AttendanceBreakdown)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate payslip preview"})
+			return
+		}
+		lines := make([]dto.PayslipLineItem, 0, len(p.Lines))
+		for _, line := range p.Lines {
+			lines = append(lines, toPayslipLineItem(line))
+		}
+
+		preview.TotalSalaries = preview.TotalSalaries.Add(p.TotalSalary)
+		preview.TotalTax = preview.TotalTax.Add(p.Tax)
+		preview.TotalNetPay = preview.TotalNetPay.Add(p.NetSalary)
+		preview.TotalBPJSEmployee = preview.TotalBPJSEmployee.Add(p.BPJSEmployee)
+		preview.TotalBPJSEmployer = preview.TotalBPJSEmployer.Add(p.BPJSEmployer)
+		preview.TotalEmployerCost = preview.TotalEmployerCost.Add(p.TotalSalary).Add(p.BPJSEmployer)
+		preview.Payslips = append(preview.Payslips, dto.EmployeePayslipPreview{
+			EmployeePayslipBrief: toEmployeePayslipBrief(p, user.Username),
+			TaxableIncome:        p.TaxableIncome,
+			TaxRate:              p.TaxRate,
+			TaxMethod:            p.TaxMethod,
+			PTKPStatus:           p.PTKPStatus,
+			SalaryBreakdown:      salaryBreakdown,
+			BPJSBreakdown:        bpjsBreakdown,
+			Lines:                lines,
+			MonthlySalary:        p.MonthlySalary,
+			ExpectedWorkingDays:  p.ExpectedWorkingDays,
+			EmployedWorkingDays:  p.EmployedWorkingDays,
+			DaysAttended:         p.DaysAttended,
+			PaidLeaveDays:        p.PaidLeaveDays,
+			UnpaidLeaveDays:      p.UnpaidLeaveDays,
+			HourlyRate:           p.HourlyRate,
+			OvertimeRatePerHour:  p.OvertimeRatePerHour,
+			TotalHoursWorked:     p.TotalHoursWorked,
+			TotalOvertimeHours:   p.TotalOvertimeHours,
+
+			HolidayOvertimeRatePerHour: p.HolidayOvertimeRatePerHour,
+			HolidayOvertimeHours:       p.HolidayOvertimeHours,
+			AttendanceBreakdown:        attendanceBreakdown,
+			Warnings:                   payslipWarnings(p),
+		})
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(preview))
+}
+
+func payrollPeriodWarnings(payroll models.Payroll, holidays map[string]bool) []string {
+	warnings := make([]string, 0)
+
+	if payroll.PeriodStart.IsZero() {
+		warnings = append(warnings, "payroll period start date is not set")
+	}
+	if payroll.PeriodEnd.IsZero() {
+		warnings = append(warnings, "payroll period end date is not set")
+	}
+	if !payroll.PeriodStart.IsZero() && !payroll.PeriodEnd.IsZero() && payroll.PeriodStart.After(payroll.PeriodEnd) {
+		warnings = append(warnings, "payroll period start date is after the end date")
+	}
+	if utils.CountWorkingDays(payroll.PeriodStart, payroll.PeriodEnd, holidays) == 0 {
+		warnings = append(warnings, "payroll period has zero expected working days")
+	}
+
+	return warnings
+}
+
+func payslipWarnings(payslip models.Payslip) []string {
+	warnings := make([]string, 0)
+
+	if !payslip.MonthlySalary.IsPositive() {
+		warnings = append(warnings, "employee has no salary configured")
+	}
+	if payslip.ExpectedWorkingDays == 0 {
+		warnings = append(warnings, "zero expected working days, base salary and hourly rate are 0")
+	}
+	if payslip.Kind == models.PayslipKindFinalSettlement {
+		warnings = append(warnings, "final settlement, employment ends within the period")
+	}
+	if payslip.EmployedWorkingDays < payslip.ExpectedWorkingDays {
+		warnings = append(warnings, fmt.Sprintf("employed for %d of %d working days, base salary is prorated", payslip.EmployedWorkingDays, payslip.ExpectedWorkingDays))
+	}
+	if payslip.DaysAttended == 0 && payslip.PaidLeaveDays == 0 && payslip.UnpaidLeaveDays == 0 {
+		warnings = append(warnings, "no attendance recorded in the period")
+	}
+	if overtimes, err := parseBreakdown[dto.OvertimeBreakdownItem](payslip.OvertimeBreakdown); err == nil {
+		awaiting := 0
+		for _, o := range overtimes {
+			if o.Status == models.OvertimeStatusSubmitted {
+				awaiting++
+			}
+		}
+		if awaiting > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d overtime submissions awaiting approval are not paid", awaiting))
+		}
+	}
+	if payslip.UnpaidLeaveDays > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d days of unpaid leave are deducted", payslip.UnpaidLeaveDays))
+	}
+	if payslip.ExpectedWorkingDays > 0 && payslip.DaysAttended+payslip.PaidLeaveDays+payslip.UnpaidLeaveDays > payslip.ExpectedWorkingDays {
+		warnings = append(warnings, "days attended exceed expected working days")
+	}
+
+	return warnings
+}
+
 // GetPayrollSummary godoc
 // @Summary      Get payroll summary
 // @Description  Generates a summary of all employee payslips for a given month and year.
+// @Description  For failed payrolls the summary contains no payslips but reports the failure reason.
 // @Tags         Payroll
 // @Security     BearerAuth
 // @Produce      json
 // @Param        year   path      int  true  "Year"
 // @Param        month  path      int  true  "Month"
+// @Param        version query    int  false "Payslip version (defaults to the current payslips)"
 // @Success      200    {object}  dto.SuccessResponse[dto.PayrollSummaryResponse]
 // @Failure      400    {object}  dto.ErrorResponse
 // @Failure      401    {object}  dto.ErrorResponse
@@ -296,41 +1143,110 @@ func GeneratePayrollSummary(c *gin.Context) {
 		return
 	}
 
+	var summary dto.PayrollSummaryResponse
+	summary.PayrollID = payroll.ID
+	summary.Year = payroll.Year
+	summary.Month = payroll.Month
+	summary.Status = payroll.Status
+	summary.Attempts = payroll.Attempts
+	summary.FailureReason = payroll.FailureReason
+	summary.FailedAt = payroll.FailedAt
+	summary.Version = payroll.Version
+	summary.OpenAttendances = payroll.OpenAttendances
+	summary.Payslips = make([]dto.EmployeePayslipBrief, 0)
+
+	// a failed payroll has no payslips, but the summary still reports why it failed
+	if payroll.Status == models.PayrollStatusFailed {
+		c.JSON(http.StatusOK, utils.WrapSuccessResponse(summary))
+		return
+	}
+
 	if payroll.Status != models.PayrollStatusProcessed {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "payroll has not been processed"})
 		return
 	}
 
+	// defaults to the current payslips, older versions stay available for audit
+	query := db.DB.Preload("User").Where("payroll_id = ?", payroll.ID)
+	if v := c.Query("version"); v != "" {
+		version, err := strconv.Atoi(v)
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+		query = query.Where("version = ?", version)
+		summary.Version = version
+	} else {
+		query = query.Where("superseded_at IS NULL")
+	}
+
 	var payslips []models.Payslip
-	if err := db.DB.
-		Preload("User").
-		Where("payroll_id = ?", payroll.ID).
-		Find(&payslips).Error; err != nil {
+	if err := query.Find(&payslips).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payslips"})
 		return
 	}
 
-	var summary dto.PayrollSummaryResponse
-	summary.PayrollID = payroll.ID
-	summary.Year = payroll.Year
-	summary.Month = payroll.Month
-	summary.Payslips = make([]dto.EmployeePayslipBrief, 0)
-
 	for _, p := range payslips {
-		summary.TotalSalaries += p.TotalSalary
-		summary.Payslips = append(summary.Payslips, dto.EmployeePayslipBrief{
-			UserID:        p.UserID,
-			Username:      p.User.Username,
-			BaseSalary:    p.BaseSalary,
-			OvertimePay:   p.OvertimePay,
-			Reimbursement: p.Reimbursement,
-			TotalPay:      p.TotalSalary,
-		})
+		summary.TotalSalaries = summary.TotalSalaries.Add(p.TotalSalary)
+		summary.TotalTax = summary.TotalTax.Add(p.Tax)
+		summary.TotalNetPay = summary.TotalNetPay.Add(p.NetSalary)
+		summary.TotalBPJSEmployee = summary.TotalBPJSEmployee.Add(p.BPJSEmployee)
+		summary.TotalBPJSEmployer = summary.TotalBPJSEmployer.Add(p.BPJSEmployer)
+		summary.TotalEmployerCost = summary.TotalEmployerCost.Add(p.TotalSalary).Add(p.BPJSEmployer)
+		summary.Payslips = append(summary.Payslips, toEmployeePayslipBrief(p, p.User.Username))
 	}
 
 	c.JSON(http.StatusOK, utils.WrapSuccessResponse(summary))
 }
 
+func toPayrollResponse(payroll models.Payroll) dto.PayrollResponse {
+	return dto.PayrollResponse{
+		ID:            payroll.ID,
+		Name:          payroll.Name,
+		PeriodStart:   &payroll.PeriodStart,
+		PeriodEnd:     &payroll.PeriodEnd,
+		Status:        payroll.Status,
+		Attempts:      payroll.Attempts,
+		LastError:     payroll.LastError,
+		FailureReason: payroll.FailureReason,
+		FailedAt:      payroll.FailedAt,
+		Version:       payroll.Version,
+		ReopenedAt:    payroll.ReopenedAt,
+		ReopenReason:  payroll.ReopenReason,
+
+		OpenAttendances: payroll.OpenAttendances,
+	}
+}
+
+func toEmployeePayslipBrief(p models.Payslip, username string) dto.EmployeePayslipBrief {
+	return dto.EmployeePayslipBrief{
+		UserID:          p.UserID,
+		Username:        username,
+		Kind:            p.Kind,
+		PayBasis:        p.PayBasis,
+		BaseSalary:      p.BaseSalary,
+		OvertimePay:     p.OvertimePay,
+		Reimbursement:   p.Reimbursement,
+		OtherEarnings:   p.OtherEarnings,
+		TotalPay:        p.TotalSalary,
+		OtherDeductions: p.OtherDeductions,
+		Tax:             p.Tax,
+		BPJSEmployee:    p.BPJSEmployee,
+		NetPay:          p.NetSalary,
+		BPJSEmployer:    p.BPJSEmployer,
+		EmployerCost:    p.TotalSalary.Add(p.BPJSEmployer),
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
 func toJSON[T any](v T) string {
 	b, err := json.Marshal(v)
 	if err != nil {
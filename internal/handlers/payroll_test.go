@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -31,6 +32,10 @@ func setupTestRouterForPayroll() *gin.Engine {
 	r := gin.Default()
 	r.POST("/payrolls/:year/:month", AuthStubMiddlewareForPayroll(), handlers.UpsertPayroll)
 	r.POST("/payrolls/:year/:month/run", AuthStubMiddlewareForPayroll(), handlers.RunPayroll)
+	r.POST("/payrolls/:year/:month/retry", AuthStubMiddlewareForPayroll(), handlers.RetryPayroll)
+	r.GET("/payrolls/:year/:month/summary", AuthStubMiddlewareForPayroll(), handlers.GeneratePayrollSummary)
+	r.GET("/payrolls/:year/:month/preview", AuthStubMiddlewareForPayroll(), handlers.PreviewPayroll)
+	r.POST("/payrolls/:year/:month/reopen", AuthStubMiddlewareForPayroll(), handlers.ReopenPayroll)
 	return r
 }
 
@@ -46,14 +51,16 @@ func setupTestDBForPayroll() (*gorm.DB, func(), error) {
 		Username: "adminuser",
 		Password: password,
 		RoleID:   1, // admin
-		Salary:   2200000,
+		Salary:   decimal.NewFromInt(2200000),
+		// admin accounts are not on the payroll
+		EmploymentStatus: models.EmploymentStatusInactive,
 	}
 	employee := models.User{
 		ID:       2,
 		Username: "employee",
 		Password: password,
 		RoleID:   2, // employee
-		Salary:   2200000,
+		Salary:   decimal.NewFromInt(2200000),
 	}
 	d.Create(&admin)
 	d.Create(&employee)
@@ -93,6 +100,28 @@ func TestUpsertPayroll_Success(t *testing.T) {
 	assert.Equal(t, "draft", resp.Data.Status)
 }
 
+func TestUpsertPayroll_PeriodInCompanyTimezone(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	// a bare date is taken as is, an instant as the day it falls on in Jakarta: 20:00 UTC is 03:00 the next day
+	w := postJSON(r, http.MethodPost, "/payrolls/2025/6", gin.H{
+		"period_start": "2025-06-01T00:00:00+07:00",
+		"period_end":   "2025-06-29T20:00:00Z",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var payroll models.Payroll
+	d.Where("month = ? AND year = ?", 6, 2025).First(&payroll)
+	assert.True(t, payroll.PeriodStart.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)), "period start %s", payroll.PeriodStart)
+	assert.True(t, payroll.PeriodEnd.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)), "period end %s", payroll.PeriodEnd)
+}
+
 func TestRunPayroll_Success(t *testing.T) {
 	r := setupTestRouterForPayroll()
 	d, cleanup, err := setupTestDBForPayroll()
@@ -125,12 +154,14 @@ func TestRunPayroll_Success(t *testing.T) {
 		UserID:      2,
 		Date:        time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
 		HoursWorked: 2,
+		Status:      models.OvertimeStatusApproved,
 		CreatedBy:   1,
 	}
 	reimbursement := models.Reimbursement{
 		UserID:    2,
-		Amount:    100000,
+		Amount:    decimal.NewFromInt(100000),
 		Date:      time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+		Status:    models.ReimbursementStatusApproved,
 		CreatedBy: 1,
 	}
 	d.Create(&attendance)
@@ -147,6 +178,40 @@ func TestRunPayroll_Success(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Nil(t, err)
 	assert.Equal(t, "pending", resp.Data.Status)
+
+	var job models.PayrollJob
+	err = d.Where("payroll_id = ?", payroll.ID).First(&job).Error
+	assert.Nil(t, err)
+	assert.Equal(t, models.PayrollJobStatusQueued, job.Status)
+	assert.Equal(t, uint(1), job.CreatedBy)
+}
+
+func TestUpsertPayroll_NotDraft(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	pending := models.Payroll{Month: 6, Year: 2025, Status: models.PayrollStatusPending, PeriodEnd: end}
+	failed := models.Payroll{Month: 7, Year: 2025, Status: models.PayrollStatusFailed}
+	d.Create(&pending)
+	d.Create(&failed)
+
+	w := postJSON(r, http.MethodPost, "/payrolls/2025/6", gin.H{"period_end": "2025-06-27T00:00:00Z"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "only draft or failed payrolls can be updated")
+
+	var current models.Payroll
+	d.First(&current, pending.ID)
+	assert.True(t, current.PeriodEnd.Equal(end))
+
+	// a failed payroll can still be corrected before it is retried
+	w = postJSON(r, http.MethodPost, "/payrolls/2025/7", gin.H{"name": "July Payroll"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "July Payroll")
 }
 
 func TestUpsertPayroll_InvalidYear(t *testing.T) {
@@ -278,6 +343,394 @@ func TestRunPayroll_PendingState(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "currently being processed")
 }
 
+func TestRunPayroll_FailedState(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:  6,
+		Year:   2025,
+		Status: models.PayrollStatusFailed,
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/run", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "use the retry endpoint")
+}
+
+func TestRetryPayroll_Success(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	failedAt := time.Now()
+	payroll := models.Payroll{
+		Month:         6,
+		Year:          2025,
+		Status:        models.PayrollStatusFailed,
+		Attempts:      5,
+		LastError:     "connection reset",
+		FailureReason: "connection reset",
+		FailedAt:      &failedAt,
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/retry", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, models.PayrollStatusPending, resp.Data.Status)
+	assert.Equal(t, 5, resp.Data.Attempts)
+	assert.Empty(t, resp.Data.FailureReason)
+	assert.Nil(t, resp.Data.FailedAt)
+
+	var count int64
+	d.Model(&models.PayrollJob{}).Where("payroll_id = ? AND status = ?", payroll.ID, models.PayrollJobStatusQueued).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRetryPayroll_NotFailed(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:  6,
+		Year:   2025,
+		Status: models.PayrollStatusProcessed,
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/retry", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "only failed payrolls can be retried")
+}
+
+func TestRunPayroll_NoTaxTable(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// the earliest tax table is effective from 2024
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2023,
+		PeriodStart: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2023/6/preview", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var preview dto.SuccessResponse[dto.PayrollPreviewResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Contains(t, preview.Data.Warnings, "missing rate table: no tax table effective on 2023-06-30")
+	assert.Empty(t, preview.Data.Payslips)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/payrolls/2023/6/run", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "no tax table effective on 2023-06-30")
+
+	var current models.Payroll
+	d.First(&current, payroll.ID)
+	assert.Equal(t, models.PayrollStatusDraft, current.Status)
+	var count int64
+	d.Model(&models.PayrollJob{}).Where("payroll_id = ?", payroll.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestRetryPayroll_NoBPJSRateTable(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	// the seeded rate tables cover every period the tax tables do, from January 2024
+	payroll := models.Payroll{
+		Month:       1,
+		Year:        2024,
+		Status:      models.PayrollStatusFailed,
+		PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+
+	preview := func() dto.PayrollPreviewResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/payrolls/2024/1/preview", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+	assert.NotContains(t, preview().Warnings, "missing rate table: no BPJS rate table effective on 2024-01-31")
+
+	d.Exec("DELETE FROM bpjs_rates")
+	d.Exec("DELETE FROM bpjs_rate_tables")
+
+	missing := preview()
+	assert.Contains(t, missing.Warnings, "missing rate table: no BPJS rate table effective on 2024-01-31")
+	assert.Empty(t, missing.Payslips)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2024/1/retry", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "no BPJS rate table effective on 2024-01-31")
+
+	var count int64
+	d.Model(&models.PayrollJob{}).Where("payroll_id = ?", payroll.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestGeneratePayrollSummary_Failed(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	failedAt := time.Now()
+	payroll := models.Payroll{
+		Month:         6,
+		Year:          2025,
+		Status:        models.PayrollStatusFailed,
+		Attempts:      5,
+		FailureReason: "connection reset",
+		FailedAt:      &failedAt,
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2025/6/summary", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollSummaryResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, models.PayrollStatusFailed, resp.Data.Status)
+	assert.Equal(t, "connection reset", resp.Data.FailureReason)
+	assert.NotNil(t, resp.Data.FailedAt)
+	assert.Empty(t, resp.Data.Payslips)
+}
+
+func TestPreviewPayroll_Success(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+
+	attendance := models.Attendance{
+		UserID:     2,
+		Date:       time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+		CheckInAt:  timePtr(time.Date(2025, 6, 5, 9, 0, 0, 0, time.UTC)),
+		CheckOutAt: timePtr(time.Date(2025, 6, 5, 17, 0, 0, 0, time.UTC)),
+	}
+	d.Create(&attendance)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Data.Warnings)
+	// the inactive admin gets no payslip
+	assert.Len(t, resp.Data.Payslips, 1)
+
+	for _, p := range resp.Data.Payslips {
+		assert.Equal(t, 21, p.ExpectedWorkingDays)
+		if p.UserID == 2 {
+			assert.Equal(t, 1, p.DaysAttended)
+			// 2,200,000 * 1 / 21, rounded half-up to 2 decimals
+			assert.Equal(t, "104761.9", p.BaseSalary.String())
+			// below the TER category A threshold, so nothing is withheld
+			assert.Equal(t, "TK/0", p.PTKPStatus)
+			assert.Equal(t, models.TaxMethodTER, p.TaxMethod)
+			assert.True(t, p.Tax.IsZero())
+			// BPJS on the monthly salary: JHT 2% + JP 1% + Kesehatan 1% of 2,200,000
+			assert.Equal(t, "88000", p.BPJSEmployee.String())
+			// JHT 3.7% + JP 2% + JKK 0.24% + JKM 0.3% + Kesehatan 4%
+			assert.Equal(t, "225280", p.BPJSEmployer.String())
+			assert.Len(t, p.BPJSBreakdown, 5)
+			assert.Equal(t, "16761.9", p.NetPay.String())
+			assert.Equal(t, "330041.9", p.EmployerCost.String())
+		}
+	}
+
+	// nothing is persisted
+	var count int64
+	d.Model(&models.Payslip{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	d.First(&payroll, payroll.ID)
+	assert.Equal(t, models.PayrollStatusDraft, payroll.Status)
+}
+
+func TestPreviewPayroll_MissingPeriodDates(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month: 6,
+		Year:  2025,
+	}
+	d.Create(&payroll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Contains(t, resp.Data.Warnings, "payroll period start date is not set")
+	assert.Contains(t, resp.Data.Warnings, "payroll period end date is not set")
+}
+
+func TestReopenPayroll_Success(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:  6,
+		Year:   2025,
+		Status: models.PayrollStatusProcessed,
+	}
+	d.Create(&payroll)
+
+	payslip := models.Payslip{
+		PayrollID:              payroll.ID,
+		UserID:                 2,
+		Month:                  6,
+		Year:                   2025,
+		AttendanceBreakdown:    "[]",
+		OvertimeBreakdown:      "[]",
+		ReimbursementBreakdown: "[]",
+	}
+	d.Create(&payslip)
+
+	body := []byte(`{"reason": "wrong attendance for employee"}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/reopen", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, models.PayrollStatusDraft, resp.Data.Status)
+	assert.Equal(t, 2, resp.Data.Version)
+	assert.Equal(t, "wrong attendance for employee", resp.Data.ReopenReason)
+
+	// the payslip is kept, but superseded
+	d.First(&payslip, payslip.ID)
+	assert.NotNil(t, payslip.SupersededAt)
+	assert.Equal(t, uint(1), payslip.SupersededBy)
+}
+
+func TestReopenPayroll_NotProcessed(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	d, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month: 6,
+		Year:  2025,
+	}
+	d.Create(&payroll)
+
+	body := []byte(`{"reason": "wrong attendance"}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/reopen", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "only processed payrolls can be reopened")
+}
+
+func TestReopenPayroll_MissingReason(t *testing.T) {
+	r := setupTestRouterForPayroll()
+	_, cleanup, err := setupTestDBForPayroll()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	body := []byte(`{}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/payrolls/2025/6/reopen", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "error")
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,11 +12,64 @@ import (
 	"dealls-case-study/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// ListPayslips godoc
+// @Summary      List payslips of current user
+// @Description  Lists the current version of every payslip of the current user with its period and totals,
+// @Description  latest period first unless sorted otherwise.
+// @Tags         Payslip
+// @Security     BearerAuth
+// @Produce      json
+// @Param        start     query     string  false  "First period end, e.g. 2025-01-01"
+// @Param        end       query     string  false  "Last period end, e.g. 2025-12-31"
+// @Param        page      query     int     false  "Page, from 1"
+// @Param        per_page  query     int     false  "Items per page, 20 by default and at most 100"
+// @Param        sort      query     string  false  "Sort by date, the period end (default), created_at or net_salary"
+// @Param        order     query     string  false  "desc (default) or asc"
+// @Success      200    {object}  dto.PageResponse[[]dto.PayslipSummaryResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /payslips [get]
+func ListPayslips(c *gin.Context) {
+	var payslips []models.Payslip
+	page, total, err := findHistoryPage(c, true, payslipHistory, db.DB.Preload("Payroll"), &payslips)
+	if errors.Is(err, errHistoryQueryInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payslips"})
+		return
+	}
+
+	resp := make([]dto.PayslipSummaryResponse, 0, len(payslips))
+	for _, payslip := range payslips {
+		resp = append(resp, dto.PayslipSummaryResponse{
+			ID:              payslip.ID,
+			Month:           payslip.Month,
+			Year:            payslip.Year,
+			Kind:            payslip.Kind,
+			Version:         payslip.Version,
+			PeriodStart:     payslip.Payroll.PeriodStart.Format("2006-01-02"),
+			PeriodEnd:       payslip.Payroll.PeriodEnd.Format("2006-01-02"),
+			TotalSalary:     payslip.TotalSalary,
+			OtherDeductions: payslip.OtherDeductions,
+			Tax:             payslip.Tax,
+			BPJSEmployee:    payslip.BPJSEmployee,
+			NetSalary:       payslip.NetSalary,
+		})
+	}
+
+	c.JSON(http.StatusOK, utils.WrapPageResponse(resp, page, total))
+}
+
 // GetPayslip godoc
 // @Summary      Get payslip for current user
-// @Description  Fetches payslip for a specific month and year
+// @Description  Fetches the current payslip for a specific month and year.
+// @Description  Payslips superseded by a reopened payroll are only available through the history endpoint.
 // @Tags         Payslip
 // @Security     BearerAuth
 // @Produce      json
@@ -41,39 +95,139 @@ func GetPayslip(c *gin.Context) {
 	var payslip models.Payslip
 	err := db.DB.
 		Preload("User").
-		Where("user_id = ? AND year = ? AND month = ?", userID, year, month).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence")
+		}).
+		Where("user_id = ? AND year = ? AND month = ? AND superseded_at IS NULL", userID, year, month).
+		Order("version DESC").
 		First(&payslip).Error
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Payslip not found"})
 		return
 	}
 
-	var aB []dto.AttendanceBreakdownItem
-	err = json.Unmarshal([]byte(payslip.AttendanceBreakdown), &aB)
+	resp, err := toPayslipResponse(payslip)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err})
 		return
 	}
+	if resp.YearToDate, err = payslipYearToDate(db.DB, userID, year, month); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payslips"})
+		return
+	}
 
-	var oB []dto.OvertimeBreakdownItem
-	err = json.Unmarshal([]byte(payslip.OvertimeBreakdown), &oB)
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// GetPayslipHistory godoc
+// @Summary      Get payslip history for current user
+// @Description  Fetches every version of the payslip for a specific month and year, latest first.
+// @Description  Older versions were superseded when the payroll was reopened and run again.
+// @Tags         Payslip
+// @Security     BearerAuth
+// @Produce      json
+// @Param        year   path      int  true  "Year"
+// @Param        month  path      int  true  "Month"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.PayslipResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Router       /payslips/{year}/{month}/history [get]
+func GetPayslipHistory(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	year, err1 := strconv.Atoi(c.Param("year"))
+	month, err2 := strconv.Atoi(c.Param("month"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year or month"})
+		return
+	}
+
+	var payslips []models.Payslip
+	err := db.DB.
+		Preload("User").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence")
+		}).
+		Where("user_id = ? AND year = ? AND month = ?", userID, year, month).
+		Order("version DESC").
+		Find(&payslips).Error
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payslips"})
+		return
+	}
+	if len(payslips) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payslip not found"})
 		return
 	}
 
-	var rB []dto.ReimbursementBreakdownItem
-	err = json.Unmarshal([]byte(payslip.ReimbursementBreakdown), &rB)
+	// the year to date is that of the current payslips, whichever version is shown
+	ytd, err := payslipYearToDate(db.DB, userID, year, month)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payslips"})
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.PayslipResponse{
-		ID:     payslip.ID,
-		Month:  payslip.Month,
-		Year:   payslip.Year,
-		UserID: payslip.UserID,
+	history := make([]dto.PayslipResponse, 0, len(payslips))
+	for _, payslip := range payslips {
+		resp, err := toPayslipResponse(payslip)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+			return
+		}
+		resp.YearToDate = ytd
+		history = append(history, resp)
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(history))
+}
+
+func toPayslipResponse(payslip models.Payslip) (dto.PayslipResponse, error) {
+	var aB []dto.AttendanceBreakdownItem
+	if err := json.Unmarshal([]byte(payslip.AttendanceBreakdown), &aB); err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	var oB []dto.OvertimeBreakdownItem
+	if err := json.Unmarshal([]byte(payslip.OvertimeBreakdown), &oB); err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	var rB []dto.ReimbursementBreakdownItem
+	if err := json.Unmarshal([]byte(payslip.ReimbursementBreakdown), &rB); err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	// payslips generated before salary history and BPJS contributions have no breakdown for them
+	sB, err := parseBreakdown[dto.SalaryBreakdownItem](payslip.SalaryBreakdown)
+	if err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	bB, err := parseBreakdown[dto.BPJSBreakdownItem](payslip.BPJSBreakdown)
+	if err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	lB, err := parseBreakdown[dto.LeaveBreakdownItem](payslip.LeaveBreakdown)
+	if err != nil {
+		return dto.PayslipResponse{}, err
+	}
+
+	lines := make([]dto.PayslipLineItem, 0, len(payslip.Lines))
+	for _, line := range payslip.Lines {
+		lines = append(lines, toPayslipLineItem(line))
+	}
+
+	return dto.PayslipResponse{
+		ID:           payslip.ID,
+		Month:        payslip.Month,
+		Year:         payslip.Year,
+		UserID:       payslip.UserID,
+		Kind:         payslip.Kind,
+		PayBasis:     payslip.PayBasis,
+		Version:      payslip.Version,
+		SupersededAt: payslip.SupersededAt,
 
 		// summary totals
 		BaseSalary:    payslip.BaseSalary,
@@ -81,18 +235,91 @@ func GetPayslip(c *gin.Context) {
 		Reimbursement: payslip.Reimbursement,
 		TotalSalary:   payslip.TotalSalary,
 
+		// pay components
+		OtherEarnings:   payslip.OtherEarnings,
+		OtherDeductions: payslip.OtherDeductions,
+		Lines:           lines,
+
+		// PPh 21 withholding
+		TaxableIncome:   payslip.TaxableIncome,
+		TaxRate:         payslip.TaxRate,
+		Tax:             payslip.Tax,
+		NetSalary:       payslip.NetSalary,
+		TaxMethod:       payslip.TaxMethod,
+		TaxTableVersion: payslip.TaxTableVersion,
+		PTKPStatus:      payslip.PTKPStatus,
+
+		// BPJS contributions
+		BPJSEmployee: payslip.BPJSEmployee,
+		BPJSEmployer: payslip.BPJSEmployer,
+
 		// calculation context
-		MonthlySalary:       payslip.User.Salary,
+		MonthlySalary:       payslip.MonthlySalary,
 		ExpectedWorkingDays: payslip.ExpectedWorkingDays,
+		EmployedWorkingDays: payslip.EmployedWorkingDays,
 		DaysAttended:        payslip.DaysAttended,
+		PaidLeaveDays:       payslip.PaidLeaveDays,
+		UnpaidLeaveDays:     payslip.UnpaidLeaveDays,
 		HourlyRate:          payslip.HourlyRate,
 		OvertimeRatePerHour: payslip.OvertimeRatePerHour,
 
+		HolidayOvertimeRatePerHour: payslip.HolidayOvertimeRatePerHour,
+
 		// breakdowns
 		TotalHoursWorked:       payslip.TotalHoursWorked,
 		TotalOvertimeHours:     payslip.TotalOvertimeHours,
+		HolidayOvertimeHours:   payslip.HolidayOvertimeHours,
+		SalaryBreakdown:        sB,
 		AttendanceBreakdown:    aB,
 		OvertimeBreakdown:      oB,
+		LeaveBreakdown:         lB,
 		ReimbursementBreakdown: rB,
-	}))
+		BPJSBreakdown:          bB,
+	}, nil
+}
+
+// processedPayslipsOfYear selects the user's current payslips of the processed payrolls of a year.
+func processedPayslipsOfYear(tx *gorm.DB, userID uint, year int) *gorm.DB {
+	return tx.Model(&models.Payslip{}).
+		Joins("JOIN payrolls ON payrolls.id = payslips.payroll_id").
+		Where("payslips.user_id = ? AND payslips.year = ?", userID, year).
+		Where("payslips.superseded_at IS NULL AND payrolls.status = ?", models.PayrollStatusProcessed)
+}
+
+// payslipYearToDate sums the user's payslips of the year's processed payrolls up to and including the month.
+func payslipYearToDate(tx *gorm.DB, userID uint, year, month int) (dto.PayslipYearToDate, error) {
+	var ytd dto.PayslipYearToDate
+	err := processedPayslipsOfYear(tx, userID, year).
+		Select("COUNT(*) AS months, "+
+			"COALESCE(SUM(payslips.total_salary - COALESCE(payslips.reimbursement, 0)), 0) AS total_salary, "+
+			"COALESCE(SUM(payslips.overtime_pay), 0) AS overtime_pay, "+
+			"COALESCE(SUM(payslips.reimbursement), 0) AS reimbursement, "+
+			"COALESCE(SUM(payslips.taxable_income), 0) AS taxable_income, "+
+			"COALESCE(SUM(payslips.tax), 0) AS tax, "+
+			"COALESCE(SUM(payslips.net_salary), 0) AS net_salary").
+		Where("payslips.month <= ?", month).
+		Scan(&ytd).Error
+	return ytd, err
+}
+
+// parseBreakdown decodes a breakdown stored as JSON, an empty column is an empty breakdown.
+func parseBreakdown[T any](data string) ([]T, error) {
+	items := []T{}
+	if data == "" {
+		return items, nil
+	}
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func toPayslipLineItem(line models.PayslipLine) dto.PayslipLineItem {
+	return dto.PayslipLineItem{
+		Code:    line.Code,
+		Name:    line.Name,
+		Type:    line.Type,
+		Amount:  line.Amount,
+		Taxable: line.Taxable,
+	}
 }
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,7 +26,9 @@ func AuthStubMiddlewareForPayslip() gin.HandlerFunc {
 
 func setupTestRouterForPayslip() *gin.Engine {
 	r := gin.Default()
+	r.GET("/payslips", AuthStubMiddlewareForPayslip(), handlers.ListPayslips)
 	r.GET("/payslips/:year/:month", AuthStubMiddlewareForPayslip(), handlers.GetPayslip)
+	r.GET("/payslips/:year/:month/history", AuthStubMiddlewareForPayslip(), handlers.GetPayslipHistory)
 	return r
 }
 
@@ -39,6 +43,8 @@ func setupTestDBForPayslip() (func(), error) {
 		Username: "johndoe",
 		Password: "password",
 		RoleID:   2,
+		// raised after the payslip was generated
+		Salary: decimal.NewFromInt(7000),
 	}
 
 	if err := d.Create(&user).Error; err != nil {
@@ -60,10 +66,11 @@ func setupTestDBForPayslip() (func(), error) {
 		UserID:                 1,
 		Month:                  5,
 		Year:                   2024,
-		BaseSalary:             5000,
-		OvertimePay:            200,
-		Reimbursement:          100,
-		TotalSalary:            5300,
+		BaseSalary:             decimal.NewFromInt(5000),
+		OvertimePay:            decimal.NewFromInt(200),
+		Reimbursement:          decimal.NewFromInt(100),
+		TotalSalary:            decimal.NewFromInt(5300),
+		MonthlySalary:          decimal.NewFromInt(5000),
 		TotalHoursWorked:       160,
 		TotalOvertimeHours:     10,
 		AttendanceBreakdown:    "[]",
@@ -98,6 +105,9 @@ func TestGetPayslip_Success(t *testing.T) {
 	assert.Equal(t, uint(1), response.Data.UserID)
 	assert.Equal(t, 2024, response.Data.Year)
 	assert.Equal(t, 5, response.Data.Month)
+	assert.True(t, decimal.NewFromInt(5300).Equal(response.Data.TotalSalary))
+	// the salary snapshot taken at run time, not the current salary
+	assert.True(t, decimal.NewFromInt(5000).Equal(response.Data.MonthlySalary))
 }
 
 func TestGetPayslip_NotFound(t *testing.T) {
@@ -135,3 +145,169 @@ func TestGetPayslip_InvalidParams(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "Invalid year or month")
 }
+
+func TestGetPayslip_ReturnsLatestVersion(t *testing.T) {
+	r := setupTestRouterForPayslip()
+
+	cleanup, err := setupTestDBForPayslip()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	// supersede the seeded payslip with a second version
+	now := time.Now()
+	db.DB.Model(&models.Payslip{}).Where("version = 1").Update("superseded_at", now)
+	latest := models.Payslip{
+		PayrollID:              1,
+		UserID:                 1,
+		Month:                  5,
+		Year:                   2024,
+		Version:                2,
+		BaseSalary:             decimal.NewFromInt(5500),
+		TotalSalary:            decimal.NewFromInt(5500),
+		AttendanceBreakdown:    "[]",
+		OvertimeBreakdown:      "[]",
+		ReimbursementBreakdown: "[]",
+	}
+	db.DB.Create(&latest)
+
+	req := httptest.NewRequest(http.MethodGet, "/payslips/2024/5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.SuccessResponse[dto.PayslipResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Data.Version)
+	assert.Nil(t, response.Data.SupersededAt)
+
+	req = httptest.NewRequest(http.MethodGet, "/payslips/2024/5/history", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var history dto.SuccessResponse[[]dto.PayslipResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &history)
+	assert.NoError(t, err)
+	assert.Len(t, history.Data, 2)
+	assert.Equal(t, 2, history.Data[0].Version)
+	assert.Equal(t, 1, history.Data[1].Version)
+	assert.NotNil(t, history.Data[1].SupersededAt)
+}
+
+func TestGetPayslipHistory_NotFound(t *testing.T) {
+	r := setupTestRouterForPayslip()
+
+	_, cleanup, err := db.InitTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/payslips/2024/12/history", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Payslip not found")
+}
+
+func TestListPayslips_YearToDate(t *testing.T) {
+	r := setupTestRouterForPayslip()
+
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.User{ID: 1, Username: "johndoe", Password: "password", RoleID: 2})
+	payslip := func(month, version int, status string, total int64) {
+		payroll := models.Payroll{Month: month, Year: 2025, Status: status,
+			PeriodStart: time.Date(2025, time.Month(month), 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:   time.Date(2025, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC),
+		}
+		d.Where(models.Payroll{Month: month, Year: 2025}).FirstOrCreate(&payroll)
+		p := models.Payslip{
+			PayrollID:              payroll.ID,
+			UserID:                 1,
+			Month:                  month,
+			Year:                   2025,
+			Version:                version,
+			TotalSalary:            decimal.NewFromInt(total),
+			OvertimePay:            decimal.NewFromInt(100),
+			Tax:                    decimal.NewFromInt(50),
+			NetSalary:              decimal.NewFromInt(total - 50),
+			AttendanceBreakdown:    "[]",
+			OvertimeBreakdown:      "[]",
+			ReimbursementBreakdown: "[]",
+		}
+		if version == 1 && month == 2 {
+			p.SupersededAt = timePtr(time.Now())
+		}
+		if month == 3 {
+			p.Reimbursement = decimal.NewFromInt(500)
+		}
+		d.Create(&p)
+	}
+	payslip(1, 1, models.PayrollStatusProcessed, 5000)
+	// February was reopened and run again
+	payslip(2, 1, models.PayrollStatusProcessed, 4000)
+	payslip(2, 2, models.PayrollStatusProcessed, 5500)
+	payslip(3, 1, models.PayrollStatusProcessed, 6000)
+	payslip(4, 1, models.PayrollStatusPending, 7000)
+
+	req := httptest.NewRequest(http.MethodGet, "/payslips?start=2025-01-01&end=2025-12-31", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list dto.PageResponse[[]dto.PayslipSummaryResponse]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(4), list.Meta.Total)
+	if assert.Len(t, list.Data, 4) {
+		assert.Equal(t, 4, list.Data[0].Month)
+		assert.Equal(t, 2, list.Data[2].Version)
+		assert.Equal(t, "2025-02-01", list.Data[2].PeriodStart)
+		assert.Equal(t, "2025-02-28", list.Data[2].PeriodEnd)
+	}
+
+	// a page at a time, filtered by period end
+	req = httptest.NewRequest(http.MethodGet, "/payslips?end=2025-03-31&sort=date&order=asc&per_page=2&page=2", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(3), list.Meta.Total)
+	assert.Equal(t, 2, list.Meta.TotalPages)
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, 3, list.Data[0].Month)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/payslips/2025/3", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.SuccessResponse[dto.PayslipResponse]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	ytd := response.Data.YearToDate
+	// January, the current February and March
+	assert.Equal(t, 3, ytd.Months)
+	// reimbursements are not pay, March's 500 is only in the reimbursement total
+	assert.Equal(t, "16000", ytd.TotalSalary.String())
+	assert.Equal(t, "500", ytd.Reimbursement.String())
+	assert.Equal(t, "300", ytd.OvertimePay.String())
+	assert.Equal(t, "150", ytd.Tax.String())
+	assert.Equal(t, "16350", ytd.NetSalary.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/payslips?start=last", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"dealls-case-study/internal/db"
@@ -10,15 +15,26 @@ import (
 	"dealls-case-study/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errReimbursementOverLimit is reported to the client as a bad request.
+var errReimbursementOverLimit = errors.New("reimbursement exceeds the category limit")
+
 // SubmitReimbursement godoc
 // @Summary      Submit reimbursement for current user
-// @Description  Allows an employee to submit a reimbursement request.
+// @Description  Allows an employee to submit a reimbursement request. Claims without a category are filed as "other".
+// @Description  A claim must not exceed the category's per-claim limit, nor its monthly limit together with the
+// @Description  employee's other claims of the month that are not rejected. The claim is paid once an admin approves it.
+// @Description  Send multipart/form-data with the same fields to attach receipts: JPEG, PNG, WebP or PDF files of at most 5 MiB, 5 per claim.
 // @Tags         Reimbursements
 // @Accept       json
+// @Accept       multipart/form-data
 // @Produce      json
 // @Param        request body     dto.SubmitReimbursementRequest true "Reimbursement data"
+// @Param        receipts formData file false "Receipt files, multipart only"
 // @Success      201    {object}  dto.SuccessResponse[dto.SubmitReimbursementResponse]
 // @Failure      400    {object}  dto.ErrorResponse
 // @Failure      401    {object}  dto.ErrorResponse
@@ -28,31 +44,448 @@ import (
 func SubmitReimbursement(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	var req dto.SubmitReimbursementRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	req, receipts, err := bindSubmitReimbursement(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !req.Amount.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be greater than 0"})
+		return
+	}
+	if !req.Amount.Equal(req.Amount.Round(2)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must have at most 2 decimal places"})
+		return
+	}
+
+	code := req.Category
+	if code == "" {
+		code = models.ReimbursementCategoryOther
+	}
+	var category models.ReimbursementCategory
+	if err := db.DB.Where("code = ?", code).First(&category).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reimbursement category"})
+		return
+	}
+
+	loc, err := userLocation(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit reimbursement"})
+		return
+	}
+
 	reimbursement := models.Reimbursement{
-		UserID:    userID,
-		Amount:    req.Amount,
-		Date:      time.Now(),
-		CreatedBy: userID,
+		UserID:     userID,
+		CategoryID: &category.ID,
+		Category:   &category,
+		Amount:     req.Amount,
+		Date:       utils.CalendarDate(time.Now(), loc),
+		Status:     models.ReimbursementStatusSubmitted,
+		CreatedBy:  userID,
 	}
 
 	if req.Description != nil {
 		reimbursement.Description = *req.Description
 	}
 
-	if err := db.DB.Create(&reimbursement).Error; err != nil {
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if category.ExceedsClaimLimit(reimbursement.Amount) {
+			return fmt.Errorf("%w: %s claims are limited to %s", errReimbursementOverLimit, category.Name, category.PerClaimLimit.Decimal.StringFixed(2))
+		}
+
+		if category.MonthlyLimit.Valid {
+			// concurrent claims of the employee wait here, so each sees the others in the monthly sum
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.User{}, userID).Error; err != nil {
+				return err
+			}
+			claimed, err := claimedThisMonth(tx, userID, category.ID, reimbursement.Date)
+			if err != nil {
+				return err
+			}
+			if category.ExceedsMonthlyLimit(claimed, reimbursement.Amount) {
+				remaining := decimal.Max(category.MonthlyLimit.Decimal.Sub(claimed), decimal.Zero)
+				return fmt.Errorf("%w: %s claims are limited to %s a month, %s remaining", errReimbursementOverLimit, category.Name,
+					category.MonthlyLimit.Decimal.StringFixed(2), remaining.StringFixed(2))
+			}
+		}
+
+		if err := tx.Omit("Category").Create(&reimbursement).Error; err != nil {
+			return err
+		}
+
+		if len(receipts) > 0 {
+			attachments, err := storeReceipts(c.Request.Context(), tx, reimbursement, receipts, userID)
+			if err != nil {
+				return err
+			}
+			reimbursement.Attachments = attachments
+		}
+		return nil
+	})
+	if err != nil {
+		// the receipts were stored, but the claim they belong to was rolled back
+		deleteReceipts(c.Request.Context(), reimbursement.Attachments)
+	}
+	if errors.Is(err, errReimbursementOverLimit) || errors.Is(err, errReceiptInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit reimbursement"})
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.WrapSuccessResponse(dto.SubmitReimbursementResponse{
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(dto.SubmitReimbursementResponse{
 		ID:          reimbursement.ID,
 		Amount:      reimbursement.Amount,
+		Category:    category.Code,
+		Status:      reimbursement.Status,
 		Description: &reimbursement.Description,
+		Attachments: toReimbursementAttachmentResponses(reimbursement.Attachments),
 	}))
 }
+
+// bindSubmitReimbursement reads a claim sent as JSON, or as a multipart form with its receipts.
+func bindSubmitReimbursement(c *gin.Context) (dto.SubmitReimbursementRequest, []*multipart.FileHeader, error) {
+	var req dto.SubmitReimbursementRequest
+	if c.ContentType() != "multipart/form-data" {
+		err := c.ShouldBindJSON(&req)
+		return req, nil, err
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return req, nil, err
+	}
+	amount, err := decimal.NewFromString(c.PostForm("amount"))
+	if err != nil {
+		return req, nil, errors.New("amount must be a number")
+	}
+	req.Amount = amount
+	req.Category = c.PostForm("category")
+	if description, ok := c.GetPostForm("description"); ok {
+		req.Description = &description
+	}
+	return req, form.File["receipts"], nil
+}
+
+// claimedThisMonth sums the user's claims of a category in the calendar month of date,
+// rejected claims do not count.
+func claimedThisMonth(tx *gorm.DB, userID, categoryID uint, date time.Time) (decimal.Decimal, error) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 1, 0)
+
+	var claimed decimal.NullDecimal
+	err := tx.Model(&models.Reimbursement{}).
+		Select("SUM(amount)").
+		Where("user_id = ? AND category_id = ? AND status <> ?", userID, categoryID, models.ReimbursementStatusRejected).
+		Where("date >= ? AND date < ?", start, end).
+		Scan(&claimed).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return claimed.Decimal, nil
+}
+
+// ListPendingReimbursements godoc
+// @Summary      List reimbursements to review
+// @Description  Lists the submitted reimbursement claims of all employees, oldest first.
+// @Tags         Reimbursements
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.ReimbursementResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements/pending [get]
+func ListPendingReimbursements(c *gin.Context) {
+	var reimbursements []models.Reimbursement
+	if err := db.DB.Preload("Category").Preload("Attachments").
+		Where("status = ?", models.ReimbursementStatusSubmitted).
+		Order("date").
+		Find(&reimbursements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list reimbursements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toReimbursementResponses(reimbursements)))
+}
+
+// ApproveReimbursement godoc
+// @Summary      Approve reimbursement
+// @Description  Approves a submitted claim. It is paid by the next payroll processed for a period ending on or after
+// @Description  the claim date, and marked paid once that payroll is processed. Admins cannot approve their own claims.
+// @Tags         Reimbursements
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Reimbursement ID"
+// @Param        request body     dto.ReviewReimbursementRequest false "Reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.ReimbursementResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements/{id}/approve [post]
+func ApproveReimbursement(c *gin.Context) {
+	reviewReimbursement(c, models.ReimbursementStatusApproved)
+}
+
+// RejectReimbursement godoc
+// @Summary      Reject reimbursement
+// @Description  Rejects a submitted claim, it is never paid and no longer counts toward the monthly limit. A reason is required.
+// @Tags         Reimbursements
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Reimbursement ID"
+// @Param        request body     dto.ReviewReimbursementRequest true "Reason"
+// @Success      200    {object}  dto.SuccessResponse[dto.ReimbursementResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements/{id}/reject [post]
+func RejectReimbursement(c *gin.Context) {
+	reviewReimbursement(c, models.ReimbursementStatusRejected)
+}
+
+func reviewReimbursement(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reimbursement id"})
+		return
+	}
+
+	var req dto.ReviewReimbursementRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if status == models.ReimbursementStatusRejected && strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required to reject a reimbursement"})
+		return
+	}
+
+	var reimbursement models.Reimbursement
+	if err := db.DB.Preload("Category").Preload("Attachments").First(&reimbursement, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement not found"})
+		return
+	}
+
+	reviewerID := c.GetUint("user_id")
+	if reimbursement.UserID == reviewerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you cannot review your own reimbursement"})
+		return
+	}
+	if reimbursement.Status != models.ReimbursementStatusSubmitted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reimbursement is already " + reimbursement.Status})
+		return
+	}
+
+	// only a claim still submitted is reviewed, a concurrent review of it wins
+	now := time.Now()
+	result := db.DB.Model(&models.Reimbursement{}).
+		Where("id = ? AND status = ?", reimbursement.ID, models.ReimbursementStatusSubmitted).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"reviewed_by":   reviewerID,
+			"reviewed_at":   now,
+			"review_reason": req.Reason,
+			"updated_by":    reviewerID,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review reimbursement"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		var current models.Reimbursement
+		if err := db.DB.Select("status").First(&current, reimbursement.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review reimbursement"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reimbursement is already " + current.Status})
+		return
+	}
+	reimbursement.Status = status
+	reimbursement.ReviewedBy = &reviewerID
+	reimbursement.ReviewedAt = &now
+	reimbursement.ReviewReason = req.Reason
+	reimbursement.UpdatedBy = reviewerID
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toReimbursementResponse(reimbursement)))
+}
+
+// ListReimbursementCategories godoc
+// @Summary      List reimbursement categories
+// @Description  Lists the categories a claim can be filed under with their limits. A null limit means no cap.
+// @Tags         Reimbursements
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.ReimbursementCategoryResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursement-categories [get]
+func ListReimbursementCategories(c *gin.Context) {
+	var categories []models.ReimbursementCategory
+	if err := db.DB.Order("id").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list reimbursement categories"})
+		return
+	}
+
+	resp := make([]dto.ReimbursementCategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		resp = append(resp, toReimbursementCategoryResponse(category))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// UpdateReimbursementCategory godoc
+// @Summary      Update reimbursement category limits
+// @Description  Changes the name and limits of a category. New limits apply to claims submitted from now on.
+// @Tags         Reimbursements
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "Category ID"
+// @Param        request body     dto.ReimbursementCategoryRequest true "Category"
+// @Success      200    {object}  dto.SuccessResponse[dto.ReimbursementCategoryResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursement-categories/{id} [put]
+func UpdateReimbursementCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reimbursement category id"})
+		return
+	}
+
+	var req dto.ReimbursementCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, limit := range []decimal.NullDecimal{req.PerClaimLimit, req.MonthlyLimit} {
+		if limit.Valid && !limit.Decimal.IsPositive() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limits must be greater than 0, or null for no limit"})
+			return
+		}
+	}
+
+	var category models.ReimbursementCategory
+	if err := db.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement category not found"})
+		return
+	}
+
+	category.Name = req.Name
+	category.PerClaimLimit = req.PerClaimLimit
+	category.MonthlyLimit = req.MonthlyLimit
+	category.UpdatedBy = c.GetUint("user_id")
+
+	if err := db.DB.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update reimbursement category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toReimbursementCategoryResponse(category)))
+}
+
+// findPayableReimbursements returns the approved claims not paid yet, dated up to the end
+// of the period. Claims approved after their period was processed are paid by the next one.
+func findPayableReimbursements(tx *gorm.DB, userID uint, end time.Time) ([]models.Reimbursement, error) {
+	var reimbursements []models.Reimbursement
+	err := tx.Preload("Category").
+		Where("user_id = ? AND status = ? AND date < ?", userID, models.ReimbursementStatusApproved, end.AddDate(0, 0, 1)).
+		Order("date").
+		Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+// markReimbursementsPaid marks the claims paid by a processed payslip.
+func markReimbursementsPaid(tx *gorm.DB, payslip models.Payslip, payrollID uint) error {
+	items, err := parseBreakdown[dto.ReimbursementBreakdownItem](payslip.ReimbursementBreakdown)
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return tx.Model(&models.Reimbursement{}).
+		Where("id IN ? AND status = ?", ids, models.ReimbursementStatusApproved).
+		Updates(map[string]interface{}{
+			"status":     models.ReimbursementStatusPaid,
+			"payroll_id": payrollID,
+			"paid_at":    time.Now(),
+		}).Error
+}
+
+func toReimbursementBreakdown(reimbursements []models.Reimbursement) []dto.ReimbursementBreakdownItem {
+	items := make([]dto.ReimbursementBreakdownItem, 0, len(reimbursements))
+	for _, r := range reimbursements {
+		item := dto.ReimbursementBreakdownItem{
+			ID:          r.ID,
+			Date:        r.DateOnlyString(),
+			Amount:      r.Amount,
+			Description: r.Description,
+		}
+		if r.Category != nil {
+			item.Category = r.Category.Code
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func toReimbursementResponses(reimbursements []models.Reimbursement) []dto.ReimbursementResponse {
+	resp := make([]dto.ReimbursementResponse, 0, len(reimbursements))
+	for _, r := range reimbursements {
+		resp = append(resp, toReimbursementResponse(r))
+	}
+	return resp
+}
+
+func toReimbursementResponse(r models.Reimbursement) dto.ReimbursementResponse {
+	resp := dto.ReimbursementResponse{
+		ID:           r.ID,
+		UserID:       r.UserID,
+		Date:         r.DateOnlyString(),
+		Amount:       r.Amount,
+		Description:  r.Description,
+		Status:       r.Status,
+		ReviewedBy:   r.ReviewedBy,
+		ReviewedAt:   r.ReviewedAt,
+		ReviewReason: r.ReviewReason,
+		PayrollID:    r.PayrollID,
+		PaidAt:       r.PaidAt,
+		Attachments:  toReimbursementAttachmentResponses(r.Attachments),
+	}
+	if r.Category != nil {
+		resp.Category = r.Category.Code
+	}
+	return resp
+}
+
+func toReimbursementCategoryResponse(category models.ReimbursementCategory) dto.ReimbursementCategoryResponse {
+	return dto.ReimbursementCategoryResponse{
+		ID:            category.ID,
+		Code:          category.Code,
+		Name:          category.Name,
+		PerClaimLimit: category.PerClaimLimit,
+		MonthlyLimit:  category.MonthlyLimit,
+	}
+}
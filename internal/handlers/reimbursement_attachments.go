@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/storage"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxReceiptSize is the largest receipt accepted, 5 MiB
+	maxReceiptSize = 5 << 20
+	// maxReceiptsPerClaim limits the receipts attached to one claim
+	maxReceiptsPerClaim = 5
+)
+
+// receiptTypes maps the accepted receipt content types, as sniffed from the file, to
+// the extension the file is stored with.
+var receiptTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// errReceiptInvalid wraps errors that are reported to the client as a bad request.
+var errReceiptInvalid = errors.New("invalid receipt")
+
+// AddReimbursementAttachments godoc
+// @Summary      Attach receipts to a reimbursement
+// @Description  Uploads receipts for one of the current user's claims that is not reviewed yet.
+// @Description  Receipts are JPEG, PNG, WebP or PDF files of at most 5 MiB, and a claim has at most 5.
+// @Tags         Reimbursements
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id        path      int   true  "Reimbursement ID"
+// @Param        receipts  formData  file  true  "Receipt files"
+// @Success      201    {object}  dto.SuccessResponse[dto.ReimbursementResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements/{id}/attachments [post]
+func AddReimbursementAttachments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reimbursement id"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var reimbursement models.Reimbursement
+	if err := db.DB.Preload("Category").Preload("Attachments").
+		Where("user_id = ?", userID).
+		First(&reimbursement, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement not found"})
+		return
+	}
+	if reimbursement.Status != models.ReimbursementStatusSubmitted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "receipts cannot be added, the reimbursement is already " + reimbursement.Status})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["receipts"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "receipts are required"})
+		return
+	}
+	receipts := form.File["receipts"]
+
+	var added []models.ReimbursementAttachment
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// concurrent uploads and reviews of the claim wait here, so the receipt limit and status hold
+		var locked models.Reimbursement
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, reimbursement.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status != models.ReimbursementStatusSubmitted {
+			return fmt.Errorf("%w: receipts cannot be added, the reimbursement is already %s", errReceiptInvalid, locked.Status)
+		}
+
+		attachments, err := storeReceipts(c.Request.Context(), tx, reimbursement, receipts, userID)
+		if err != nil {
+			return err
+		}
+		added = attachments
+		return nil
+	})
+	if err != nil {
+		// the receipts were stored, but their records were rolled back
+		deleteReceipts(c.Request.Context(), added)
+	}
+	if errors.Is(err, errReceiptInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store receipts"})
+		return
+	}
+	reimbursement.Attachments = append(reimbursement.Attachments, added...)
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toReimbursementResponse(reimbursement)))
+}
+
+// DownloadReimbursementAttachment godoc
+// @Summary      Download a reimbursement receipt
+// @Description  Streams a receipt to the employee who filed the claim, their manager or an admin.
+// @Tags         Reimbursements
+// @Produce      application/octet-stream
+// @Param        id            path  int  true  "Reimbursement ID"
+// @Param        attachmentId  path  int  true  "Attachment ID"
+// @Success      200    {file}    file
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reimbursements/{id}/attachments/{attachmentId} [get]
+func DownloadReimbursementAttachment(c *gin.Context) {
+	id, err1 := strconv.Atoi(c.Param("id"))
+	attachmentID, err2 := strconv.Atoi(c.Param("attachmentId"))
+	if err1 != nil || err2 != nil || id <= 0 || attachmentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reimbursement or attachment id"})
+		return
+	}
+
+	var reimbursement models.Reimbursement
+	if err := db.DB.Preload("User").First(&reimbursement, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reimbursement not found"})
+		return
+	}
+	if reimbursement.UserID != c.GetUint("user_id") && !canReview(c, reimbursement.User) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the employee, their manager or an admin can view this receipt"})
+		return
+	}
+
+	var attachment models.ReimbursementAttachment
+	if err := db.DB.Where("reimbursement_id = ?", reimbursement.ID).First(&attachment, attachmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	file, err := storage.Store.Get(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read receipt"})
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+	})
+}
+
+// storeReceipts validates and stores the receipts of a claim, the caller holds a lock on the claim.
+// Receipts already stored are removed again when one of them fails; when the surrounding
+// transaction fails later, the caller removes the returned receipts with deleteReceipts.
+func storeReceipts(ctx context.Context, tx *gorm.DB, reimbursement models.Reimbursement, receipts []*multipart.FileHeader, userID uint) ([]models.ReimbursementAttachment, error) {
+	var attached int64
+	if err := tx.Model(&models.ReimbursementAttachment{}).
+		Where("reimbursement_id = ?", reimbursement.ID).
+		Count(&attached).Error; err != nil {
+		return nil, err
+	}
+	if int(attached)+len(receipts) > maxReceiptsPerClaim {
+		return nil, fmt.Errorf("%w: a reimbursement has at most %d receipts", errReceiptInvalid, maxReceiptsPerClaim)
+	}
+
+	contentTypes := make([]string, 0, len(receipts))
+	for _, receipt := range receipts {
+		contentType, err := receiptContentType(receipt)
+		if err != nil {
+			return nil, err
+		}
+		contentTypes = append(contentTypes, contentType)
+	}
+
+	attachments := make([]models.ReimbursementAttachment, 0, len(receipts))
+	var stored []models.ReimbursementAttachment
+	cleanup := func() {
+		deleteReceipts(ctx, stored)
+	}
+
+	for i, receipt := range receipts {
+		key, err := receiptKey(reimbursement.ID, receiptTypes[contentTypes[i]])
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		if err := putReceipt(ctx, key, receipt, contentTypes[i]); err != nil {
+			cleanup()
+			return nil, err
+		}
+		attachment := models.ReimbursementAttachment{
+			ReimbursementID: reimbursement.ID,
+			FileName:        filepath.Base(receipt.Filename),
+			ContentType:     contentTypes[i],
+			Size:            receipt.Size,
+			StorageKey:      key,
+			CreatedBy:       userID,
+		}
+		stored = append(stored, attachment)
+		if err := tx.Create(&attachment).Error; err != nil {
+			cleanup()
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+// deleteReceipts removes stored receipts whose records were not kept. Failures are only logged,
+// an orphaned file is harmless.
+func deleteReceipts(ctx context.Context, attachments []models.ReimbursementAttachment) {
+	for _, a := range attachments {
+		if err := storage.Store.Delete(ctx, a.StorageKey); err != nil {
+			log.Printf("failed to delete receipt %s: %v", a.StorageKey, err)
+		}
+	}
+}
+
+// receiptContentType sniffs the content type from the file, the type sent by the client is not trusted.
+func receiptContentType(receipt *multipart.FileHeader) (string, error) {
+	if receipt.Size > maxReceiptSize {
+		return "", fmt.Errorf("%w: %s is larger than %d MiB", errReceiptInvalid, receipt.Filename, maxReceiptSize>>20)
+	}
+	if receipt.Size == 0 {
+		return "", fmt.Errorf("%w: %s is empty", errReceiptInvalid, receipt.Filename)
+	}
+
+	file, err := receipt.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if _, ok := receiptTypes[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s is %s, receipts must be JPEG, PNG, WebP or PDF", errReceiptInvalid, receipt.Filename, contentType)
+	}
+	return contentType, nil
+}
+
+func putReceipt(ctx context.Context, key string, receipt *multipart.FileHeader, contentType string) error {
+	file, err := receipt.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return storage.Store.Put(ctx, key, file, receipt.Size, contentType)
+}
+
+// receiptKey is a random key, file names chosen by employees never reach the storage.
+func receiptKey(reimbursementID uint, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reimbursements/%d/%s%s", reimbursementID, hex.EncodeToString(b), ext), nil
+}
+
+func toReimbursementAttachmentResponses(attachments []models.ReimbursementAttachment) []dto.ReimbursementAttachmentResponse {
+	resp := make([]dto.ReimbursementAttachmentResponse, 0, len(attachments))
+	for _, a := range attachments {
+		resp = append(resp, dto.ReimbursementAttachmentResponse{
+			ID:          a.ID,
+			FileName:    a.FileName,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -6,14 +6,23 @@ import (
 	"dealls-case-study/internal/dto"
 	"dealls-case-study/internal/handlers"
 	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/storage"
 	"dealls-case-study/internal/utils"
 	"encoding/json"
+	"fmt"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -62,7 +71,7 @@ func TestSubmitReimbursement_Success(t *testing.T) {
 
 	description := "Travel expenses"
 	reqBody := dto.SubmitReimbursementRequest{
-		Amount:      50000,
+		Amount:      decimal.NewFromInt(50000),
 		Description: &description,
 	}
 
@@ -72,17 +81,74 @@ func TestSubmitReimbursement_Success(t *testing.T) {
 
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusCreated, w.Code)
 	assert.Contains(t, w.Body.String(), "success")
 
 	var resp dto.SuccessResponse[dto.SubmitReimbursementResponse]
 
 	err1 := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Nil(t, err1)
-	assert.Equal(t, reqBody.Amount, resp.Data.Amount)
+	assert.True(t, reqBody.Amount.Equal(resp.Data.Amount))
 	assert.Equal(t, *reqBody.Description, *resp.Data.Description)
 }
 
+func TestSubmitReimbursement_ExactAmountAsString(t *testing.T) {
+	r := setupTestRouterForReimbursement()
+
+	_, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	body := []byte(`{"amount": "12500000.55"}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/reimbursements", bytes.NewBuffer(body))
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"amount":"12500000.55"`)
+}
+
+func TestSubmitReimbursement_NonPositiveAmount(t *testing.T) {
+	r := setupTestRouterForReimbursement()
+
+	_, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	body := []byte(`{"amount": 0}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/reimbursements", bytes.NewBuffer(body))
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "amount must be greater than 0")
+}
+
+func TestSubmitReimbursement_TooManyDecimals(t *testing.T) {
+	r := setupTestRouterForReimbursement()
+
+	_, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	body := []byte(`{"amount": "50000.555"}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/reimbursements", bytes.NewBuffer(body))
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "amount must have at most 2 decimal places")
+}
+
 func TestSubmitReimbursement_InvalidPayload(t *testing.T) {
 
 	r := setupTestRouterForReimbursement()
@@ -102,3 +168,313 @@ func TestSubmitReimbursement_InvalidPayload(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "error")
 }
+
+func setupTestRouterForReimbursementReview() *gin.Engine {
+	r := gin.Default()
+	r.Use(AuthStubMiddlewareForLeaves())
+	r.POST("/reimbursements", handlers.SubmitReimbursement)
+	r.GET("/me/reimbursements", handlers.ListMyReimbursements)
+	r.GET("/reimbursements/pending", handlers.ListPendingReimbursements)
+	r.POST("/reimbursements/:id/approve", handlers.ApproveReimbursement)
+	r.POST("/reimbursements/:id/reject", handlers.RejectReimbursement)
+	r.PUT("/reimbursement-categories/:id", handlers.UpdateReimbursementCategory)
+
+	return r
+}
+
+func TestSubmitReimbursement_CategoryLimits(t *testing.T) {
+	r := setupTestRouterForReimbursementReview()
+
+	_, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	w := leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "100000", "category": "parking"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown reimbursement category")
+
+	// meals are limited to 250,000 a claim
+	w = leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "250000.01", "category": "meals"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Meals claims are limited to 250000.00")
+
+	// travel is limited to 6,000,000 a month
+	w = leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "3000000", "category": "travel"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"category":"travel"`)
+	assert.Contains(t, w.Body.String(), `"status":"submitted"`)
+	w = leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "2500000", "category": "travel"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "600000", "category": "travel"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "500000.00 remaining")
+
+	// raising the limit lets the claim through
+	var travel models.ReimbursementCategory
+	db.DB.Where("code = ?", models.ReimbursementCategoryTravel).First(&travel)
+	w = leaveRequest(r, 1, http.MethodPut, fmt.Sprintf("/reimbursement-categories/%d", travel.ID),
+		gin.H{"name": "Travel", "per_claim_limit": "3000000", "monthly_limit": nil})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"monthly_limit":null`)
+
+	w = leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": "600000", "category": "travel"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestReimbursement_ReviewAndPayroll(t *testing.T) {
+	r := setupTestRouterForReimbursementReview()
+
+	d, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	d.Model(&models.User{}).Where("id = ?", 1).Update("salary", decimal.NewFromInt(2200000))
+	admin := models.User{ID: 5, Username: "admin", Password: "password", RoleID: 1, EmploymentStatus: models.EmploymentStatusInactive}
+	d.Create(&admin)
+
+	submit := func(amount string) uint {
+		w := leaveRequest(r, 1, http.MethodPost, "/reimbursements", gin.H{"amount": amount, "category": "medical"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp dto.SuccessResponse[dto.SubmitReimbursementResponse]
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Data.ID
+	}
+	approved, rejected, pending := submit("150000"), submit("200000"), submit("50000")
+
+	// employees cannot review their own claims
+	w := leaveRequest(r, 1, http.MethodPost, fmt.Sprintf("/reimbursements/%d/approve", approved), nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = leaveRequest(r, 5, http.MethodGet, "/reimbursements/pending", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var pendingResp dto.SuccessResponse[[]dto.ReimbursementResponse]
+	json.Unmarshal(w.Body.Bytes(), &pendingResp)
+	assert.Len(t, pendingResp.Data, 3)
+
+	w = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/approve", approved), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"approved"`)
+
+	w = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/reject", rejected), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "reason is required")
+	w = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/reject", rejected), gin.H{"reason": "no receipt"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/approve", rejected), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "reimbursement is already rejected")
+
+	// only the approved claim is paid, and marked paid by the processed payroll
+	now := time.Now()
+	payroll := models.Payroll{
+		Month:       int(now.Month()),
+		Year:        now.Year(),
+		PeriodStart: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC),
+		Status:      models.PayrollStatusPending,
+	}
+	d.Create(&payroll)
+	assert.NoError(t, handlers.ProcessPayroll(d, payroll.ID, 5))
+
+	var payslip models.Payslip
+	d.Where("payroll_id = ? AND user_id = ?", payroll.ID, 1).First(&payslip)
+	assert.Equal(t, "150000", payslip.Reimbursement.String())
+
+	var claims []models.Reimbursement
+	d.Order("id").Find(&claims, []uint{approved, rejected, pending})
+	assert.Equal(t, models.ReimbursementStatusPaid, claims[0].Status)
+	assert.Equal(t, payroll.ID, *claims[0].PayrollID)
+	assert.NotNil(t, claims[0].PaidAt)
+	assert.Equal(t, models.ReimbursementStatusRejected, claims[1].Status)
+	assert.Equal(t, models.ReimbursementStatusSubmitted, claims[2].Status)
+
+	w = leaveRequest(r, 1, http.MethodGet, "/me/reimbursements", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"paid"`)
+}
+
+func TestReimbursement_ConcurrentReview(t *testing.T) {
+	r := setupTestRouterForReimbursementReview()
+
+	d, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+
+	d.Create(&models.User{ID: 5, Username: "admin", Password: "password", RoleID: 1})
+	claim := models.Reimbursement{UserID: 1, Amount: decimal.NewFromInt(50000), Date: time.Now(), Status: models.ReimbursementStatusSubmitted}
+	d.Create(&claim)
+
+	// an approval and a rejection at the same time, only one of them is applied
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		codes[0] = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/approve", claim.ID), nil).Code
+	}()
+	go func() {
+		defer wg.Done()
+		codes[1] = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/reject", claim.ID), gin.H{"reason": "no receipt"}).Code
+	}()
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusBadRequest}, codes)
+	var current models.Reimbursement
+	d.First(&current, claim.ID)
+	if codes[0] == http.StatusOK {
+		assert.Equal(t, models.ReimbursementStatusApproved, current.Status)
+	} else {
+		assert.Equal(t, models.ReimbursementStatusRejected, current.Status)
+	}
+}
+
+// multipartReimbursement posts form fields and files, files are keyed by file name.
+func multipartReimbursement(r *gin.Engine, userID uint, path string, fields map[string]string, files map[string][]byte) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	for name, content := range files {
+		part, _ := form.CreateFormFile("receipts", name)
+		part.Write(content)
+	}
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, path, &buf)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("X-User-ID", strconv.Itoa(int(userID)))
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestReimbursement_Receipts(t *testing.T) {
+	r := setupTestRouterForReimbursementReview()
+	r.POST("/reimbursements/:id/attachments", handlers.AddReimbursementAttachments)
+	r.GET("/reimbursements/:id/attachments/:attachmentId", handlers.DownloadReimbursementAttachment)
+
+	d, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+	storage.Store, _ = storage.NewLocalStorage(t.TempDir())
+
+	colleague := models.User{ID: 4, Username: "colleague", Password: "password", RoleID: 2}
+	admin := models.User{ID: 5, Username: "admin", Password: "password", RoleID: 1}
+	d.Create(&colleague)
+	d.Create(&admin)
+
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	w := multipartReimbursement(r, 1, "/reimbursements",
+		map[string]string{"amount": "120000", "category": "meals", "description": "Client lunch"},
+		map[string][]byte{"lunch.pdf": pdf})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.SuccessResponse[dto.SubmitReimbursementResponse]
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.True(t, decimal.NewFromInt(120000).Equal(resp.Data.Amount))
+	assert.Equal(t, "Client lunch", *resp.Data.Description)
+	if assert.Len(t, resp.Data.Attachments, 1) {
+		assert.Equal(t, "lunch.pdf", resp.Data.Attachments[0].FileName)
+		assert.Equal(t, "application/pdf", resp.Data.Attachments[0].ContentType)
+		assert.Equal(t, int64(len(pdf)), resp.Data.Attachments[0].Size)
+	}
+	id, attachmentID := resp.Data.ID, resp.Data.Attachments[0].ID
+
+	// the type is sniffed from the content, not the file name
+	w = multipartReimbursement(r, 1, "/reimbursements",
+		map[string]string{"amount": "50000"},
+		map[string][]byte{"receipt.pdf": []byte("just some text")})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "receipts must be JPEG, PNG, WebP or PDF")
+	var count int64
+	d.Model(&models.Reimbursement{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	w = multipartReimbursement(r, 1, fmt.Sprintf("/reimbursements/%d/attachments", id), nil,
+		map[string][]byte{"large.pdf": append(pdf, make([]byte, 5<<20)...)})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "larger than 5 MiB")
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	w = multipartReimbursement(r, 1, fmt.Sprintf("/reimbursements/%d/attachments", id), nil,
+		map[string][]byte{"photo.png": png})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"content_type":"image/png"`)
+
+	// only the employee, their manager or an admin can download
+	path := fmt.Sprintf("/reimbursements/%d/attachments/%d", id, attachmentID)
+	w = leaveRequest(r, 4, http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = leaveRequest(r, 1, http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=lunch.pdf`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, pdf, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User-ID", "5")
+	req.Header.Set("X-Role", "Admin")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// receipts cannot be added once the claim is reviewed
+	w = leaveRequest(r, 5, http.MethodPost, fmt.Sprintf("/reimbursements/%d/approve", id), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = multipartReimbursement(r, 1, fmt.Sprintf("/reimbursements/%d/attachments", id), nil,
+		map[string][]byte{"late.pdf": pdf})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "already approved")
+}
+
+func TestReimbursement_ConcurrentReceipts(t *testing.T) {
+	r := setupTestRouterForReimbursementReview()
+	r.POST("/reimbursements/:id/attachments", handlers.AddReimbursementAttachments)
+
+	d, cleanup, err := setupTestDBForReimbursement()
+	if err != nil {
+		t.Fatalf("Failed to set up test DB: %v", err)
+	}
+	defer cleanup()
+	dir := t.TempDir()
+	storage.Store, _ = storage.NewLocalStorage(dir)
+
+	claim := models.Reimbursement{UserID: 1, Amount: decimal.NewFromInt(50000), Date: time.Now(), Status: models.ReimbursementStatusSubmitted}
+	d.Create(&claim)
+
+	// two uploads of 3 receipts at the same time, only one fits in the limit of 5
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	receipts := map[string][]byte{"a.pdf": pdf, "b.pdf": pdf, "c.pdf": pdf}
+	path := fmt.Sprintf("/reimbursements/%d/attachments", claim.ID)
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = multipartReimbursement(r, 1, path, nil, receipts).Code
+		}()
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{http.StatusCreated, http.StatusBadRequest}, codes)
+
+	var count int64
+	d.Model(&models.ReimbursementAttachment{}).Where("reimbursement_id = ?", claim.ID).Count(&count)
+	assert.Equal(t, int64(3), count)
+
+	files, _ := os.ReadDir(filepath.Join(dir, "reimbursements", strconv.Itoa(int(claim.ID))))
+	assert.Len(t, files, 3)
+}
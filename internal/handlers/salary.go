@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"time"
+
+	"dealls-case-study/internal/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// salarySegment is a part of a payroll period during which one salary was in effect.
+type salarySegment struct {
+	Start         time.Time
+	End           time.Time
+	Salary        decimal.Decimal
+	EffectiveFrom time.Time
+}
+
+// findSalarySegments splits a period by the user's salary history. Days before the first
+// entry use the first entry; a user without history is paid User.Salary for the whole period.
+func findSalarySegments(tx *gorm.DB, user models.User, start, end time.Time) ([]salarySegment, error) {
+	var history []models.SalaryHistory
+	if err := tx.
+		Where("user_id = ? AND effective_from <= ?", user.ID, end).
+		Order("effective_from").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return []salarySegment{{Start: start, End: end, Salary: user.Salary}}, nil
+	}
+
+	// the entry in effect on the first day of the period
+	current := history[0]
+	rest := history[1:]
+	for len(rest) > 0 && !rest[0].EffectiveFrom.After(start) {
+		current, rest = rest[0], rest[1:]
+	}
+
+	var segments []salarySegment
+	segmentStart := start
+	for _, next := range rest {
+		segments = append(segments, salarySegment{
+			Start:         segmentStart,
+			End:           next.EffectiveFrom.AddDate(0, 0, -1),
+			Salary:        current.Salary,
+			EffectiveFrom: current.EffectiveFrom,
+		})
+		current, segmentStart = next, next.EffectiveFrom
+	}
+	segments = append(segments, salarySegment{
+		Start:         segmentStart,
+		End:           end,
+		Salary:        current.Salary,
+		EffectiveFrom: current.EffectiveFrom,
+	})
+	return segments, nil
+}
+
+// proratedSalary is the salary weighted by the working days each salary was in effect,
+// so a raise mid-period only counts from its effective date.
+func proratedSalary(segments []salarySegment, schedule models.Schedule, holidays map[string]bool) decimal.Decimal {
+	if len(segments) == 1 {
+		return segments[0].Salary
+	}
+
+	total, days := decimal.Zero, 0
+	for _, s := range segments {
+		d := schedule.WorkingDays(s.Start, s.End, holidays)
+		total = total.Add(s.Salary.Mul(decimal.NewFromInt(int64(d))))
+		days += d
+	}
+	if days == 0 {
+		return segments[len(segments)-1].Salary
+	}
+	return total.Div(decimal.NewFromInt(int64(days)))
+}
+
+// currentSalary returns the salary effective on the given date, used to keep User.Salary in sync.
+func currentSalary(tx *gorm.DB, userID uint, date time.Time) (*models.SalaryHistory, error) {
+	var entry models.SalaryHistory
+	err := tx.
+		Where("user_id = ? AND effective_from <= ?", userID, date).
+		Order("effective_from DESC").
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// errScheduleInvalid wraps errors that are reported to the client as a bad request.
+var errScheduleInvalid = errors.New("invalid schedule")
+
+// maxWorkPatternDays bounds the cycle of a work pattern, a year is plenty for any roster.
+const maxWorkPatternDays = 366
+
+// maxScheduleDays bounds the days GetSchedule lists at once.
+const maxScheduleDays = 62
+
+// ListShifts godoc
+// @Summary      List shifts
+// @Description  Lists the shifts work patterns are made of.
+// @Tags         Schedules
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.ShiftResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /shifts [get]
+func ListShifts(c *gin.Context) {
+	var shifts []models.Shift
+	if err := db.DB.Order("start_time, name").Find(&shifts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list shifts"})
+		return
+	}
+
+	resp := make([]dto.ShiftResponse, 0, len(shifts))
+	for _, shift := range shifts {
+		resp = append(resp, toShiftResponse(shift))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// CreateShift godoc
+// @Summary      Create shift
+// @Description  Adds a shift from start_time to end_time, wall clock times in the employee's timezone. A shift ending
+// @Description  at or before its start ends the next day. hours is what a day of the shift is paid and pays the
+// @Description  hourly rate, at most the time from start to end.
+// @Tags         Schedules
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.ShiftRequest true "Shift"
+// @Success      201    {object}  dto.SuccessResponse[dto.ShiftResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /shifts [post]
+func CreateShift(c *gin.Context) {
+	var req dto.ShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start, err1 := utils.ParseTimeOfDay(req.StartTime)
+	end, err2 := utils.ParseTimeOfDay(req.EndTime)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_time and end_time must be times of day such as 08:00"})
+		return
+	}
+	span := end - start
+	if span <= 0 {
+		span += 24 * time.Hour
+	}
+	length := decimal.NewFromFloat(span.Hours())
+	if !req.Hours.IsPositive() || req.Hours.GreaterThan(length) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("hours must be greater than 0 and at most %s, the length of the shift", length)})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.Shift{}).Where("name = ?", req.Name).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a shift named " + req.Name + " already exists"})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	shift := models.Shift{
+		Name:      req.Name,
+		StartTime: formatTimeOfDay(start),
+		EndTime:   formatTimeOfDay(end),
+		Hours:     req.Hours,
+		CreatedBy: adminID,
+		UpdatedBy: adminID,
+	}
+	if err := db.DB.Create(&shift).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create shift"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toShiftResponse(shift)))
+}
+
+// ListWorkPatterns godoc
+// @Summary      List work patterns
+// @Description  Lists the work patterns employees can be put on.
+// @Tags         Schedules
+// @Produce      json
+// @Success      200    {object}  dto.SuccessResponse[[]dto.WorkPatternResponse]
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /work-patterns [get]
+func ListWorkPatterns(c *gin.Context) {
+	var patterns []models.WorkPattern
+	if err := db.DB.Preload("Days").Order("name").Find(&patterns).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list work patterns"})
+		return
+	}
+
+	resp := make([]dto.WorkPatternResponse, 0, len(patterns))
+	for _, pattern := range patterns {
+		resp = append(resp, toWorkPatternResponse(pattern))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// CreateWorkPattern godoc
+// @Summary      Create work pattern
+// @Description  Adds a cycle of days repeating from start_date, shifts lists the shift ID of every day of the cycle
+// @Description  with null on rest days. A Monday to Friday week is 7 days starting on a Monday,
+// @Description  a rotation of two early shifts, two late shifts and two days off is 6 days.
+// @Tags         Schedules
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.WorkPatternRequest true "Work pattern"
+// @Success      201    {object}  dto.SuccessResponse[dto.WorkPatternResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /work-patterns [post]
+func CreateWorkPattern(c *gin.Context) {
+	var req dto.WorkPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Shifts) == 0 || len(req.Shifts) > maxWorkPatternDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("shifts must have 1 to %d days", maxWorkPatternDays)})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	pattern := models.WorkPattern{
+		Name:      req.Name,
+		StartDate: dateOnly(req.StartDate),
+		CreatedBy: adminID,
+		UpdatedBy: adminID,
+	}
+	for day, shiftID := range req.Shifts {
+		pattern.Days = append(pattern.Days, models.WorkPatternDay{Day: day, ShiftID: shiftID})
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.WorkPattern{}).Where("name = ?", pattern.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: a work pattern named %s already exists", errScheduleInvalid, pattern.Name)
+		}
+
+		shifts := 0
+		for _, shiftID := range req.Shifts {
+			if shiftID == nil {
+				continue
+			}
+			shifts++
+			if err := tx.First(&models.Shift{}, *shiftID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: shift %d not found", errScheduleInvalid, *shiftID)
+				}
+				return err
+			}
+		}
+		if shifts == 0 {
+			return fmt.Errorf("%w: a work pattern needs at least one shift", errScheduleInvalid)
+		}
+
+		return tx.Create(&pattern).Error
+	})
+	if errors.Is(err, errScheduleInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create work pattern"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toWorkPatternResponse(pattern)))
+}
+
+// CreateUserWorkPattern godoc
+// @Summary      Put employee on a work pattern
+// @Description  Puts the employee on a work pattern from effective_from until the next one. Check-ins, expected
+// @Description  working days, leave days and the hourly rate follow the employee's shifts; employees without a
+// @Description  work pattern work the standard Monday to Friday week of 8 hour days. It cannot take effect within
+// @Description  a pending or processed payroll.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.CreateUserWorkPatternRequest true "Work pattern"
+// @Success      201    {object}  dto.SuccessResponse[dto.UserWorkPatternResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/work-patterns [post]
+func CreateUserWorkPattern(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.CreateUserWorkPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	entry := models.UserWorkPattern{
+		UserID:        user.ID,
+		WorkPatternID: req.WorkPatternID,
+		EffectiveFrom: dateOnly(req.EffectiveFrom),
+		CreatedBy:     c.GetUint("user_id"),
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entry.WorkPattern, entry.WorkPatternID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: work pattern %d not found", errScheduleInvalid, entry.WorkPatternID)
+			}
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.UserWorkPattern{}).
+			Where("user_id = ? AND effective_from = ?", user.ID, entry.EffectiveFrom).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: a work pattern is already effective on that date", errScheduleInvalid)
+		}
+
+		// the pattern applies to every later payroll as well
+		if err := checkPayrollNotLocked(tx, entry.EffectiveFrom, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)); err != nil {
+			return err
+		}
+
+		return tx.Omit("WorkPattern").Create(&entry).Error
+	})
+	if errors.Is(err, errScheduleInvalid) || errors.Is(err, errPayrollLocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign work pattern"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toUserWorkPatternResponse(entry)))
+}
+
+// ListUserWorkPatterns godoc
+// @Summary      Get employee work patterns
+// @Description  Lists the work patterns the employee was put on, oldest first.
+// @Tags         Users
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.UserWorkPatternResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/work-patterns [get]
+func ListUserWorkPatterns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	schedule, err := findSchedule(db.DB, uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get work patterns"})
+		return
+	}
+
+	resp := make([]dto.UserWorkPatternResponse, 0, len(schedule))
+	for _, entry := range schedule {
+		resp = append(resp, toUserWorkPatternResponse(entry))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// GetSchedule godoc
+// @Summary      Get own schedule
+// @Description  Lists the current user's shift on every day from start to end, dates formatted as 2006-01-02.
+// @Description  Defaults to the 7 days from today; at most 62 days at once. Rest days have no shift.
+// @Tags         Attendance
+// @Produce      json
+// @Param        start  query     string  false  "First day"
+// @Param        end    query     string  false  "Last day"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.ScheduleDayResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendances/schedule [get]
+func GetSchedule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	loc, err := userLocation(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get schedule"})
+		return
+	}
+	start := utils.CalendarDate(time.Now(), loc)
+	if v := c.Query("start"); v != "" {
+		if start, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a date such as 2025-06-01"})
+			return
+		}
+	}
+	end := start.AddDate(0, 0, 6)
+	if v := c.Query("end"); v != "" {
+		if end, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a date such as 2025-06-30"})
+			return
+		}
+	}
+	if end.Before(start) || end.Sub(start) >= maxScheduleDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("end must be on or after start and within %d days of it", maxScheduleDays)})
+		return
+	}
+
+	schedule, err := findSchedule(db.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get schedule"})
+		return
+	}
+	var holidays []models.Holiday
+	if err := db.DB.Where("date BETWEEN ? AND ?", start, end).Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get schedule"})
+		return
+	}
+	holidayNames := make(map[string]string, len(holidays))
+	for _, holiday := range holidays {
+		holidayNames[holiday.DateOnlyString()] = holiday.Name
+	}
+
+	resp := make([]dto.ScheduleDayResponse, 0)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		day := dto.ScheduleDayResponse{Date: d.Format("2006-01-02"), Holiday: holidayNames[d.Format("2006-01-02")]}
+		if shift := schedule.ShiftOn(d); shift != nil {
+			s := toShiftResponse(*shift)
+			day.Shift = &s
+		}
+		resp = append(resp, day)
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+// formatTimeOfDay formats a duration since midnight as a wall clock time, the way shifts store them.
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// findSchedule returns the work patterns of the user with their shifts, oldest first.
+func findSchedule(tx *gorm.DB, userID uint) (models.Schedule, error) {
+	var schedule models.Schedule
+	if err := tx.Preload("WorkPattern.Days.Shift").
+		Where("user_id = ?", userID).
+		Order("effective_from").
+		Find(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func toShiftResponse(shift models.Shift) dto.ShiftResponse {
+	return dto.ShiftResponse{
+		ID:        shift.ID,
+		Name:      shift.Name,
+		StartTime: shift.StartTime,
+		EndTime:   shift.EndTime,
+		Hours:     shift.Hours,
+	}
+}
+
+func toWorkPatternResponse(pattern models.WorkPattern) dto.WorkPatternResponse {
+	shifts := make([]*uint, len(pattern.Days))
+	for _, day := range pattern.Days {
+		if day.Day >= 0 && day.Day < len(shifts) {
+			shifts[day.Day] = day.ShiftID
+		}
+	}
+	return dto.WorkPatternResponse{
+		ID:        pattern.ID,
+		Name:      pattern.Name,
+		StartDate: pattern.StartDate.Format("2006-01-02"),
+		Shifts:    shifts,
+	}
+}
+
+func toUserWorkPatternResponse(entry models.UserWorkPattern) dto.UserWorkPatternResponse {
+	return dto.UserWorkPatternResponse{
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		WorkPatternID: entry.WorkPatternID,
+		WorkPattern:   entry.WorkPattern.Name,
+		EffectiveFrom: entry.EffectiveFrom.Format("2006-01-02"),
+	}
+}
@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestRouterForSchedules() *gin.Engine {
+	r := setupTestRouterForLeaves()
+	r.POST("/shifts", handlers.CreateShift)
+	r.GET("/shifts", handlers.ListShifts)
+	r.POST("/work-patterns", handlers.CreateWorkPattern)
+	r.GET("/work-patterns", handlers.ListWorkPatterns)
+	r.POST("/users/:id/work-patterns", handlers.CreateUserWorkPattern)
+	r.GET("/users/:id/work-patterns", handlers.ListUserWorkPatterns)
+	r.GET("/attendances/schedule", handlers.GetSchedule)
+	return r
+}
+
+func TestWorkPatterns_Payroll(t *testing.T) {
+	r := setupTestRouterForSchedules()
+	d, cleanup, err := setupTestDBForLeaves()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	w := leaveRequest(r, 1, http.MethodPost, "/shifts", gin.H{"name": "early", "start_time": "6am", "end_time": "14:00", "hours": "7"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = leaveRequest(r, 1, http.MethodPost, "/shifts", gin.H{"name": "early", "start_time": "06:00", "end_time": "14:00", "hours": "9"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at most 8")
+
+	w = leaveRequest(r, 1, http.MethodPost, "/shifts", gin.H{"name": "early", "start_time": "06:00", "end_time": "14:00", "hours": "7"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var early dto.SuccessResponse[dto.ShiftResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &early))
+
+	// ends the next morning
+	w = leaveRequest(r, 1, http.MethodPost, "/shifts", gin.H{"name": "night", "start_time": "22:00", "end_time": "06:00", "hours": "7"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = leaveRequest(r, 1, http.MethodPost, "/work-patterns", gin.H{"name": "idle", "start_date": "2025-06-02T00:00:00Z", "shifts": []any{nil, nil}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at least one shift")
+
+	// Monday to Saturday
+	id := early.Data.ID
+	w = leaveRequest(r, 1, http.MethodPost, "/work-patterns", gin.H{
+		"name": "warehouse", "start_date": "2025-06-02T00:00:00Z", "shifts": []any{id, id, id, id, id, id, nil},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var pattern dto.SuccessResponse[dto.WorkPatternResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &pattern))
+	assert.Len(t, pattern.Data.Shifts, 7)
+	assert.Nil(t, pattern.Data.Shifts[6])
+
+	w = leaveRequest(r, 1, http.MethodPost, "/users/2/work-patterns", gin.H{"work_pattern_id": pattern.Data.ID, "effective_from": "2025-06-01T00:00:00Z"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = leaveRequest(r, 1, http.MethodPost, "/users/2/work-patterns", gin.H{"work_pattern_id": pattern.Data.ID, "effective_from": "2025-06-01T00:00:00Z"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = leaveRequest(r, 2, http.MethodGet, "/attendances/schedule?start=2025-06-06&end=2025-06-08", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var schedule dto.SuccessResponse[[]dto.ScheduleDayResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &schedule))
+	if assert.Len(t, schedule.Data, 3) {
+		assert.Equal(t, "early", schedule.Data[0].Shift.Name)
+		assert.Equal(t, "early", schedule.Data[1].Shift.Name)
+		assert.Nil(t, schedule.Data[2].Shift)
+	}
+
+	// Saturday counts as a leave day, Sunday does not
+	w = leaveRequest(r, 2, http.MethodPost, "/leaves", gin.H{
+		"leave_type": "annual", "start_date": "2025-06-14T00:00:00Z", "end_date": "2025-06-15T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var leave dto.SuccessResponse[dto.LeaveRequestResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &leave))
+	assert.Equal(t, 1, leave.Data.Days)
+	w = leaveRequest(r, 3, http.MethodPost, "/leaves/"+strconv.Itoa(int(leave.Data.ID))+"/approve", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	d.Create(&models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	for _, day := range []int{7, 9} {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 6, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 14, 0, 0, 0, time.UTC)),
+		})
+	}
+
+	w = leaveRequest(r, 1, http.MethodGet, "/payrolls/2025/6/preview", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var preview dto.SuccessResponse[dto.PayrollPreviewResponse]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &preview))
+
+	var rostered, standard dto.EmployeePayslipPreview
+	for _, payslip := range preview.Data.Payslips {
+		switch payslip.UserID {
+		case 2:
+			rostered = payslip
+		case 4:
+			standard = payslip
+		}
+	}
+	// June 2025 has 25 days from Monday to Saturday, 7 hours each
+	assert.Equal(t, 25, rostered.ExpectedWorkingDays)
+	assert.Equal(t, 2, rostered.DaysAttended)
+	assert.Equal(t, 1, rostered.PaidLeaveDays)
+	assert.Equal(t, 14.0, rostered.TotalHoursWorked)
+	// 2,100,000 / 175 hours
+	assert.Equal(t, "12000", rostered.HourlyRate.String())
+	// 2,100,000 * (2 attended + 1 leave) / 25
+	assert.Equal(t, "252000", rostered.BaseSalary.String())
+
+	// without a work pattern it is still Monday to Friday, 8 hours a day
+	assert.Equal(t, 21, standard.ExpectedWorkingDays)
+	assert.Equal(t, "12500", standard.HourlyRate.String())
+}
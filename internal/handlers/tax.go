@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dealls-case-study/internal/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// errRateTableMissing is returned when a payroll period has no rates to compute its payslips with.
+var errRateTableMissing = errors.New("missing rate table")
+
+type incomeTax struct {
+	Rate         decimal.Decimal
+	Amount       decimal.Decimal
+	Method       string
+	TableVersion string
+	PTKPStatus   string
+}
+
+// findTaxTable returns the latest tax table effective on the given date.
+func findTaxTable(tx *gorm.DB, date time.Time) (*models.TaxTable, error) {
+	var table models.TaxTable
+	err := tx.
+		Preload("Brackets").
+		Preload("PTKPs").
+		Where("effective_from <= ?", date).
+		Order("effective_from DESC").
+		First(&table).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no tax table effective on %s", errRateTableMissing, date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// withholdIncomeTax computes the PPh 21 withholding for a payslip.
+// January to November use the TER monthly rate on the month's gross taxable income,
+// December, and the final settlement of an employee leaving during the year, recompute
+// the annual tax on the year's income and withhold the difference (the true-up).
+// Deductions (the employee's pension contributions) only apply to the annual computation.
+func withholdIncomeTax(tx *gorm.DB, user models.User, payroll *models.Payroll, taxableIncome decimal.Decimal, deductions decimal.Decimal) (incomeTax, error) {
+	table, err := findTaxTable(tx, payroll.PeriodEnd)
+	if err != nil {
+		return incomeTax{}, err
+	}
+
+	ptkp, ok := table.PTKP(user.PTKPStatus)
+	if !ok {
+		return incomeTax{}, fmt.Errorf("unknown PTKP status %q for user %s", user.PTKPStatus, user.Username)
+	}
+
+	result := incomeTax{
+		Method:       models.TaxMethodTER,
+		TableVersion: table.Version,
+		PTKPStatus:   ptkp.Status,
+	}
+	hasNPWP := user.NPWP != ""
+
+	if payroll.Month != 12 && !user.TerminatedWithin(payroll.PeriodStart, payroll.PeriodEnd) {
+		result.Rate, result.Amount = table.MonthlyWithholding(ptkp, hasNPWP, taxableIncome)
+		return result, nil
+	}
+
+	var ytd struct {
+		TaxableIncome     decimal.Decimal
+		BPJSTaxDeductible decimal.Decimal
+		Tax               decimal.Decimal
+	}
+	if err := processedPayslipsOfYear(tx, user.ID, payroll.Year).
+		Select("COALESCE(SUM(payslips.taxable_income), 0) AS taxable_income, "+
+			"COALESCE(SUM(payslips.bpjs_tax_deductible), 0) AS bpjs_tax_deductible, "+
+			"COALESCE(SUM(payslips.tax), 0) AS tax").
+		Where("payslips.month < ?", payroll.Month).
+		Scan(&ytd).Error; err != nil {
+		return incomeTax{}, err
+	}
+
+	result.Method = models.TaxMethodAnnualTrueUp
+	result.Amount = table.AnnualWithholding(ptkp, hasNPWP,
+		ytd.TaxableIncome.Add(taxableIncome),
+		ytd.BPJSTaxDeductible.Add(deductions),
+		ytd.Tax)
+	return result, nil
+}
@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UpdateUserTaxProfile godoc
+// @Summary      Update employee tax profile
+// @Description  Sets the PTKP status (e.g. TK/0, K/1) and NPWP used for PPh 21 withholding.
+// @Description  Employees without an NPWP are withheld at a higher rate.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdateTaxProfileRequest true "Tax profile"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/tax-profile [put]
+func UpdateUserTaxProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdateTaxProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := findTaxTable(db.DB, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := table.PTKP(req.PTKPStatus); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PTKP status"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.PTKPStatus = req.PTKPStatus
+	if req.NPWP != nil {
+		user.NPWP = *req.NPWP
+	}
+	user.UpdatedBy = c.GetUint("user_id")
+
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tax profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+// UpdateUserEmployment godoc
+// @Summary      Update employee employment
+// @Description  Sets the employment status, hire date and termination date that decide payroll eligibility.
+// @Description  Inactive users are never paid. Joiners and leavers are paid for the days they are employed,
+// @Description  and the period containing the termination date produces a final settlement payslip.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdateEmploymentRequest true "Employment"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/employment [put]
+func UpdateUserEmployment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdateEmploymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.EmploymentStatus == models.EmploymentStatusTerminated && req.TerminationDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "termination_date is required for terminated employees"})
+		return
+	}
+	if req.EmploymentStatus != models.EmploymentStatusTerminated && req.TerminationDate != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "termination_date is only allowed for terminated employees"})
+		return
+	}
+	if req.HireDate != nil && req.TerminationDate != nil && req.TerminationDate.Before(*req.HireDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "termination_date must not be before hire_date"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.EmploymentStatus = req.EmploymentStatus
+	user.HireDate = nil
+	if req.HireDate != nil {
+		hireDate := dateOnly(*req.HireDate)
+		user.HireDate = &hireDate
+	}
+	user.TerminationDate = nil
+	user.TerminationReason = ""
+	if req.TerminationDate != nil {
+		terminationDate := dateOnly(*req.TerminationDate)
+		user.TerminationDate = &terminationDate
+		user.TerminationReason = req.TerminationReason
+	}
+	user.UpdatedBy = c.GetUint("user_id")
+
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+func toUserResponse(user models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:         user.ID,
+		Username:   user.Username,
+		Salary:     user.Salary,
+		PTKPStatus: user.PTKPStatus,
+		NPWP:       user.NPWP,
+
+		EmploymentStatus:  user.EmploymentStatus,
+		HireDate:          user.HireDate,
+		TerminationDate:   user.TerminationDate,
+		TerminationReason: user.TerminationReason,
+		ManagerID:         user.ManagerID,
+		Timezone:          user.Timezone,
+		PayBasis:          user.PayBasis,
+
+		OvertimeMonthlyCap: user.OvertimeMonthlyCap,
+	}
+}
+
+// UpdateUserManager godoc
+// @Summary      Set employee manager
+// @Description  Sets the manager who reviews the employee's leave requests. A null manager_id removes the manager,
+// @Description  leaving the reviews to admins.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdateManagerRequest true "Manager"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/manager [put]
+func UpdateUserManager(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdateManagerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if req.ManagerID != nil {
+		if *req.ManagerID == user.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a user can't be their own manager"})
+			return
+		}
+		var manager models.User
+		if err := db.DB.First(&manager, *req.ManagerID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "manager not found"})
+			return
+		}
+	}
+
+	user.ManagerID = req.ManagerID
+	user.UpdatedBy = c.GetUint("user_id")
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update manager"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+// UpdateUserTimezone godoc
+// @Summary      Set employee timezone
+// @Description  Sets the IANA timezone, e.g. Asia/Makassar, the employee's attendance, overtime and reimbursement dates
+// @Description  are taken in. An empty timezone falls back to the company timezone.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdateTimezoneRequest true "Timezone"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/timezone [put]
+func UpdateUserTimezone(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdateTimezoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// time.LoadLocation also accepts "Local", which is whatever zone the server runs in
+	if _, err := utils.LoadTimezone(req.Timezone); err != nil || req.Timezone == "Local" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown timezone " + req.Timezone})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.Timezone = req.Timezone
+	user.UpdatedBy = c.GetUint("user_id")
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update timezone"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+// UpdateUserPayBasis godoc
+// @Summary      Set employee pay basis
+// @Description  Sets what the employee's salary pays for: a month (the default), an hour or a day of work.
+// @Description  Hourly employees are paid the hours between check-in and check-out, daily employees the days attended,
+// @Description  both with paid leave days at their shift hours. The salary is the rate, so change it too when switching.
+// @Description  Payrolls use the pay basis the employee has when they run.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdatePayBasisRequest true "Pay basis"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/pay-basis [put]
+func UpdateUserPayBasis(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdatePayBasisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.PayBasis = req.PayBasis
+	user.UpdatedBy = c.GetUint("user_id")
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pay basis"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+// UpdateUserOvertimeCap godoc
+// @Summary      Set employee overtime cap
+// @Description  Sets the most overtime hours the employee can submit in a calendar month, 0 for no cap.
+// @Description  A null overtime_monthly_cap falls back to the company cap, OVERTIME_MONTHLY_CAP.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.UpdateOvertimeCapRequest true "Monthly overtime cap"
+// @Success      200    {object}  dto.SuccessResponse[dto.UserResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/overtime-cap [put]
+func UpdateUserOvertimeCap(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.UpdateOvertimeCapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.OvertimeMonthlyCap = req.OvertimeMonthlyCap
+	user.UpdatedBy = c.GetUint("user_id")
+	if err := db.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update overtime cap"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(toUserResponse(user)))
+}
+
+// userLocation is the timezone the user's attendance dates are taken in, the company's when the
+// user has none.
+func userLocation(tx *gorm.DB, userID uint) (*time.Location, error) {
+	var user models.User
+	err := tx.Select("id", "timezone").First(&user, userID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return utils.LoadTimezone(user.Timezone)
+}
+
+// canReview reports whether the current user may approve or reject a request of the given employee:
+// admins can review anyone's requests, managers their reports', nobody their own.
+func canReview(c *gin.Context, employee models.User) bool {
+	reviewerID := c.GetUint("user_id")
+	if employee.ID == reviewerID {
+		return false
+	}
+	if c.GetString("role") == "Admin" {
+		return true
+	}
+	return employee.ManagerID != nil && *employee.ManagerID == reviewerID
+}
+
+// CreateUserSalary godoc
+// @Summary      Change employee salary
+// @Description  Records a new monthly salary effective from a date. Payrolls prorate a salary change
+// @Description  within their period; payslips already generated keep the salary they were computed with.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Param        request body     dto.CreateSalaryRequest true "Salary"
+// @Success      201    {object}  dto.SuccessResponse[dto.SalaryHistoryResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/salaries [post]
+func CreateUserSalary(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req dto.CreateSalaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Salary.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "salary must be greater than 0"})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	effectiveFrom := dateOnly(req.EffectiveFrom)
+
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.SalaryHistory{}).Where("user_id = ? AND effective_from = ?", user.ID, effectiveFrom).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a salary change is already effective on that date"})
+		return
+	}
+
+	entry := models.SalaryHistory{
+		UserID:        user.ID,
+		Salary:        req.Salary,
+		EffectiveFrom: effectiveFrom,
+		Reason:        req.Reason,
+		CreatedBy:     adminID,
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		var history int64
+		if err := tx.Model(&models.SalaryHistory{}).Where("user_id = ?", user.ID).Count(&history).Error; err != nil {
+			return err
+		}
+		// keep the salary the user had so far for the days before the change
+		baselineFrom := dateOnly(user.CreatedAt)
+		if history == 0 && user.Salary.IsPositive() && baselineFrom.Before(effectiveFrom) {
+			baseline := models.SalaryHistory{
+				UserID:        user.ID,
+				Salary:        user.Salary,
+				EffectiveFrom: baselineFrom,
+				Reason:        "initial salary",
+				CreatedBy:     adminID,
+			}
+			if err := tx.Create(&baseline).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+
+		current, err := currentSalary(tx, user.ID, time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// only future changes so far
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&user).Updates(map[string]any{"salary": current.Salary, "updated_by": adminID}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change salary"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.WrapSuccessResponse(toSalaryHistoryResponse(entry)))
+}
+
+// ListUserSalaries godoc
+// @Summary      Get employee salary history
+// @Description  Lists the employee's salary changes, oldest first.
+// @Tags         Users
+// @Produce      json
+// @Param        id     path      int  true  "User ID"
+// @Success      200    {object}  dto.SuccessResponse[[]dto.SalaryHistoryResponse]
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/salaries [get]
+func ListUserSalaries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var history []models.SalaryHistory
+	if err := db.DB.Where("user_id = ?", id).Order("effective_from").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get salary history"})
+		return
+	}
+
+	resp := make([]dto.SalaryHistoryResponse, 0, len(history))
+	for _, entry := range history {
+		resp = append(resp, toSalaryHistoryResponse(entry))
+	}
+
+	c.JSON(http.StatusOK, utils.WrapSuccessResponse(resp))
+}
+
+func toSalaryHistoryResponse(entry models.SalaryHistory) dto.SalaryHistoryResponse {
+	return dto.SalaryHistoryResponse{
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		Salary:        entry.Salary,
+		EffectiveFrom: entry.EffectiveFromString(),
+		Reason:        entry.Reason,
+	}
+}
+
+// dateOnly truncates a timestamp to midnight UTC of its calendar date.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
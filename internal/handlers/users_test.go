@@ -0,0 +1,327 @@
+package handlers_test
+
+import (
+	"bytes"
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/dto"
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func AuthStubMiddlewareForUsers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("role", "Admin")
+		c.Next()
+	}
+}
+
+func setupTestRouterForUsers() *gin.Engine {
+	r := gin.Default()
+	r.PUT("/users/:id/tax-profile", AuthStubMiddlewareForUsers(), handlers.UpdateUserTaxProfile)
+	r.PUT("/users/:id/employment", AuthStubMiddlewareForUsers(), handlers.UpdateUserEmployment)
+	r.POST("/users/:id/salaries", AuthStubMiddlewareForUsers(), handlers.CreateUserSalary)
+	r.GET("/users/:id/salaries", AuthStubMiddlewareForUsers(), handlers.ListUserSalaries)
+	r.PUT("/users/:id/timezone", AuthStubMiddlewareForUsers(), handlers.UpdateUserTimezone)
+	r.GET("/payrolls/:year/:month/preview", AuthStubMiddlewareForUsers(), handlers.PreviewPayroll)
+	return r
+}
+
+func setupTestDBForUsers() (*gorm.DB, func(), error) {
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	employee := models.User{
+		ID:        2,
+		Username:  "employee",
+		Password:  "password",
+		RoleID:    2,
+		Salary:    decimal.NewFromInt(10000000),
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := d.Create(&employee).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return d, cleanup, nil
+}
+
+func TestUpdateUserTaxProfile_Success(t *testing.T) {
+	r := setupTestRouterForUsers()
+	d, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	body, _ := json.Marshal(map[string]string{"ptkp_status": "K/1", "npwp": "01.234.567.8-901.000"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/2/tax-profile", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.UserResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "K/1", resp.Data.PTKPStatus)
+	assert.Equal(t, "01.234.567.8-901.000", resp.Data.NPWP)
+
+	var user models.User
+	d.First(&user, 2)
+	assert.Equal(t, "K/1", user.PTKPStatus)
+	assert.Equal(t, uint(1), user.UpdatedBy)
+}
+
+func TestUpdateUserTaxProfile_InvalidStatus(t *testing.T) {
+	r := setupTestRouterForUsers()
+	_, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	body, _ := json.Marshal(map[string]string{"ptkp_status": "K/9"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/2/tax-profile", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid PTKP status")
+}
+
+func TestUpdateUserTaxProfile_NotFound(t *testing.T) {
+	r := setupTestRouterForUsers()
+	_, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	body, _ := json.Marshal(map[string]string{"ptkp_status": "TK/0"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/99/tax-profile", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateUserSalary_ProratesPayroll(t *testing.T) {
+	r := setupTestRouterForUsers()
+	d, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	d.Model(&models.User{}).Where("id = ?", 2).Update("salary", decimal.NewFromInt(2100000))
+
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+	for _, day := range []int{2, 16} {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 17, 0, 0, 0, time.UTC)),
+		})
+	}
+
+	body, _ := json.Marshal(map[string]string{"salary": "4200000", "effective_from": "2025-06-16T00:00:00Z", "reason": "promotion"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/2/salaries", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// the salary the user had so far is kept as the first entry
+	var history []models.SalaryHistory
+	d.Where("user_id = ?", 2).Order("effective_from").Find(&history)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "2100000", history[0].Salary.String())
+
+	var user models.User
+	d.First(&user, 2)
+	assert.Equal(t, "4200000", user.Salary.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+
+	p := resp.Data.Payslips[0]
+	// June 2-13 (10 working days) at 2,100,000 and June 16-30 (11 working days) at 4,200,000
+	assert.Equal(t, "3200000", p.MonthlySalary.String())
+	// one day at each salary: 100,000 + 200,000
+	assert.Equal(t, "300000", p.BaseSalary.String())
+	assert.Len(t, p.SalaryBreakdown, 2)
+	assert.Equal(t, 10, p.SalaryBreakdown[0].ExpectedWorkingDays)
+	assert.Equal(t, "100000", p.SalaryBreakdown[0].Amount.String())
+	assert.Equal(t, "2025-06-16", p.SalaryBreakdown[1].EffectiveFrom)
+	assert.Equal(t, "200000", p.SalaryBreakdown[1].Amount.String())
+}
+
+func TestCreateUserSalary_DuplicateDate(t *testing.T) {
+	r := setupTestRouterForUsers()
+	_, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	for _, expected := range []int{http.StatusCreated, http.StatusBadRequest} {
+		body, _ := json.Marshal(map[string]string{"salary": "12000000", "effective_from": "2025-07-01T00:00:00Z"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users/2/salaries", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+	}
+}
+
+func TestUpdateUserEmployment_FinalSettlement(t *testing.T) {
+	r := setupTestRouterForUsers()
+	d, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	d.Create(&payroll)
+	for _, day := range []int{2, 16} {
+		d.Create(&models.Attendance{
+			UserID:     2,
+			Date:       time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC),
+			CheckInAt:  timePtr(time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)),
+			CheckOutAt: timePtr(time.Date(2025, 6, day, 17, 0, 0, 0, time.UTC)),
+		})
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"employment_status":  "terminated",
+		"hire_date":          "2024-01-01T00:00:00Z",
+		"termination_date":   "2025-06-13T00:00:00Z",
+		"termination_reason": "resignation",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/2/employment", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/payrolls/2025/6/preview", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.PayrollPreviewResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Len(t, resp.Data.Payslips, 1)
+
+	p := resp.Data.Payslips[0]
+	assert.Equal(t, models.PayslipKindFinalSettlement, p.Kind)
+	assert.Equal(t, 21, p.ExpectedWorkingDays)
+	assert.Equal(t, 10, p.EmployedWorkingDays)
+	// the attendance after the termination date is not paid
+	assert.Equal(t, 1, p.DaysAttended)
+	// leavers get the annual computation in their last period
+	assert.Equal(t, models.TaxMethodAnnualTrueUp, p.TaxMethod)
+
+	// not eligible for the next period
+	d.Create(&models.Payroll{
+		Month:       7,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/payrolls/2025/7/preview", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Data.Payslips)
+}
+
+func TestUpdateUserEmployment_TerminatedRequiresDate(t *testing.T) {
+	r := setupTestRouterForUsers()
+	_, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	body, _ := json.Marshal(map[string]string{"employment_status": "terminated"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/2/employment", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "termination_date is required")
+}
+
+func TestUpdateUserTimezone(t *testing.T) {
+	r := setupTestRouterForUsers()
+	d, cleanup, err := setupTestDBForUsers()
+	if err != nil {
+		t.Fatalf("DB setup failed: %v", err)
+	}
+	defer cleanup()
+
+	for _, timezone := range []string{"WITA", "Local"} {
+		w := postJSON(r, http.MethodPut, "/users/2/timezone", gin.H{"timezone": timezone})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown timezone")
+	}
+
+	w := postJSON(r, http.MethodPut, "/users/2/timezone", gin.H{"timezone": "Asia/Makassar"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.SuccessResponse[dto.UserResponse]
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "Asia/Makassar", resp.Data.Timezone)
+
+	var user models.User
+	d.First(&user, 2)
+	assert.Equal(t, "Asia/Makassar", user.Timezone)
+
+	// an empty timezone goes back to the company's
+	w = postJSON(r, http.MethodPut, "/users/2/timezone", gin.H{"timezone": ""})
+	assert.Equal(t, http.StatusOK, w.Code)
+	d.First(&user, 2)
+	assert.Empty(t, user.Timezone)
+}
@@ -7,10 +7,10 @@ import (
 )
 
 type Attendance struct {
-	ID          uint `gorm:"primaryKey"`
-	UserID      uint
-	User        User `gorm:"foreignKey:UserID"`
-	Date        time.Time
+	ID          uint       `gorm:"primaryKey"`
+	UserID      uint       `gorm:"uniqueIndex:idx_attendances_user_date"`
+	User        User       `gorm:"foreignKey:UserID"`
+	Date        time.Time  `gorm:"uniqueIndex:idx_attendances_user_date"`
 	CheckInAt   *time.Time `gorm:"default:null"`
 	CheckOutAt  *time.Time `gorm:"default:null"`
 	HoursWorked float64    `gorm:"not null"`
@@ -18,6 +18,11 @@ type Attendance struct {
 	CreatedBy   uint
 	UpdatedAt   time.Time
 	UpdatedBy   uint
+
+	// AutoClosedAt is when the attendance job checked the employee out at the shift end
+	AutoClosedAt *time.Time
+	// Anomaly is why the attendance needs review, e.g. a check-out the job could not fill in
+	Anomaly string `gorm:"not null;default:''"`
 }
 
 func (a *Attendance) DateOnlyString() string {
@@ -25,6 +30,8 @@ func (a *Attendance) DateOnlyString() string {
 }
 
 func (a *Attendance) BeforeSave(tx *gorm.DB) (err error) {
+	// a correction can remove the check-out again
+	a.HoursWorked = 0
 	if a.CheckInAt != nil && a.CheckOutAt != nil {
 		duration := a.CheckOutAt.Sub(*a.CheckInAt).Hours()
 		if duration < 0 {
@@ -34,3 +41,68 @@ func (a *Attendance) BeforeSave(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+const (
+	AttendanceAuditCreate = "create"
+	AttendanceAuditUpdate = "update"
+	AttendanceAuditVoid   = "void"
+)
+
+// AttendanceAudit records a correction of an attendance with the values before and after it.
+// Before is empty for a created attendance, after for a voided one, which is deleted.
+type AttendanceAudit struct {
+	ID           uint   `gorm:"primaryKey"`
+	AttendanceID uint   `gorm:"not null;index"`
+	UserID       uint   `gorm:"not null;index"`
+	Action       string `gorm:"not null"`
+	Reason       string `gorm:"not null"`
+	// CorrectionRequestID is the employee's request the correction was approved from, if any
+	CorrectionRequestID *uint
+
+	BeforeDate       *time.Time
+	BeforeCheckInAt  *time.Time
+	BeforeCheckOutAt *time.Time
+	AfterDate        *time.Time
+	AfterCheckInAt   *time.Time
+	AfterCheckOutAt  *time.Time
+
+	CreatedAt time.Time
+	CreatedBy uint
+}
+
+const (
+	CorrectionStatusPending   = "pending"
+	CorrectionStatusApproved  = "approved"
+	CorrectionStatusRejected  = "rejected"
+	CorrectionStatusCancelled = "cancelled"
+)
+
+// AttendanceCorrectionRequest is an employee's request to fix their attendance on a past date.
+// A nil CheckInAt or CheckOutAt keeps what the attendance has, approving the request applies it
+// like an admin correction.
+type AttendanceCorrectionRequest struct {
+	ID uint `gorm:"primaryKey"`
+	// an employee has at most one pending request a date
+	UserID     uint      `gorm:"not null;index;uniqueIndex:idx_correction_requests_pending,where:status = 'pending'"`
+	User       User      `gorm:"foreignKey:UserID"`
+	Date       time.Time `gorm:"not null;uniqueIndex:idx_correction_requests_pending,where:status = 'pending'"`
+	CheckInAt  *time.Time
+	CheckOutAt *time.Time
+	Reason     string `gorm:"not null"`
+	Status     string `gorm:"not null;default:'pending';index"`
+
+	ReviewedBy *uint
+	ReviewedAt *time.Time
+	ReviewNote string
+	// AttendanceID is the attendance the approved request created or corrected
+	AttendanceID *uint
+
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
+}
+
+func (r *AttendanceCorrectionRequest) DateOnlyString() string {
+	return r.Date.Format("2006-01-02")
+}
@@ -53,3 +53,16 @@ func TestAttendance_BeforeSave_NoCheckOut(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0.0, a.HoursWorked)
 }
+
+func TestAttendance_BeforeSave_CheckOutRemoved(t *testing.T) {
+	checkIn := time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC)
+
+	a := Attendance{
+		CheckInAt:   &checkIn,
+		HoursWorked: 8,
+	}
+
+	err := a.BeforeSave(&gorm.DB{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, a.HoursWorked)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BPJSProgramJHT       = "jht"
+	BPJSProgramJP        = "jp"
+	BPJSProgramJKK       = "jkk"
+	BPJSProgramJKM       = "jkm"
+	BPJSProgramKesehatan = "kesehatan"
+)
+
+// BPJSRateTable is a versioned set of BPJS Ketenagakerjaan and Kesehatan contribution rates.
+// The latest table effective on a payroll's period end is used, so a yearly cap
+// adjustment is a new row rather than a code change.
+type BPJSRateTable struct {
+	ID            uint      `gorm:"primaryKey"`
+	Version       string    `gorm:"uniqueIndex;not null"`
+	EffectiveFrom time.Time `gorm:"not null"`
+	CreatedAt     time.Time
+	CreatedBy     uint
+	UpdatedAt     time.Time
+	UpdatedBy     uint
+
+	Rates []BPJSRate `gorm:"foreignKey:BPJSRateTableID"`
+}
+
+// BPJSRate is the employee and employer rate of a single program. Contributions are
+// computed on the monthly salary, capped at SalaryCap when it is set.
+type BPJSRate struct {
+	ID              uint                `gorm:"primaryKey"`
+	BPJSRateTableID uint                `gorm:"index"`
+	Program         string              `gorm:"not null"`
+	EmployeeRate    decimal.Decimal     `gorm:"type:numeric(7,4);not null"`
+	EmployerRate    decimal.Decimal     `gorm:"type:numeric(7,4);not null"`
+	SalaryCap       decimal.NullDecimal `gorm:"type:numeric(20,2)"`
+
+	// employer-paid premiums that are income of the employee for PPh 21
+	EmployerTaxable bool `gorm:"not null;default:false"`
+	// employee-paid contributions that reduce annual taxable income (iuran pensiun / JHT)
+	EmployeeTaxDeductible bool `gorm:"not null;default:false"`
+}
+
+// BPJSContribution is the computed contribution of a single program.
+type BPJSContribution struct {
+	Program               string
+	Base                  decimal.Decimal
+	EmployeeRate          decimal.Decimal
+	EmployerRate          decimal.Decimal
+	EmployeeAmount        decimal.Decimal
+	EmployerAmount        decimal.Decimal
+	EmployerTaxable       bool
+	EmployeeTaxDeductible bool
+}
+
+// Contributions computes the contributions of every program for a monthly salary.
+func (t *BPJSRateTable) Contributions(salary decimal.Decimal) []BPJSContribution {
+	var contributions []BPJSContribution
+	for _, r := range t.Rates {
+		base := salary
+		if r.SalaryCap.Valid && base.GreaterThan(r.SalaryCap.Decimal) {
+			base = r.SalaryCap.Decimal
+		}
+		contributions = append(contributions, BPJSContribution{
+			Program:               r.Program,
+			Base:                  base,
+			EmployeeRate:          r.EmployeeRate,
+			EmployerRate:          r.EmployerRate,
+			EmployeeAmount:        base.Mul(r.EmployeeRate),
+			EmployerAmount:        base.Mul(r.EmployerRate),
+			EmployerTaxable:       r.EmployerTaxable,
+			EmployeeTaxDeductible: r.EmployeeTaxDeductible,
+		})
+	}
+	return contributions
+}
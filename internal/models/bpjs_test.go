@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBPJSRateTable_Contributions(t *testing.T) {
+	table := BPJSRateTable{
+		Rates: []BPJSRate{
+			{Program: BPJSProgramJHT, EmployeeRate: decimal.RequireFromString("0.02"), EmployerRate: decimal.RequireFromString("0.037")},
+			{
+				Program:      BPJSProgramKesehatan,
+				EmployeeRate: decimal.RequireFromString("0.01"),
+				EmployerRate: decimal.RequireFromString("0.04"),
+				SalaryCap:    decimal.NewNullDecimal(decimal.NewFromInt(12000000)),
+			},
+		},
+	}
+
+	contributions := table.Contributions(decimal.NewFromInt(20000000))
+	assert.Len(t, contributions, 2)
+
+	jht := contributions[0]
+	assert.Equal(t, "20000000", jht.Base.String())
+	assert.Equal(t, "400000", jht.EmployeeAmount.String())
+	assert.Equal(t, "740000", jht.EmployerAmount.String())
+
+	// capped at 12,000,000
+	kesehatan := contributions[1]
+	assert.Equal(t, "12000000", kesehatan.Base.String())
+	assert.Equal(t, "120000", kesehatan.EmployeeAmount.String())
+	assert.Equal(t, "480000", kesehatan.EmployerAmount.String())
+
+	// below the cap the salary itself is the base
+	contributions = table.Contributions(decimal.NewFromInt(5000000))
+	assert.Equal(t, "5000000", contributions[1].Base.String())
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	HolidaySourceManual = "manual"
+	HolidaySourceICS    = "ics"
+)
+
+// Holiday is a public holiday: not a working day, no check-ins, and overtime on it is paid
+// at the holiday rate.
+type Holiday struct {
+	ID     uint      `gorm:"primaryKey"`
+	Date   time.Time `gorm:"not null;uniqueIndex"`
+	Name   string    `gorm:"not null"`
+	Source string    `gorm:"not null;default:'manual'"`
+	// UID of the imported calendar event
+	ExternalUID string
+	CreatedAt   time.Time
+	CreatedBy   uint
+	UpdatedAt   time.Time
+	UpdatedBy   uint
+}
+
+func (h *Holiday) DateOnlyString() string {
+	return h.Date.Format("2006-01-02")
+}
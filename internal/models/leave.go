@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+const (
+	LeaveTypeAnnual    = "annual"
+	LeaveTypeSick      = "sick"
+	LeaveTypeUnpaid    = "unpaid"
+	LeaveTypeMaternity = "maternity"
+)
+
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// LeaveType is a kind of leave. Paid leave days are paid as attended days, unpaid leave
+// days are deducted from the base salary.
+type LeaveType struct {
+	ID   uint   `gorm:"primaryKey"`
+	Code string `gorm:"uniqueIndex;not null"`
+	Name string `gorm:"not null"`
+	Paid bool   `gorm:"not null"`
+	// working days accrued every year, 0 means the leave is not limited by a balance
+	AnnualEntitlement int `gorm:"not null"`
+	CreatedAt         time.Time
+	CreatedBy         uint
+	UpdatedAt         time.Time
+	UpdatedBy         uint
+}
+
+// HasBalance reports whether requests are limited by a yearly balance.
+func (t *LeaveType) HasBalance() bool {
+	return t.AnnualEntitlement > 0
+}
+
+// Entitlement is the number of days accrued in a year. Employees hired during the year
+// accrue for the remaining months, counting the month they were hired in.
+func (t *LeaveType) Entitlement(year int, hireDate *time.Time) int {
+	if hireDate == nil || hireDate.Year() < year {
+		return t.AnnualEntitlement
+	}
+	if hireDate.Year() > year {
+		return 0
+	}
+	months := 12 - int(hireDate.Month()) + 1
+	return t.AnnualEntitlement * months / 12
+}
+
+// LeaveBalance is the leave an employee accrued for a year. The days used and pending
+// are derived from the employee's leave requests.
+type LeaveBalance struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_leave_balances_user_type_year"`
+	User        User      `gorm:"foreignKey:UserID"`
+	LeaveTypeID uint      `gorm:"not null;uniqueIndex:idx_leave_balances_user_type_year"`
+	LeaveType   LeaveType `gorm:"foreignKey:LeaveTypeID"`
+	Year        int       `gorm:"not null;uniqueIndex:idx_leave_balances_user_type_year"`
+	Entitled    int       `gorm:"not null"`
+	CreatedAt   time.Time
+	CreatedBy   uint
+	UpdatedAt   time.Time
+	UpdatedBy   uint
+}
+
+// LeaveRequest is an employee's request for leave from StartDate to EndDate, inclusive.
+// Days counts the working days it covers.
+type LeaveRequest struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;index"`
+	User        User      `gorm:"foreignKey:UserID"`
+	LeaveTypeID uint      `gorm:"not null"`
+	LeaveType   LeaveType `gorm:"foreignKey:LeaveTypeID"`
+	StartDate   time.Time `gorm:"not null"`
+	EndDate     time.Time `gorm:"not null"`
+	Days        int       `gorm:"not null"`
+	Reason      string
+	Status      string `gorm:"not null;default:'pending';index"`
+
+	ReviewedBy *uint
+	ReviewedAt *time.Time
+	ReviewNote string
+
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
+}
+
+// Covers reports whether the request includes the given date.
+func (r *LeaveRequest) Covers(date time.Time) bool {
+	return !date.Before(r.StartDate) && !date.After(r.EndDate)
+}
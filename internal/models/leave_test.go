@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaveType_Entitlement(t *testing.T) {
+	annual := LeaveType{Code: LeaveTypeAnnual, AnnualEntitlement: 12}
+	datePtr := func(year int, month time.Month) *time.Time {
+		d := time.Date(year, month, 15, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	assert.Equal(t, 12, annual.Entitlement(2025, nil))
+	assert.Equal(t, 12, annual.Entitlement(2025, datePtr(2024, 10)))
+	assert.Equal(t, 12, annual.Entitlement(2025, datePtr(2025, 1)))
+	// hired in July, accrues July to December
+	assert.Equal(t, 6, annual.Entitlement(2025, datePtr(2025, 7)))
+	assert.Equal(t, 1, annual.Entitlement(2025, datePtr(2025, 12)))
+	assert.Equal(t, 0, annual.Entitlement(2025, datePtr(2026, 1)))
+
+	sick := LeaveType{Code: LeaveTypeSick}
+	assert.False(t, sick.HasBalance())
+	assert.True(t, annual.HasBalance())
+}
+
+func TestLeaveRequest_Covers(t *testing.T) {
+	r := LeaveRequest{
+		StartDate: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.False(t, r.Covers(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, r.Covers(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, r.Covers(time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, r.Covers(time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)))
+}
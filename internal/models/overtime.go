@@ -4,16 +4,31 @@ import (
 	"time"
 )
 
+const (
+	OvertimeStatusSubmitted = "submitted"
+	OvertimeStatusApproved  = "approved"
+	OvertimeStatusRejected  = "rejected"
+)
+
+// Overtime is only paid once approved by the employee's manager or an admin.
+// An employee submits at most one overtime a day.
 type Overtime struct {
-	ID          uint `gorm:"primaryKey"`
-	UserID      uint
-	User        User `gorm:"foreignKey:UserID"`
-	Date        time.Time
-	HoursWorked float64 `gorm:"not null" json:"hours_worked"`
-	CreatedAt   time.Time
-	CreatedBy   uint
-	UpdatedAt   time.Time
-	UpdatedBy   uint
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"uniqueIndex:idx_overtimes_user_date"`
+	User        User      `gorm:"foreignKey:UserID"`
+	Date        time.Time `gorm:"uniqueIndex:idx_overtimes_user_date"`
+	HoursWorked float64   `gorm:"not null" json:"hours_worked"`
+
+	Status       string `gorm:"not null;default:'submitted';index"`
+	ReviewedBy   *uint
+	Reviewer     *User `gorm:"foreignKey:ReviewedBy"`
+	ReviewedAt   *time.Time
+	ReviewReason string
+
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
 }
 
 func (o *Overtime) DateOnlyString() string {
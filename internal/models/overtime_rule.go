@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	OvertimeDayWeekday = "weekday"
+	OvertimeDayRestDay = "rest_day"
+	OvertimeDayHoliday = "holiday"
+)
+
+// OvertimeRuleSet is a versioned set of overtime multipliers. Every overtime entry is paid
+// by the latest set effective on its date, so a change in regulation is a new row and
+// payrolls already processed keep their rates when they are run again.
+type OvertimeRuleSet struct {
+	ID            uint      `gorm:"primaryKey"`
+	Version       string    `gorm:"uniqueIndex;not null"`
+	EffectiveFrom time.Time `gorm:"not null"`
+	CreatedAt     time.Time
+	CreatedBy     uint
+	UpdatedAt     time.Time
+	UpdatedBy     uint
+
+	Rules []OvertimeRule `gorm:"foreignKey:OvertimeRuleSetID"`
+}
+
+// OvertimeRule is the multiplier of the hourly rate for the overtime hours of a day type
+// from FromHour up to ToHour. The last tier of a day type has no ToHour.
+type OvertimeRule struct {
+	ID                uint                `gorm:"primaryKey"`
+	OvertimeRuleSetID uint                `gorm:"index"`
+	DayType           string              `gorm:"not null"`
+	FromHour          decimal.Decimal     `gorm:"type:numeric(5,2);not null"`
+	ToHour            decimal.NullDecimal `gorm:"type:numeric(5,2)"`
+	Multiplier        decimal.Decimal     `gorm:"type:numeric(5,2);not null"`
+}
+
+// OvertimeTier is the part of an overtime entry paid at one multiplier.
+type OvertimeTier struct {
+	Hours      decimal.Decimal
+	Multiplier decimal.Decimal
+}
+
+// Tiers splits the hours worked on a day type across its rules. Hours past the last
+// bounded rule are paid at the last rule's multiplier.
+func (s *OvertimeRuleSet) Tiers(dayType string, hours decimal.Decimal) []OvertimeTier {
+	var rules []OvertimeRule
+	for _, r := range s.Rules {
+		if r.DayType == dayType {
+			rules = append(rules, r)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].FromHour.LessThan(rules[j].FromHour) })
+
+	var tiers []OvertimeTier
+	for i, r := range rules {
+		if !hours.GreaterThan(r.FromHour) {
+			break
+		}
+		to := hours
+		if r.ToHour.Valid && i < len(rules)-1 && r.ToHour.Decimal.LessThan(hours) {
+			to = r.ToHour.Decimal
+		}
+		tiers = append(tiers, OvertimeTier{Hours: to.Sub(r.FromHour), Multiplier: r.Multiplier})
+	}
+	return tiers
+}
+
+// FirstMultiplier is the multiplier of the first overtime hour on a day type, zero when
+// the set has no rule for it.
+func (s *OvertimeRuleSet) FirstMultiplier(dayType string) decimal.Decimal {
+	if tiers := s.Tiers(dayType, decimal.NewFromFloat(0.01)); len(tiers) > 0 {
+		return tiers[0].Multiplier
+	}
+	return decimal.Zero
+}
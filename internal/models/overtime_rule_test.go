@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertimeRuleSet_Tiers(t *testing.T) {
+	hours := func(h float64) decimal.Decimal { return decimal.NewFromFloat(h) }
+	bound := func(h float64) decimal.NullDecimal { return decimal.NewNullDecimal(hours(h)) }
+
+	set := OvertimeRuleSet{Rules: []OvertimeRule{
+		{DayType: OvertimeDayWeekday, FromHour: hours(1), Multiplier: hours(2)},
+		{DayType: OvertimeDayWeekday, FromHour: hours(0), ToHour: bound(1), Multiplier: hours(1.5)},
+		{DayType: OvertimeDayRestDay, FromHour: hours(0), ToHour: bound(8), Multiplier: hours(2)},
+		{DayType: OvertimeDayRestDay, FromHour: hours(8), ToHour: bound(9), Multiplier: hours(3)},
+	}}
+
+	format := func(tiers []OvertimeTier) []string {
+		out := []string{}
+		for _, tier := range tiers {
+			out = append(out, tier.Hours.String()+"h x"+tier.Multiplier.String())
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"0.5h x1.5"}, format(set.Tiers(OvertimeDayWeekday, hours(0.5))))
+	assert.Equal(t, []string{"1h x1.5", "1.5h x2"}, format(set.Tiers(OvertimeDayWeekday, hours(2.5))))
+	assert.Equal(t, []string{"2h x2"}, format(set.Tiers(OvertimeDayRestDay, hours(2))))
+	// hours past the last bounded rule stay at its multiplier
+	assert.Equal(t, []string{"8h x2", "2h x3"}, format(set.Tiers(OvertimeDayRestDay, hours(10))))
+	assert.Empty(t, set.Tiers(OvertimeDayHoliday, hours(2)))
+
+	assert.Equal(t, "1.5", set.FirstMultiplier(OvertimeDayWeekday).String())
+	assert.True(t, set.FirstMultiplier(OvertimeDayHoliday).IsZero())
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PayComponentTypeEarning   = "earning"
+	PayComponentTypeDeduction = "deduction"
+
+	PayComponentCalculationFixed   = "fixed"
+	PayComponentCalculationFormula = "formula"
+)
+
+// PayComponent is a configurable allowance (transport, meal, housing) or deduction
+// (loan repayment, unpaid leave). It is paid to the employees and roles it is assigned to.
+//
+// Taxable earnings add to PPh 21 taxable income, taxable deductions reduce it.
+// Recurring components are paid every period an assignment is effective,
+// one-off components only in the period containing the assignment's EffectiveFrom.
+type PayComponent struct {
+	ID              uint   `gorm:"primaryKey"`
+	Code            string `gorm:"uniqueIndex;not null"`
+	Name            string `gorm:"not null"`
+	Type            string `gorm:"not null"`
+	CalculationType string `gorm:"not null;default:'fixed'"`
+	// fixed amount, or the value of the "amount" variable in a formula
+	Amount    decimal.Decimal `gorm:"type:numeric(20,2);not null;default:0"`
+	Formula   string
+	Taxable   bool `gorm:"not null;default:false"`
+	Recurring bool `gorm:"not null"`
+	Active    bool `gorm:"not null"`
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
+}
+
+// PayComponentAssignment assigns a component to a single user or to every user of a role.
+// A user assignment takes precedence over a role assignment of the same component.
+type PayComponentAssignment struct {
+	ID             uint         `gorm:"primaryKey"`
+	PayComponentID uint         `gorm:"index;not null"`
+	PayComponent   PayComponent `gorm:"foreignKey:PayComponentID"`
+	UserID         *uint        `gorm:"index"`
+	RoleID         *uint        `gorm:"index"`
+	// overrides the component's amount for this assignment
+	Amount        decimal.NullDecimal `gorm:"type:numeric(20,2)"`
+	EffectiveFrom time.Time           `gorm:"not null"`
+	EffectiveTo   *time.Time
+	CreatedAt     time.Time
+	CreatedBy     uint
+	UpdatedAt     time.Time
+	UpdatedBy     uint
+}
+
+// AppliesTo reports whether the assignment pays out in a payroll period.
+func (a *PayComponentAssignment) AppliesTo(periodStart, periodEnd time.Time) bool {
+	if !a.PayComponent.Recurring {
+		return !a.EffectiveFrom.Before(periodStart) && !a.EffectiveFrom.After(periodEnd)
+	}
+	if a.EffectiveFrom.After(periodEnd) {
+		return false
+	}
+	return a.EffectiveTo == nil || !a.EffectiveTo.Before(periodStart)
+}
+
+// PayslipLine is an itemized earning or deduction on a payslip. Lines generated from
+// a pay component reference it; statutory lines (base salary, tax, BPJS) do not.
+type PayslipLine struct {
+	ID             uint `gorm:"primaryKey"`
+	PayslipID      uint `gorm:"index;not null"`
+	PayComponentID *uint
+	Sequence       int             `gorm:"not null"`
+	Code           string          `gorm:"not null"`
+	Name           string          `gorm:"not null"`
+	Type           string          `gorm:"not null"`
+	Amount         decimal.Decimal `gorm:"type:numeric;not null"`
+	Taxable        bool            `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayComponentAssignment_AppliesTo(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	datePtr := func(month time.Month, day int) *time.Time {
+		d := date(month, day)
+		return &d
+	}
+
+	recurring := PayComponent{Recurring: true}
+	oneOff := PayComponent{Recurring: false}
+
+	tests := []struct {
+		name       string
+		assignment PayComponentAssignment
+		expected   bool
+	}{
+		{"recurring open-ended", PayComponentAssignment{PayComponent: recurring, EffectiveFrom: date(1, 1)}, true},
+		{"recurring starts after period", PayComponentAssignment{PayComponent: recurring, EffectiveFrom: date(7, 1)}, false},
+		{"recurring starts mid-period", PayComponentAssignment{PayComponent: recurring, EffectiveFrom: date(6, 15)}, true},
+		{"recurring ended before period", PayComponentAssignment{PayComponent: recurring, EffectiveFrom: date(1, 1), EffectiveTo: datePtr(5, 31)}, false},
+		{"recurring ends mid-period", PayComponentAssignment{PayComponent: recurring, EffectiveFrom: date(1, 1), EffectiveTo: datePtr(6, 1)}, true},
+		{"one-off in period", PayComponentAssignment{PayComponent: oneOff, EffectiveFrom: date(6, 30)}, true},
+		{"one-off before period", PayComponentAssignment{PayComponent: oneOff, EffectiveFrom: date(5, 31)}, false},
+		{"one-off after period", PayComponentAssignment{PayComponent: oneOff, EffectiveFrom: date(7, 1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.assignment.AppliesTo(start, end))
+		})
+	}
+}
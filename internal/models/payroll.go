@@ -8,21 +8,33 @@ const (
 	PayrollStatusDraft     = "draft"
 	PayrollStatusPending   = "pending"
 	PayrollStatusProcessed = "processed"
+	PayrollStatusFailed    = "failed"
 )
 
 type Payroll struct {
-	ID          uint `gorm:"primaryKey"`
-	Name        string
-	Month       int `gorm:"uniqueIndex:idx_month_year"`
-	Year        int `gorm:"uniqueIndex:idx_month_year"`
-	PeriodStart time.Time
-	PeriodEnd   time.Time
-	Status      string `gorm:"default:'draft'"`
-	ProcessedAt time.Time
-	CreatedAt   time.Time
-	CreatedBy   uint
-	UpdatedAt   time.Time
-	UpdatedBy   uint
+	ID            uint `gorm:"primaryKey"`
+	Name          string
+	Month         int `gorm:"uniqueIndex:idx_month_year"`
+	Year          int `gorm:"uniqueIndex:idx_month_year"`
+	PeriodStart   time.Time
+	PeriodEnd     time.Time
+	Status        string `gorm:"default:'draft'"`
+	ProcessedAt   time.Time
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"type:text"`
+	FailureReason string     `gorm:"type:text"`
+	FailedAt      *time.Time `gorm:"default:null"`
+	Version       int        `gorm:"not null;default:1"`
+	ReopenedAt    *time.Time `gorm:"default:null"`
+	ReopenedBy    uint
+	ReopenReason  string `gorm:"type:text"`
+	CreatedAt     time.Time
+	CreatedBy     uint
+	UpdatedAt     time.Time
+	UpdatedBy     uint
+
+	// OpenAttendances counts the attendances in the period without a check-out when the payroll was run
+	OpenAttendances int `gorm:"not null;default:0"`
 
 	Payslips []Payslip `gorm:"foreignKey:PayrollID"`
 }
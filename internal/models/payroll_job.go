@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	PayrollJobStatusQueued    = "queued"
+	PayrollJobStatusSucceeded = "succeeded"
+	PayrollJobStatusFailed    = "failed"
+
+	DefaultPayrollJobMaxAttempts = 5
+)
+
+// PayrollJob is a durable queue entry for processing a payroll in the background.
+// Jobs are claimed by the worker with SELECT ... FOR UPDATE SKIP LOCKED. A claim counts the
+// attempt and leases the job by pushing RunAt ahead, so a crashed worker's job is picked up
+// again once the lease runs out.
+type PayrollJob struct {
+	ID          uint       `gorm:"primaryKey"`
+	PayrollID   uint       `gorm:"index"`
+	Payroll     Payroll    `gorm:"foreignKey:PayrollID"`
+	Status      string     `gorm:"default:'queued';index"`
+	Attempts    int        `gorm:"not null;default:0"`
+	MaxAttempts int        `gorm:"not null"`
+	RunAt       time.Time  `gorm:"index"`
+	LastError   string     `gorm:"type:text"`
+	CompletedAt *time.Time `gorm:"default:null"`
+	CreatedAt   time.Time
+	CreatedBy   uint
+	UpdatedAt   time.Time
+	UpdatedBy   uint
+}
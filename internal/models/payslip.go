@@ -2,6 +2,14 @@ package models
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PayslipKindRegular = "regular"
+	// PayslipKindFinalSettlement is the last payslip of an employee terminated within the period
+	PayslipKindFinalSettlement = "final_settlement"
 )
 
 type Payslip struct {
@@ -12,27 +20,66 @@ type Payslip struct {
 	PayrollID uint
 	Payroll   Payroll `gorm:"foreignKey:PayrollID"`
 
-	Month int `gorm:"not null"`
-	Year  int `gorm:"not null"`
+	Month int    `gorm:"not null"`
+	Year  int    `gorm:"not null"`
+	Kind  string `gorm:"not null;default:'regular'"`
+
+	// PayBasis is the employee's pay basis the payslip was computed with
+	PayBasis string `gorm:"not null;default:'monthly'"`
+
+	// versioning, a reopened payroll supersedes its payslips instead of deleting them
+	Version      int        `gorm:"not null;default:1"`
+	SupersededAt *time.Time `gorm:"default:null;index"`
+	SupersededBy uint
 
-	// summary totals
-	BaseSalary    float64
-	OvertimePay   float64
-	Reimbursement float64
-	TotalSalary   float64
+	// summary totals, computed amounts are stored at full precision
+	// so the configured rounding scope is preserved
+	BaseSalary    decimal.Decimal `gorm:"type:numeric"`
+	OvertimePay   decimal.Decimal `gorm:"type:numeric"`
+	Reimbursement decimal.Decimal `gorm:"type:numeric"`
+	TotalSalary   decimal.Decimal `gorm:"type:numeric"`
+
+	// pay components
+	OtherEarnings   decimal.Decimal `gorm:"type:numeric"`
+	OtherDeductions decimal.Decimal `gorm:"type:numeric"`
+	Lines           []PayslipLine   `gorm:"foreignKey:PayslipID"`
+
+	// PPh 21 withholding
+	TaxableIncome   decimal.Decimal `gorm:"type:numeric"`
+	TaxRate         decimal.Decimal `gorm:"type:numeric"`
+	Tax             decimal.Decimal `gorm:"type:numeric"`
+	NetSalary       decimal.Decimal `gorm:"type:numeric"`
+	TaxMethod       string
+	TaxTableVersion string
+	PTKPStatus      string
+
+	// BPJS contributions, the employee portion is deducted from net salary,
+	// the employer portion is a cost on top of it
+	BPJSEmployee      decimal.Decimal `gorm:"type:numeric"`
+	BPJSEmployer      decimal.Decimal `gorm:"type:numeric"`
+	BPJSTaxDeductible decimal.Decimal `gorm:"type:numeric"`
+	BPJSBreakdown     string          `gorm:"type:text"`
 
 	// calculation context
-	MonthlySalary       float64 `gorm:"not null"`
-	ExpectedWorkingDays int     `gorm:"not null"`
-	DaysAttended        int     `gorm:"not null"`
-	HourlyRate          float64 `gorm:"not null"`
-	OvertimeRatePerHour float64 `gorm:"not null"`
+	MonthlySalary       decimal.Decimal `gorm:"type:numeric;not null"`
+	ExpectedWorkingDays int             `gorm:"not null"`
+	EmployedWorkingDays int
+	DaysAttended        int `gorm:"not null"`
+	PaidLeaveDays       int
+	UnpaidLeaveDays     int
+	HourlyRate          decimal.Decimal `gorm:"type:numeric;not null"`
+	OvertimeRatePerHour decimal.Decimal `gorm:"type:numeric;not null"`
+
+	HolidayOvertimeRatePerHour decimal.Decimal `gorm:"type:numeric"`
 
 	// breakdowns
 	TotalHoursWorked       float64
 	TotalOvertimeHours     float64
+	HolidayOvertimeHours   float64
+	SalaryBreakdown        string `gorm:"type:text"`
 	AttendanceBreakdown    string `gorm:"type:text"`
 	OvertimeBreakdown      string `gorm:"type:text"`
+	LeaveBreakdown         string `gorm:"type:text"`
 	ReimbursementBreakdown string `gorm:"type:text"`
 
 	CreatedAt time.Time
@@ -2,21 +2,90 @@ package models
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ReimbursementCategoryMedical = "medical"
+	ReimbursementCategoryTravel  = "travel"
+	ReimbursementCategoryMeals   = "meals"
+	ReimbursementCategoryOther   = "other"
+)
+
+const (
+	ReimbursementStatusSubmitted = "submitted"
+	ReimbursementStatusApproved  = "approved"
+	ReimbursementStatusRejected  = "rejected"
+	ReimbursementStatusPaid      = "paid"
 )
 
+// ReimbursementCategory limits what an employee can claim. A null limit means the
+// category is not capped.
+type ReimbursementCategory struct {
+	ID            uint                `gorm:"primaryKey"`
+	Code          string              `gorm:"uniqueIndex;not null"`
+	Name          string              `gorm:"not null"`
+	PerClaimLimit decimal.NullDecimal `gorm:"type:numeric(20,2)"`
+	MonthlyLimit  decimal.NullDecimal `gorm:"type:numeric(20,2)"`
+	CreatedAt     time.Time
+	CreatedBy     uint
+	UpdatedAt     time.Time
+	UpdatedBy     uint
+}
+
+// ExceedsClaimLimit reports whether a single claim is over the per-claim cap.
+func (c *ReimbursementCategory) ExceedsClaimLimit(amount decimal.Decimal) bool {
+	return c.PerClaimLimit.Valid && amount.GreaterThan(c.PerClaimLimit.Decimal)
+}
+
+// ExceedsMonthlyLimit reports whether a claim on top of what was already claimed in the
+// month is over the monthly cap.
+func (c *ReimbursementCategory) ExceedsMonthlyLimit(claimed, amount decimal.Decimal) bool {
+	return c.MonthlyLimit.Valid && claimed.Add(amount).GreaterThan(c.MonthlyLimit.Decimal)
+}
+
+// Reimbursement is a claim by an employee. Approved claims are paid by the next payroll
+// and marked paid once that payroll is processed.
 type Reimbursement struct {
 	ID          uint `gorm:"primaryKey"`
 	UserID      uint
 	User        User `gorm:"foreignKey:UserID"`
+	CategoryID  *uint
+	Category    *ReimbursementCategory `gorm:"foreignKey:CategoryID"`
 	Date        time.Time
-	Amount      float64 `gorm:"not null"`
+	Amount      decimal.Decimal `gorm:"type:numeric(20,2);not null"`
 	Description string
-	CreatedBy   uint
-	CreatedAt   time.Time
-	UpdatedBy   uint
-	UpdatedAt   time.Time
+
+	Status       string `gorm:"not null;default:'submitted';index"`
+	ReviewedBy   *uint
+	Reviewer     *User `gorm:"foreignKey:ReviewedBy"`
+	ReviewedAt   *time.Time
+	ReviewReason string
+	PayrollID    *uint
+	PaidAt       *time.Time
+
+	Attachments []ReimbursementAttachment `gorm:"foreignKey:ReimbursementID"`
+
+	CreatedBy uint
+	CreatedAt time.Time
+	UpdatedBy uint
+	UpdatedAt time.Time
 }
 
 func (r *Reimbursement) DateOnlyString() string {
 	return r.Date.Format("2006-01-02")
 }
+
+// ReimbursementAttachment is a receipt of a claim. The file itself is kept in storage
+// under StorageKey.
+type ReimbursementAttachment struct {
+	ID              uint   `gorm:"primaryKey"`
+	ReimbursementID uint   `gorm:"not null;index"`
+	FileName        string `gorm:"not null"`
+	ContentType     string `gorm:"not null"`
+	Size            int64  `gorm:"not null"`
+	StorageKey      string `gorm:"uniqueIndex;not null"`
+	CreatedBy       uint
+	CreatedAt       time.Time
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,3 +13,18 @@ func TestReimbursement_DateOnlyString(t *testing.T) {
 	a := Reimbursement{Date: date}
 	assert.Equal(t, "2025-06-11", a.DateOnlyString())
 }
+
+func TestReimbursementCategory_Limits(t *testing.T) {
+	capped := ReimbursementCategory{
+		PerClaimLimit: decimal.NewNullDecimal(decimal.NewFromInt(500000)),
+		MonthlyLimit:  decimal.NewNullDecimal(decimal.NewFromInt(1000000)),
+	}
+	assert.False(t, capped.ExceedsClaimLimit(decimal.NewFromInt(500000)))
+	assert.True(t, capped.ExceedsClaimLimit(decimal.NewFromInt(500001)))
+	assert.False(t, capped.ExceedsMonthlyLimit(decimal.NewFromInt(600000), decimal.NewFromInt(400000)))
+	assert.True(t, capped.ExceedsMonthlyLimit(decimal.NewFromInt(600000), decimal.NewFromInt(400001)))
+
+	var uncapped ReimbursementCategory
+	assert.False(t, uncapped.ExceedsClaimLimit(decimal.NewFromInt(1000000000)))
+	assert.False(t, uncapped.ExceedsMonthlyLimit(decimal.NewFromInt(1000000000), decimal.NewFromInt(1)))
+}
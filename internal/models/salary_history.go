@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SalaryHistory is an employee's monthly salary from EffectiveFrom until the next entry.
+// User.Salary mirrors the entry effective today; payroll always reads the history.
+type SalaryHistory struct {
+	ID            uint            `gorm:"primaryKey"`
+	UserID        uint            `gorm:"not null;uniqueIndex:idx_salary_histories_user_effective_from"`
+	User          User            `gorm:"foreignKey:UserID"`
+	Salary        decimal.Decimal `gorm:"type:numeric(20,2);not null"`
+	EffectiveFrom time.Time       `gorm:"not null;uniqueIndex:idx_salary_histories_user_effective_from"`
+	Reason        string
+	CreatedAt     time.Time
+	CreatedBy     uint
+}
+
+func (s *SalaryHistory) EffectiveFromString() string {
+	return s.EffectiveFrom.Format("2006-01-02")
+}
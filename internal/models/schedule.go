@@ -0,0 +1,149 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Shift is a working day from StartTime to EndTime, wall clock times such as "08:00" in the
+// employee's timezone. A shift ending at or before its start ends the next day.
+// Hours is what a day of the shift is paid, the span without breaks.
+type Shift struct {
+	ID        uint            `gorm:"primaryKey"`
+	Name      string          `gorm:"uniqueIndex;not null"`
+	StartTime string          `gorm:"not null"`
+	EndTime   string          `gorm:"not null"`
+	Hours     decimal.Decimal `gorm:"type:numeric(5,2);not null"`
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
+}
+
+// DefaultShift is worked Monday to Friday by employees without a work pattern.
+var DefaultShift = Shift{Name: "Standard", StartTime: "08:00", EndTime: "17:00", Hours: decimal.NewFromInt(8)}
+
+// Overnight reports whether the shift ends the day after it starts.
+func (s *Shift) Overnight() bool {
+	return s.EndTime <= s.StartTime
+}
+
+// StartOn is when the shift starts on date, in loc.
+func (s *Shift) StartOn(date time.Time, loc *time.Location) time.Time {
+	return wallClock(date, s.StartTime, loc)
+}
+
+// EndOn is when the shift starting on date ends, in loc.
+func (s *Shift) EndOn(date time.Time, loc *time.Location) time.Time {
+	if s.Overnight() {
+		date = date.AddDate(0, 0, 1)
+	}
+	return wallClock(date, s.EndTime, loc)
+}
+
+func wallClock(date time.Time, clock string, loc *time.Location) time.Time {
+	t, _ := time.Parse("15:04", clock)
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+}
+
+// WorkPattern is a cycle of days repeating from StartDate, every day a shift or a rest day.
+// The standard week is a 7 day cycle starting on a Monday with shifts on its first five days.
+type WorkPattern struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"uniqueIndex;not null"`
+	StartDate time.Time `gorm:"not null"`
+	CreatedAt time.Time
+	CreatedBy uint
+	UpdatedAt time.Time
+	UpdatedBy uint
+
+	Days []WorkPatternDay `gorm:"foreignKey:WorkPatternID"`
+}
+
+// WorkPatternDay is the shift on a day of the cycle, counted from 0. A rest day has no shift.
+type WorkPatternDay struct {
+	ID            uint   `gorm:"primaryKey"`
+	WorkPatternID uint   `gorm:"not null;uniqueIndex:idx_work_pattern_days_pattern_day"`
+	Day           int    `gorm:"not null;uniqueIndex:idx_work_pattern_days_pattern_day"`
+	ShiftID       *uint  `gorm:"index"`
+	Shift         *Shift `gorm:"foreignKey:ShiftID"`
+}
+
+// ShiftOn returns the shift of the cycle on date, nil on a rest day.
+func (p *WorkPattern) ShiftOn(date time.Time) *Shift {
+	if len(p.Days) == 0 {
+		return nil
+	}
+	days := int(math.Floor(date.Sub(p.StartDate).Hours() / 24))
+	day := (days%len(p.Days) + len(p.Days)) % len(p.Days)
+	for _, d := range p.Days {
+		if d.Day == day {
+			return d.Shift
+		}
+	}
+	return nil
+}
+
+// UserWorkPattern puts an employee on a work pattern from EffectiveFrom until the next one.
+type UserWorkPattern struct {
+	ID            uint        `gorm:"primaryKey"`
+	UserID        uint        `gorm:"not null;uniqueIndex:idx_user_work_patterns_user_effective_from"`
+	WorkPatternID uint        `gorm:"not null;index"`
+	WorkPattern   WorkPattern `gorm:"foreignKey:WorkPatternID"`
+	EffectiveFrom time.Time   `gorm:"not null;uniqueIndex:idx_user_work_patterns_user_effective_from"`
+	CreatedAt     time.Time
+	CreatedBy     uint
+}
+
+// Schedule is an employee's work patterns ordered by EffectiveFrom. Days before the first one
+// follow the standard Monday to Friday week of DefaultShift.
+type Schedule []UserWorkPattern
+
+// Rostered reports whether a work pattern is in effect on date.
+func (s Schedule) Rostered(date time.Time) bool {
+	return len(s) > 0 && !s[0].EffectiveFrom.After(date)
+}
+
+// ShiftOn returns the employee's shift on date, nil on a rest day.
+func (s Schedule) ShiftOn(date time.Time) *Shift {
+	for i := len(s) - 1; i >= 0; i-- {
+		if !s[i].EffectiveFrom.After(date) {
+			return s[i].WorkPattern.ShiftOn(date)
+		}
+	}
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return nil
+	}
+	shift := DefaultShift
+	return &shift
+}
+
+// IsWorkingDay reports whether the employee has a shift on date and it is not a holiday.
+// Holidays are keyed by date in the "2006-01-02" format.
+func (s Schedule) IsWorkingDay(date time.Time, holidays map[string]bool) bool {
+	return s.ShiftOn(date) != nil && !holidays[date.Format("2006-01-02")]
+}
+
+// WorkingDays counts the employee's working days from start to end.
+func (s Schedule) WorkingDays(start, end time.Time, holidays map[string]bool) int {
+	days := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if s.IsWorkingDay(d, holidays) {
+			days++
+		}
+	}
+	return days
+}
+
+// WorkingHours sums the hours of the employee's shifts on the working days from start to end.
+func (s Schedule) WorkingHours(start, end time.Time, holidays map[string]bool) decimal.Decimal {
+	hours := decimal.Zero
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if s.IsWorkingDay(d, holidays) {
+			hours = hours.Add(s.ShiftOn(d).Hours)
+		}
+	}
+	return hours
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestShift_EndOn(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	day := Shift{StartTime: "08:00", EndTime: "17:00"}
+	assert.False(t, day.Overnight())
+	assert.Equal(t, time.Date(2025, 6, 5, 8, 0, 0, 0, jakarta), day.StartOn(date(2025, 6, 5), jakarta))
+	assert.Equal(t, time.Date(2025, 6, 5, 17, 0, 0, 0, jakarta), day.EndOn(date(2025, 6, 5), jakarta))
+
+	night := Shift{StartTime: "22:00", EndTime: "06:00"}
+	assert.True(t, night.Overnight())
+	assert.Equal(t, time.Date(2025, 6, 6, 6, 0, 0, 0, jakarta), night.EndOn(date(2025, 6, 5), jakarta))
+}
+
+func TestSchedule_ShiftOn(t *testing.T) {
+	morning := &Shift{Name: "morning", Hours: decimal.NewFromInt(7)}
+	night := &Shift{Name: "night", Hours: decimal.NewFromInt(8)}
+
+	// two mornings, two nights, two days off from Monday 2 June 2025
+	rotation := WorkPattern{StartDate: date(2025, 6, 2), Days: []WorkPatternDay{
+		{Day: 0, Shift: morning}, {Day: 1, Shift: morning},
+		{Day: 2, Shift: night}, {Day: 3, Shift: night},
+		{Day: 4}, {Day: 5},
+	}}
+	schedule := Schedule{{EffectiveFrom: date(2025, 6, 9), WorkPattern: rotation}}
+
+	// the standard week before the pattern
+	assert.Equal(t, "Standard", schedule.ShiftOn(date(2025, 6, 6)).Name)
+	assert.Nil(t, schedule.ShiftOn(date(2025, 6, 7)))
+	assert.False(t, schedule.Rostered(date(2025, 6, 8)))
+
+	// 9 June is day 7 of the cycle, the second morning
+	assert.True(t, schedule.Rostered(date(2025, 6, 9)))
+	assert.Equal(t, "morning", schedule.ShiftOn(date(2025, 6, 9)).Name)
+	assert.Equal(t, "night", schedule.ShiftOn(date(2025, 6, 10)).Name)
+	assert.Nil(t, schedule.ShiftOn(date(2025, 6, 12)))
+	// Saturday and Sunday are working days in the rotation
+	assert.Equal(t, "morning", schedule.ShiftOn(date(2025, 6, 14)).Name)
+	assert.Equal(t, "morning", schedule.ShiftOn(date(2025, 6, 15)).Name)
+
+	// a cycle reaches back before its start date too
+	assert.Nil(t, rotation.ShiftOn(date(2025, 6, 1)))
+	assert.Equal(t, "night", rotation.ShiftOn(date(2025, 5, 30)).Name)
+}
+
+func TestSchedule_WorkingDays(t *testing.T) {
+	long := &Shift{Hours: decimal.NewFromInt(12)}
+	// on one day, off the next
+	alternate := WorkPattern{StartDate: date(2025, 6, 1), Days: []WorkPatternDay{{Day: 0, Shift: long}, {Day: 1}}}
+
+	var standard Schedule
+	holidays := map[string]bool{"2025-06-06": true}
+	assert.Equal(t, 21, standard.WorkingDays(date(2025, 6, 1), date(2025, 6, 30), nil))
+	assert.Equal(t, 20, standard.WorkingDays(date(2025, 6, 1), date(2025, 6, 30), holidays))
+	assert.Equal(t, "160", standard.WorkingHours(date(2025, 6, 1), date(2025, 6, 30), holidays).String())
+
+	rostered := Schedule{{EffectiveFrom: date(2025, 6, 1), WorkPattern: alternate}}
+	assert.Equal(t, 15, rostered.WorkingDays(date(2025, 6, 1), date(2025, 6, 30), nil))
+	// the 6th is not a shift day anyway
+	assert.Equal(t, 15, rostered.WorkingDays(date(2025, 6, 1), date(2025, 6, 30), holidays))
+	assert.Equal(t, "180", rostered.WorkingHours(date(2025, 6, 1), date(2025, 6, 30), holidays).String())
+	assert.True(t, rostered.IsWorkingDay(date(2025, 6, 7), holidays))
+	assert.False(t, rostered.IsWorkingDay(date(2025, 6, 8), holidays))
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TaxBracketKindTER         = "ter"
+	TaxBracketKindProgressive = "progressive"
+
+	TaxMethodTER          = "ter"
+	TaxMethodAnnualTrueUp = "annual_true_up"
+
+	DefaultPTKPStatus = "TK/0"
+)
+
+// TaxTable is a versioned set of PPh 21 rules: TER monthly rates, the annual (Pasal 17)
+// progressive brackets and PTKP amounts. The latest table effective on a payroll's period
+// end is used, so a regulation change is a new row rather than a code change.
+type TaxTable struct {
+	ID                   uint            `gorm:"primaryKey"`
+	Version              string          `gorm:"uniqueIndex;not null"`
+	EffectiveFrom        time.Time       `gorm:"not null"`
+	OccupationalCostRate decimal.Decimal `gorm:"type:numeric(7,4);not null"`
+	OccupationalCostCap  decimal.Decimal `gorm:"type:numeric(20,2);not null"`
+	NonNPWPSurchargeRate decimal.Decimal `gorm:"type:numeric(7,4);not null"`
+	CreatedAt            time.Time
+	CreatedBy            uint
+	UpdatedAt            time.Time
+	UpdatedBy            uint
+
+	Brackets []TaxBracket `gorm:"foreignKey:TaxTableID"`
+	PTKPs    []TaxPTKP    `gorm:"foreignKey:TaxTableID"`
+}
+
+// TaxBracket is a single rate band. Income falls into the first band (ordered by
+// LowerBound) whose UpperBound is not exceeded; a NULL UpperBound is unbounded.
+type TaxBracket struct {
+	ID         uint   `gorm:"primaryKey"`
+	TaxTableID uint   `gorm:"index"`
+	Kind       string `gorm:"not null"`
+	Category   string
+	LowerBound decimal.Decimal     `gorm:"type:numeric(20,2);not null"`
+	UpperBound decimal.NullDecimal `gorm:"type:numeric(20,2)"`
+	Rate       decimal.Decimal     `gorm:"type:numeric(7,4);not null"`
+}
+
+// TaxPTKP is the annual non-taxable income (PTKP) for a status such as TK/0 or K/1,
+// along with the TER category used for monthly withholding.
+type TaxPTKP struct {
+	ID          uint            `gorm:"primaryKey"`
+	TaxTableID  uint            `gorm:"index"`
+	Status      string          `gorm:"not null"`
+	TERCategory string          `gorm:"not null"`
+	Amount      decimal.Decimal `gorm:"type:numeric(20,2);not null"`
+}
+
+func (t *TaxTable) PTKP(status string) (TaxPTKP, bool) {
+	for _, p := range t.PTKPs {
+		if p.Status == status {
+			return p, true
+		}
+	}
+	return TaxPTKP{}, false
+}
+
+// TERRate returns the monthly TER rate for a category and monthly gross income.
+func (t *TaxTable) TERRate(category string, gross decimal.Decimal) decimal.Decimal {
+	for _, b := range t.brackets(TaxBracketKindTER, category) {
+		if !b.UpperBound.Valid || gross.LessThanOrEqual(b.UpperBound.Decimal) {
+			return b.Rate
+		}
+	}
+	return decimal.Zero
+}
+
+// AnnualTax applies the progressive brackets to an annual taxable income (PKP).
+func (t *TaxTable) AnnualTax(pkp decimal.Decimal) decimal.Decimal {
+	tax := decimal.Zero
+	for _, b := range t.brackets(TaxBracketKindProgressive, "") {
+		if !pkp.GreaterThan(b.LowerBound) {
+			break
+		}
+		portion := pkp
+		if b.UpperBound.Valid && pkp.GreaterThan(b.UpperBound.Decimal) {
+			portion = b.UpperBound.Decimal
+		}
+		tax = tax.Add(portion.Sub(b.LowerBound).Mul(b.Rate))
+	}
+	return tax
+}
+
+// MonthlyWithholding computes the TER withholding for a regular month.
+func (t *TaxTable) MonthlyWithholding(ptkp TaxPTKP, hasNPWP bool, gross decimal.Decimal) (rate decimal.Decimal, tax decimal.Decimal) {
+	rate = t.TERRate(ptkp.TERCategory, gross)
+	return rate, t.withSurcharge(gross.Mul(rate), hasNPWP)
+}
+
+// AnnualWithholding computes the tax still owed for the year (the December true-up):
+// the annual Pasal 17 tax on the year's income minus what was already withheld.
+// Deductions are the employee's own pension contributions (JHT, JP) for the year.
+// The result is negative when too much was withheld during the year.
+func (t *TaxTable) AnnualWithholding(ptkp TaxPTKP, hasNPWP bool, annualGross decimal.Decimal, deductions decimal.Decimal, withheld decimal.Decimal) decimal.Decimal {
+	occupationalCost := decimal.Min(annualGross.Mul(t.OccupationalCostRate), t.OccupationalCostCap)
+
+	pkp := annualGross.Sub(occupationalCost).Sub(deductions).Sub(ptkp.Amount)
+	if pkp.IsNegative() {
+		pkp = decimal.Zero
+	}
+	// PKP is rounded down to the thousand rupiah
+	pkp = pkp.Div(decimal.NewFromInt(1000)).Floor().Mul(decimal.NewFromInt(1000))
+
+	return t.withSurcharge(t.AnnualTax(pkp), hasNPWP).Sub(withheld)
+}
+
+func (t *TaxTable) withSurcharge(tax decimal.Decimal, hasNPWP bool) decimal.Decimal {
+	if hasNPWP {
+		return tax
+	}
+	return tax.Add(tax.Mul(t.NonNPWPSurchargeRate))
+}
+
+func (t *TaxTable) brackets(kind string, category string) []TaxBracket {
+	var brackets []TaxBracket
+	for _, b := range t.Brackets {
+		if b.Kind == kind && b.Category == category {
+			brackets = append(brackets, b)
+		}
+	}
+	sort.Slice(brackets, func(i, j int) bool {
+		return brackets[i].LowerBound.LessThan(brackets[j].LowerBound)
+	})
+	return brackets
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTaxTable() TaxTable {
+	return TaxTable{
+		OccupationalCostRate: decimal.RequireFromString("0.05"),
+		OccupationalCostCap:  decimal.NewFromInt(6000000),
+		NonNPWPSurchargeRate: decimal.RequireFromString("0.2"),
+		Brackets: []TaxBracket{
+			{Kind: TaxBracketKindTER, Category: "A", LowerBound: decimal.NewFromInt(5000000), Rate: decimal.RequireFromString("0.02")},
+			{Kind: TaxBracketKindTER, Category: "A", LowerBound: decimal.Zero, UpperBound: decimal.NewNullDecimal(decimal.NewFromInt(5000000)), Rate: decimal.Zero},
+			{Kind: TaxBracketKindProgressive, LowerBound: decimal.Zero, UpperBound: decimal.NewNullDecimal(decimal.NewFromInt(60000000)), Rate: decimal.RequireFromString("0.05")},
+			{Kind: TaxBracketKindProgressive, LowerBound: decimal.NewFromInt(60000000), Rate: decimal.RequireFromString("0.15")},
+		},
+		PTKPs: []TaxPTKP{
+			{Status: "TK/0", TERCategory: "A", Amount: decimal.NewFromInt(54000000)},
+		},
+	}
+}
+
+func TestTaxTable_PTKP(t *testing.T) {
+	table := testTaxTable()
+
+	ptkp, ok := table.PTKP("TK/0")
+	assert.True(t, ok)
+	assert.Equal(t, "A", ptkp.TERCategory)
+
+	_, ok = table.PTKP("K/9")
+	assert.False(t, ok)
+}
+
+func TestTaxTable_TERRate(t *testing.T) {
+	table := testTaxTable()
+
+	assert.True(t, table.TERRate("A", decimal.NewFromInt(5000000)).IsZero())
+	assert.Equal(t, "0.02", table.TERRate("A", decimal.NewFromInt(5000001)).String())
+	assert.True(t, table.TERRate("B", decimal.NewFromInt(5000001)).IsZero())
+}
+
+func TestTaxTable_AnnualTax(t *testing.T) {
+	table := testTaxTable()
+
+	assert.True(t, table.AnnualTax(decimal.Zero).IsZero())
+	assert.Equal(t, "2500000", table.AnnualTax(decimal.NewFromInt(50000000)).String())
+	// 60,000,000 at 5% plus 40,000,000 at 15%
+	assert.Equal(t, "9000000", table.AnnualTax(decimal.NewFromInt(100000000)).String())
+}
+
+func TestTaxTable_MonthlyWithholding(t *testing.T) {
+	table := testTaxTable()
+	ptkp, _ := table.PTKP("TK/0")
+
+	rate, tax := table.MonthlyWithholding(ptkp, true, decimal.NewFromInt(10000000))
+	assert.Equal(t, "0.02", rate.String())
+	assert.Equal(t, "200000", tax.String())
+
+	_, tax = table.MonthlyWithholding(ptkp, false, decimal.NewFromInt(10000000))
+	assert.Equal(t, "240000", tax.String())
+}
+
+func TestTaxTable_AnnualWithholding(t *testing.T) {
+	table := testTaxTable()
+	ptkp, _ := table.PTKP("TK/0")
+
+	// occupational cost is capped at 6,000,000 and PKP is floored to the thousand
+	tax := table.AnnualWithholding(ptkp, true, decimal.NewFromInt(200000999), decimal.Zero, decimal.Zero)
+	// PKP 140,000,000: 3,000,000 + 80,000,000 * 15%
+	assert.Equal(t, "15000000", tax.String())
+
+	refund := table.AnnualWithholding(ptkp, true, decimal.NewFromInt(50000000), decimal.Zero, decimal.NewFromInt(100000))
+	assert.Equal(t, "-100000", refund.String())
+
+	// pension contributions reduce PKP to 136,000,000: 3,000,000 + 76,000,000 * 15%
+	tax = table.AnnualWithholding(ptkp, true, decimal.NewFromInt(200000999), decimal.NewFromInt(4000000), decimal.Zero)
+	assert.Equal(t, "14400000", tax.String())
+}
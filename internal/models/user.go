@@ -1,16 +1,80 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// EmploymentStatusActive employees are paid in every period they are employed
+	EmploymentStatusActive = "active"
+	// EmploymentStatusInactive users (system and admin accounts, suspended employees) are never paid
+	EmploymentStatusInactive = "inactive"
+	// EmploymentStatusTerminated employees are paid up to their termination date,
+	// the period containing it gets a final settlement payslip
+	EmploymentStatusTerminated = "terminated"
+)
+
+const (
+	// PayBasisMonthly employees are paid their salary for the working days of the period
+	PayBasisMonthly = "monthly"
+	// PayBasisHourly employees are paid their salary as an hourly rate for the hours worked
+	PayBasisHourly = "hourly"
+	// PayBasisDaily employees are paid their salary as a daily rate for the days worked
+	PayBasisDaily = "daily"
+)
 
 type User struct {
-	ID        uint   `gorm:"primaryKey"`
-	Username  string `gorm:"uniqueIndex;not null"`
-	Password  string `gorm:"not null"`
-	Salary    float64
-	RoleID    uint
-	Role      Role `gorm:"foreignKey:RoleID"`
+	ID       uint            `gorm:"primaryKey"`
+	Username string          `gorm:"uniqueIndex;not null"`
+	Password string          `gorm:"not null"`
+	Salary   decimal.Decimal `gorm:"type:numeric(20,2)"`
+	RoleID   uint
+	Role     Role `gorm:"foreignKey:RoleID"`
+
+	// tax profile
+	PTKPStatus string `gorm:"not null;default:'TK/0'"`
+	NPWP       string
+
+	// employment, a missing hire or termination date is unbounded
+	EmploymentStatus  string `gorm:"not null;default:'active'"`
+	HireDate          *time.Time
+	TerminationDate   *time.Time
+	TerminationReason string
+
+	// ManagerID is the user who reviews this user's requests, admins can review anyone's
+	ManagerID *uint
+
+	// Timezone is the IANA zone attendance dates are taken in, empty is the company's
+	Timezone string `gorm:"not null;default:''"`
+
+	// PayBasis is what Salary is the rate of: a month, an hour or a day of work
+	PayBasis string `gorm:"not null;default:'monthly'"`
+
+	// OvertimeMonthlyCap overrides OVERTIME_MONTHLY_CAP for this user, 0 for no cap, nil for the company's
+	OvertimeMonthlyCap *float64
+
 	CreatedAt time.Time
 	CreatedBy uint
 	UpdatedAt time.Time
 	UpdatedBy uint
 }
+
+// EmploymentWindow clips a period to the days the user is employed.
+// ok is false when the user is not employed at all during the period.
+func (u *User) EmploymentWindow(start, end time.Time) (from time.Time, to time.Time, ok bool) {
+	from, to = start, end
+	if u.HireDate != nil && u.HireDate.After(from) {
+		from = *u.HireDate
+	}
+	if u.TerminationDate != nil && u.TerminationDate.Before(to) {
+		to = *u.TerminationDate
+	}
+	return from, to, !from.After(to)
+}
+
+// TerminatedWithin reports whether the user's employment ends within the period.
+func (u *User) TerminatedWithin(start, end time.Time) bool {
+	return u.TerminationDate != nil && !u.TerminationDate.Before(start) && !u.TerminationDate.After(end)
+}
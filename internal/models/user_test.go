@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_EmploymentWindow(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	datePtr := func(month time.Month, day int) *time.Time {
+		d := time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		user     User
+		from     time.Time
+		to       time.Time
+		employed bool
+	}{
+		{"no dates", User{}, start, end, true},
+		{"hired before period", User{HireDate: datePtr(1, 6)}, start, end, true},
+		{"joiner", User{HireDate: datePtr(6, 16)}, *datePtr(6, 16), end, true},
+		{"leaver", User{TerminationDate: datePtr(6, 13)}, start, *datePtr(6, 13), true},
+		{"joined and left", User{HireDate: datePtr(6, 2), TerminationDate: datePtr(6, 13)}, *datePtr(6, 2), *datePtr(6, 13), true},
+		{"hired after period", User{HireDate: datePtr(7, 1)}, *datePtr(7, 1), end, false},
+		{"left before period", User{TerminationDate: datePtr(5, 31)}, start, *datePtr(5, 31), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, employed := tt.user.EmploymentWindow(start, end)
+			assert.Equal(t, tt.employed, employed)
+			if employed {
+				assert.Equal(t, tt.from, from)
+				assert.Equal(t, tt.to, to)
+			}
+		})
+	}
+}
+
+func TestUser_TerminatedWithin(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	assert.False(t, (&User{}).TerminatedWithin(start, end))
+
+	for _, tt := range []struct {
+		date     time.Time
+		expected bool
+	}{
+		{start, true},
+		{end, true},
+		{time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), false},
+	} {
+		u := User{TerminationDate: &tt.date}
+		assert.Equal(t, tt.expected, u.TerminatedWithin(start, end), tt.date.String())
+	}
+}
@@ -1,6 +1,13 @@
 package route
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
 	"dealls-case-study/internal/handlers"
 	"dealls-case-study/internal/middlewares"
 
@@ -9,7 +16,8 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes() {
+// SetupRoutes serves the API until ctx is cancelled, then lets in-flight requests finish.
+func SetupRoutes(ctx context.Context) {
 	r := gin.Default()
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -24,18 +32,151 @@ func SetupRoutes() {
 			attendance.POST("/check-in", handlers.CheckInAttendance)
 			attendance.POST("/check-out", handlers.CheckOutAttendance)
 			attendance.POST("/overtime", handlers.SubmitOvertime)
+			attendance.GET("/schedule", handlers.GetSchedule)
+			attendance.GET("", middlewares.AdminOnly(), handlers.ListAttendances)
+			attendance.GET("/anomalies", middlewares.AdminOnly(), handlers.ListAttendanceAnomalies)
+			attendance.POST("", middlewares.AdminOnly(), handlers.CreateAttendance)
+			attendance.PUT("/:id", middlewares.AdminOnly(), handlers.UpdateAttendance)
+			attendance.POST("/:id/void", middlewares.AdminOnly(), handlers.VoidAttendance)
+			attendance.GET("/:id/audits", middlewares.AdminOnly(), handlers.ListAttendanceAudits)
+		}
+		me := v1.Group("/me")
+		{
+			me.GET("/attendances", handlers.ListMyAttendances)
+			me.GET("/overtimes", handlers.ListMyOvertimes)
+			me.GET("/reimbursements", handlers.ListMyReimbursements)
 		}
 		payroll := v1.Group("/payrolls")
 		payroll.Use(middlewares.AdminOnly())
 		{
 			payroll.POST("/:year/:month/run", handlers.RunPayroll)
+			payroll.POST("/:year/:month/retry", handlers.RetryPayroll)
+			payroll.POST("/:year/:month/reopen", handlers.ReopenPayroll)
 			payroll.POST("/:year/:month", handlers.UpsertPayroll)
 			payroll.GET("/:year/:month/summary", handlers.GeneratePayrollSummary)
+			payroll.GET("/:year/:month/preview", handlers.PreviewPayroll)
+		}
+
+		users := v1.Group("/users")
+		users.Use(middlewares.AdminOnly())
+		{
+			users.PUT("/:id/tax-profile", handlers.UpdateUserTaxProfile)
+			users.PUT("/:id/employment", handlers.UpdateUserEmployment)
+			users.POST("/:id/salaries", handlers.CreateUserSalary)
+			users.GET("/:id/salaries", handlers.ListUserSalaries)
+			users.PUT("/:id/manager", handlers.UpdateUserManager)
+			users.PUT("/:id/timezone", handlers.UpdateUserTimezone)
+			users.PUT("/:id/pay-basis", handlers.UpdateUserPayBasis)
+			users.PUT("/:id/overtime-cap", handlers.UpdateUserOvertimeCap)
+			users.GET("/:id/leave-balances", handlers.ListUserLeaveBalances)
+			users.POST("/:id/work-patterns", handlers.CreateUserWorkPattern)
+			users.GET("/:id/work-patterns", handlers.ListUserWorkPatterns)
+		}
+
+		payComponents := v1.Group("/pay-components")
+		payComponents.Use(middlewares.AdminOnly())
+		{
+			payComponents.POST("", handlers.CreatePayComponent)
+			payComponents.GET("", handlers.ListPayComponents)
+			payComponents.PUT("/:id", handlers.UpdatePayComponent)
+			payComponents.POST("/:id/assignments", handlers.CreatePayComponentAssignment)
+			payComponents.GET("/:id/assignments", handlers.ListPayComponentAssignments)
+			payComponents.DELETE("/:id/assignments/:assignmentId", handlers.DeletePayComponentAssignment)
+		}
+
+		corrections := v1.Group("/attendance-corrections")
+		{
+			corrections.POST("", handlers.CreateAttendanceCorrectionRequest)
+			corrections.GET("", handlers.ListAttendanceCorrectionRequests)
+			corrections.GET("/pending", handlers.ListPendingAttendanceCorrectionRequests)
+			corrections.POST("/:id/approve", handlers.ApproveAttendanceCorrectionRequest)
+			corrections.POST("/:id/reject", handlers.RejectAttendanceCorrectionRequest)
+			corrections.POST("/:id/cancel", handlers.CancelAttendanceCorrectionRequest)
+		}
+
+		overtimeRules := v1.Group("/overtime-rules")
+		overtimeRules.Use(middlewares.AdminOnly())
+		{
+			overtimeRules.GET("", handlers.ListOvertimeRuleSets)
+			overtimeRules.POST("", handlers.CreateOvertimeRuleSet)
+		}
+
+		shifts := v1.Group("/shifts")
+		shifts.Use(middlewares.AdminOnly())
+		{
+			shifts.GET("", handlers.ListShifts)
+			shifts.POST("", handlers.CreateShift)
+		}
+
+		workPatterns := v1.Group("/work-patterns")
+		workPatterns.Use(middlewares.AdminOnly())
+		{
+			workPatterns.GET("", handlers.ListWorkPatterns)
+			workPatterns.POST("", handlers.CreateWorkPattern)
+		}
+
+		overtimes := v1.Group("/overtimes")
+		{
+			overtimes.GET("", middlewares.AdminOnly(), handlers.ListOvertimes)
+			overtimes.GET("/pending", handlers.ListPendingOvertimes)
+			overtimes.POST("/:id/approve", handlers.ApproveOvertime)
+			overtimes.POST("/:id/reject", handlers.RejectOvertime)
 		}
 
-		v1.POST("/reimbursements", handlers.SubmitReimbursement)
+		leaves := v1.Group("/leaves")
+		{
+			leaves.POST("", handlers.CreateLeaveRequest)
+			leaves.GET("", handlers.ListLeaveRequests)
+			leaves.GET("/balances", handlers.GetLeaveBalances)
+			leaves.GET("/pending", handlers.ListPendingLeaveRequests)
+			leaves.POST("/:id/approve", handlers.ApproveLeaveRequest)
+			leaves.POST("/:id/reject", handlers.RejectLeaveRequest)
+			leaves.POST("/:id/cancel", handlers.CancelLeaveRequest)
+		}
+		v1.GET("/leave-types", handlers.ListLeaveTypes)
+
+		holidays := v1.Group("/holidays")
+		{
+			holidays.GET("", handlers.ListHolidays)
+			holidays.POST("", middlewares.AdminOnly(), handlers.CreateHoliday)
+			holidays.POST("/import", middlewares.AdminOnly(), handlers.ImportHolidays)
+			holidays.DELETE("/:id", middlewares.AdminOnly(), handlers.DeleteHoliday)
+		}
+
+		reimbursements := v1.Group("/reimbursements")
+		{
+			reimbursements.POST("", handlers.SubmitReimbursement)
+			reimbursements.GET("", middlewares.AdminOnly(), handlers.ListReimbursements)
+			reimbursements.GET("/pending", middlewares.AdminOnly(), handlers.ListPendingReimbursements)
+			reimbursements.POST("/:id/approve", middlewares.AdminOnly(), handlers.ApproveReimbursement)
+			reimbursements.POST("/:id/reject", middlewares.AdminOnly(), handlers.RejectReimbursement)
+			reimbursements.POST("/:id/attachments", handlers.AddReimbursementAttachments)
+			reimbursements.GET("/:id/attachments/:attachmentId", handlers.DownloadReimbursementAttachment)
+		}
+		v1.GET("/reimbursement-categories", handlers.ListReimbursementCategories)
+		v1.PUT("/reimbursement-categories/:id", middlewares.AdminOnly(), handlers.UpdateReimbursementCategory)
+
+		v1.GET("/payslips", handlers.ListPayslips)
 		v1.GET("/payslips/:year/:month", handlers.GetPayslip)
+		v1.GET("/payslips/:year/:month/history", handlers.GetPayslipHistory)
 	}
 
-	r.Run()
+	addr := ":8080"
+	if port := os.Getenv("PORT"); port != "" {
+		addr = ":" + port
+	}
+	srv := &http.Server{Addr: addr, Handler: r}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("server shutdown: %v", err)
+		}
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("server: %v", err)
+	}
 }
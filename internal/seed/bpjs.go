@@ -0,0 +1,81 @@
+package seed
+
+import (
+	"dealls-case-study/internal/models"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// BPJSRateTables seeds the BPJS contribution rates. Existing versions are left untouched.
+//
+// JP's salary cap is adjusted every March; Kesehatan's cap is fixed by Perpres 64/2020.
+// JKK uses the rate of the lowest risk group (0.24%). The first table covers every period
+// the tax tables do, from January 2024.
+func BPJSRateTables(db *gorm.DB) error {
+	tables := []models.BPJSRateTable{
+		bpjsRateTable("2023", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), 9559600),
+		bpjsRateTable("2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 10042300),
+		bpjsRateTable("2025", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 10547400),
+	}
+
+	for _, table := range tables {
+		var count int64
+		if err := db.Model(&models.BPJSRateTable{}).Where("version = ?", table.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Create(&table).Error; err != nil {
+			log.Printf("Failed to seed BPJS rate table %s: %v", table.Version, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func bpjsRateTable(version string, effectiveFrom time.Time, jpCap int64) models.BPJSRateTable {
+	return models.BPJSRateTable{
+		Version:       version,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     999,
+		Rates: []models.BPJSRate{
+			{
+				Program:               models.BPJSProgramJHT,
+				EmployeeRate:          decimal.RequireFromString("0.02"),
+				EmployerRate:          decimal.RequireFromString("0.037"),
+				EmployeeTaxDeductible: true,
+			},
+			{
+				Program:               models.BPJSProgramJP,
+				EmployeeRate:          decimal.RequireFromString("0.01"),
+				EmployerRate:          decimal.RequireFromString("0.02"),
+				SalaryCap:             decimal.NewNullDecimal(decimal.NewFromInt(jpCap)),
+				EmployeeTaxDeductible: true,
+			},
+			{
+				Program:         models.BPJSProgramJKK,
+				EmployeeRate:    decimal.Zero,
+				EmployerRate:    decimal.RequireFromString("0.0024"),
+				EmployerTaxable: true,
+			},
+			{
+				Program:         models.BPJSProgramJKM,
+				EmployeeRate:    decimal.Zero,
+				EmployerRate:    decimal.RequireFromString("0.003"),
+				EmployerTaxable: true,
+			},
+			{
+				Program:         models.BPJSProgramKesehatan,
+				EmployeeRate:    decimal.RequireFromString("0.01"),
+				EmployerRate:    decimal.RequireFromString("0.04"),
+				SalaryCap:       decimal.NewNullDecimal(decimal.NewFromInt(12000000)),
+				EmployerTaxable: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"dealls-case-study/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBPJSRateTable_Contributions(t *testing.T) {
+	table := bpjsRateTable("2025", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 10547400)
+
+	employee := map[string]string{}
+	employer := map[string]string{}
+	for _, c := range table.Contributions(decimal.NewFromInt(20000000)) {
+		employee[c.Program] = c.EmployeeAmount.String()
+		employer[c.Program] = c.EmployerAmount.String()
+	}
+
+	assert.Equal(t, "400000", employee[models.BPJSProgramJHT])
+	assert.Equal(t, "740000", employer[models.BPJSProgramJHT])
+	// JP and Kesehatan are capped
+	assert.Equal(t, "105474", employee[models.BPJSProgramJP])
+	assert.Equal(t, "210948", employer[models.BPJSProgramJP])
+	assert.Equal(t, "120000", employee[models.BPJSProgramKesehatan])
+	assert.Equal(t, "480000", employer[models.BPJSProgramKesehatan])
+	// JKK and JKM are paid by the employer only
+	assert.Equal(t, "0", employee[models.BPJSProgramJKK])
+	assert.Equal(t, "48000", employer[models.BPJSProgramJKK])
+	assert.Equal(t, "0", employee[models.BPJSProgramJKM])
+	assert.Equal(t, "60000", employer[models.BPJSProgramJKM])
+}
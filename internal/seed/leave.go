@@ -0,0 +1,40 @@
+package seed
+
+import (
+	"dealls-case-study/internal/models"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// LeaveTypes seeds the statutory leave types. Existing types are left untouched so
+// admins can adjust their entitlement.
+//
+// Annual leave is 12 working days a year (UU 13/2003), maternity leave three months
+// (about 65 working days). Sick leave with a doctor's note and unpaid leave have no balance.
+func LeaveTypes(db *gorm.DB) error {
+	for _, leaveType := range leaveTypes() {
+		var count int64
+		if err := db.Model(&models.LeaveType{}).Where("code = ?", leaveType.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Create(&leaveType).Error; err != nil {
+			log.Printf("Failed to seed leave type %s: %v", leaveType.Code, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func leaveTypes() []models.LeaveType {
+	return []models.LeaveType{
+		{Code: models.LeaveTypeAnnual, Name: "Annual Leave", Paid: true, AnnualEntitlement: 12, CreatedBy: 999},
+		{Code: models.LeaveTypeSick, Name: "Sick Leave", Paid: true, CreatedBy: 999},
+		{Code: models.LeaveTypeUnpaid, Name: "Unpaid Leave", Paid: false, CreatedBy: 999},
+		{Code: models.LeaveTypeMaternity, Name: "Maternity Leave", Paid: true, AnnualEntitlement: 65, CreatedBy: 999},
+	}
+}
@@ -0,0 +1,74 @@
+package seed
+
+import (
+	"dealls-case-study/internal/models"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// OvertimeRuleSets seeds the overtime multipliers. Existing versions are left untouched.
+//
+// "flat" is how overtime was paid before the rules were configurable: 2x the hourly rate,
+// 3x on a public holiday. "pp-35-2021" follows PP 35/2021 for a five-day work week:
+// 1.5x the first hour and 2x after on a working day, 2x the first 8 hours, 3x the 9th and
+// 4x after on a rest day or public holiday. It applies from the first period after
+// the rules were introduced so payrolls already processed are not changed.
+func OvertimeRuleSets(db *gorm.DB) error {
+	for _, set := range overtimeRuleSets() {
+		var count int64
+		if err := db.Model(&models.OvertimeRuleSet{}).Where("version = ?", set.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Create(&set).Error; err != nil {
+			log.Printf("Failed to seed overtime rule set %s: %v", set.Version, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func overtimeRuleSets() []models.OvertimeRuleSet {
+	rule := func(dayType string, from, to, multiplier float64) models.OvertimeRule {
+		r := models.OvertimeRule{DayType: dayType, FromHour: decimal.NewFromFloat(from), Multiplier: decimal.NewFromFloat(multiplier)}
+		if to > 0 {
+			r.ToHour = decimal.NewNullDecimal(decimal.NewFromFloat(to))
+		}
+		return r
+	}
+	restDay := func(dayType string) []models.OvertimeRule {
+		return []models.OvertimeRule{
+			rule(dayType, 0, 8, 2),
+			rule(dayType, 8, 9, 3),
+			rule(dayType, 9, 0, 4),
+		}
+	}
+
+	return []models.OvertimeRuleSet{
+		{
+			Version:       "flat",
+			EffectiveFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBy:     999,
+			Rules: []models.OvertimeRule{
+				rule(models.OvertimeDayWeekday, 0, 0, 2),
+				rule(models.OvertimeDayRestDay, 0, 0, 2),
+				rule(models.OvertimeDayHoliday, 0, 0, 3),
+			},
+		},
+		{
+			Version:       "pp-35-2021",
+			EffectiveFrom: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBy:     999,
+			Rules: append(append([]models.OvertimeRule{
+				rule(models.OvertimeDayWeekday, 0, 1, 1.5),
+				rule(models.OvertimeDayWeekday, 1, 0, 2),
+			}, restDay(models.OvertimeDayRestDay)...), restDay(models.OvertimeDayHoliday)...),
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package seed
+
+import (
+	"testing"
+
+	"dealls-case-study/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertimeRuleSets_PP35(t *testing.T) {
+	sets := overtimeRuleSets()
+	set := sets[len(sets)-1]
+	assert.Equal(t, "pp-35-2021", set.Version)
+
+	units := func(dayType string, hours int64) string {
+		total := decimal.Zero
+		for _, tier := range set.Tiers(dayType, decimal.NewFromInt(hours)) {
+			total = total.Add(tier.Hours.Mul(tier.Multiplier))
+		}
+		return total.String()
+	}
+
+	// 1.5 + 2 + 2
+	assert.Equal(t, "5.5", units(models.OvertimeDayWeekday, 3))
+	// 8 * 2 + 3 + 4 * 2
+	assert.Equal(t, "27", units(models.OvertimeDayRestDay, 11))
+	assert.Equal(t, "27", units(models.OvertimeDayHoliday, 11))
+}
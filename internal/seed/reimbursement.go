@@ -0,0 +1,43 @@
+package seed
+
+import (
+	"dealls-case-study/internal/models"
+	"log"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ReimbursementCategories seeds the default claim categories and their limits. Existing
+// categories are left untouched so admins can adjust the limits.
+//
+// Claims filed as "other" are not capped and rely on the admin's review.
+func ReimbursementCategories(db *gorm.DB) error {
+	for _, category := range reimbursementCategories() {
+		var count int64
+		if err := db.Model(&models.ReimbursementCategory{}).Where("code = ?", category.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := db.Create(&category).Error; err != nil {
+			log.Printf("Failed to seed reimbursement category %s: %v", category.Code, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func reimbursementCategories() []models.ReimbursementCategory {
+	limit := func(amount int64) decimal.NullDecimal {
+		return decimal.NewNullDecimal(decimal.NewFromInt(amount))
+	}
+	return []models.ReimbursementCategory{
+		{Code: models.ReimbursementCategoryMedical, Name: "Medical", PerClaimLimit: limit(5000000), MonthlyLimit: limit(10000000), CreatedBy: 999},
+		{Code: models.ReimbursementCategoryTravel, Name: "Travel", PerClaimLimit: limit(3000000), MonthlyLimit: limit(6000000), CreatedBy: 999},
+		{Code: models.ReimbursementCategoryMeals, Name: "Meals", PerClaimLimit: limit(250000), MonthlyLimit: limit(1500000), CreatedBy: 999},
+		{Code: models.ReimbursementCategoryOther, Name: "Other", CreatedBy: 999},
+	}
+}
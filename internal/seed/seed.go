@@ -7,6 +7,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -37,6 +38,8 @@ func Run(db *gorm.DB) error {
 		Username: "admin",
 		Password: password,
 		RoleID:   1,
+		// not an employee on the payroll
+		EmploymentStatus: models.EmploymentStatusInactive,
 	}
 	err = db.FirstOrCreate(&admin, models.User{ID: admin.ID}).Error
 	if err != nil {
@@ -58,7 +61,7 @@ func Run(db *gorm.DB) error {
 			ID:        i,
 			Username:  username,
 			Password:  password,
-			Salary:    float64(i * 1000),
+			Salary:    decimal.NewFromInt(int64(i * 1000)),
 			RoleID:    uint(2),
 			CreatedBy: 999,
 		}
@@ -93,9 +96,9 @@ func Seed44(db *gorm.DB) {
 	var overtimes = []models.Overtime{}
 	var attendances = []models.Attendance{}
 	var reimbursements = []models.Reimbursement{
-		{UserID: userID, Amount: 400, Date: startDate},
-		{UserID: userID, Amount: 40, Date: startDate},
-		{UserID: userID, Amount: 4, Date: startDate},
+		{UserID: userID, Amount: decimal.NewFromInt(400), Date: startDate, Status: models.ReimbursementStatusApproved},
+		{UserID: userID, Amount: decimal.NewFromInt(40), Date: startDate, Status: models.ReimbursementStatusApproved},
+		{UserID: userID, Amount: decimal.NewFromInt(4), Date: startDate, Status: models.ReimbursementStatusApproved},
 	}
 
 	for d := 0; d < 31; d++ {
@@ -112,6 +115,7 @@ func Seed44(db *gorm.DB) {
 					UserID:      userID,
 					Date:        current,
 					HoursWorked: overtimeHours,
+					Status:      models.OvertimeStatusApproved,
 					CreatedBy:   userID,
 				})
 
@@ -0,0 +1,126 @@
+package seed
+
+import (
+	"dealls-case-study/internal/models"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// terBand is an upper bound of monthly gross income and the TER rate (in percent) up to it.
+// The last band of each category has no upper bound (0).
+type terBand struct {
+	upper int64
+	rate  string
+}
+
+// PP 58/2023 and PMK 168/2023, effective 1 January 2024
+var terPP58 = map[string][]terBand{
+	"A": {
+		{5400000, "0"}, {5650000, "0.25"}, {5950000, "0.5"}, {6300000, "0.75"}, {6750000, "1"},
+		{7500000, "1.25"}, {8550000, "1.5"}, {9650000, "1.75"}, {10050000, "2"}, {10350000, "2.25"},
+		{10700000, "2.5"}, {11050000, "3"}, {11600000, "3.5"}, {12500000, "4"}, {13750000, "5"},
+		{15100000, "6"}, {16950000, "7"}, {19750000, "8"}, {24150000, "9"}, {26450000, "10"},
+		{28000000, "11"}, {30050000, "12"}, {32400000, "13"}, {35400000, "14"}, {39100000, "15"},
+		{43850000, "16"}, {47800000, "17"}, {51400000, "18"}, {56300000, "19"}, {62200000, "20"},
+		{68600000, "21"}, {77500000, "22"}, {89000000, "23"}, {103000000, "24"}, {125000000, "25"},
+		{157000000, "26"}, {206000000, "27"}, {337000000, "28"}, {454000000, "29"}, {550000000, "30"},
+		{695000000, "31"}, {910000000, "32"}, {1400000000, "33"}, {0, "34"},
+	},
+	"B": {
+		{6200000, "0"}, {6500000, "0.25"}, {6850000, "0.5"}, {7300000, "0.75"}, {9200000, "1"},
+		{10750000, "1.5"}, {11250000, "2"}, {11600000, "2.5"}, {12600000, "3"}, {13600000, "4"},
+		{14950000, "5"}, {16400000, "6"}, {18450000, "7"}, {21850000, "8"}, {26000000, "9"},
+		{27700000, "10"}, {29350000, "11"}, {31450000, "12"}, {33950000, "13"}, {37100000, "14"},
+		{41100000, "15"}, {45800000, "16"}, {49500000, "17"}, {53800000, "18"}, {58500000, "19"},
+		{64000000, "20"}, {71000000, "21"}, {80000000, "22"}, {93000000, "23"}, {109000000, "24"},
+		{129000000, "25"}, {163000000, "26"}, {211000000, "27"}, {374000000, "28"}, {459000000, "29"},
+		{555000000, "30"}, {704000000, "31"}, {957000000, "32"}, {1405000000, "33"}, {0, "34"},
+	},
+	"C": {
+		{6600000, "0"}, {6950000, "0.25"}, {7350000, "0.5"}, {7800000, "0.75"}, {8850000, "1"},
+		{9800000, "1.25"}, {10950000, "1.5"}, {11200000, "1.75"}, {12050000, "2"}, {12950000, "3"},
+		{14150000, "4"}, {15550000, "5"}, {17050000, "6"}, {19500000, "7"}, {22700000, "8"},
+		{26600000, "9"}, {28100000, "10"}, {30100000, "11"}, {32600000, "12"}, {35400000, "13"},
+		{38900000, "14"}, {43000000, "15"}, {47400000, "16"}, {51200000, "17"}, {55800000, "18"},
+		{60400000, "19"}, {66700000, "20"}, {74500000, "21"}, {83200000, "22"}, {95600000, "23"},
+		{110000000, "24"}, {134000000, "25"}, {169000000, "26"}, {221000000, "27"}, {390000000, "28"},
+		{463000000, "29"}, {561000000, "30"}, {709000000, "31"}, {965000000, "32"}, {1419000000, "33"},
+		{0, "34"},
+	},
+}
+
+// UU 7/2021 (HPP) Pasal 17 brackets
+var progressiveHPP = []terBand{
+	{60000000, "5"}, {250000000, "15"}, {500000000, "25"}, {5000000000, "30"}, {0, "35"},
+}
+
+// PMK 101/2016 PTKP amounts and their PP 58/2023 TER category
+var ptkpPMK101 = []models.TaxPTKP{
+	{Status: "TK/0", TERCategory: "A", Amount: decimal.NewFromInt(54000000)},
+	{Status: "TK/1", TERCategory: "A", Amount: decimal.NewFromInt(58500000)},
+	{Status: "TK/2", TERCategory: "B", Amount: decimal.NewFromInt(63000000)},
+	{Status: "TK/3", TERCategory: "B", Amount: decimal.NewFromInt(67500000)},
+	{Status: "K/0", TERCategory: "A", Amount: decimal.NewFromInt(58500000)},
+	{Status: "K/1", TERCategory: "B", Amount: decimal.NewFromInt(63000000)},
+	{Status: "K/2", TERCategory: "B", Amount: decimal.NewFromInt(67500000)},
+	{Status: "K/3", TERCategory: "C", Amount: decimal.NewFromInt(72000000)},
+}
+
+// TaxTables seeds the PPh 21 tax tables. Existing versions are left untouched.
+func TaxTables(db *gorm.DB) error {
+	table := pp58TaxTable()
+
+	var count int64
+	if err := db.Model(&models.TaxTable{}).Where("version = ?", table.Version).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := db.Create(&table).Error; err != nil {
+		log.Printf("Failed to seed tax table %s: %v", table.Version, err)
+		return err
+	}
+	return nil
+}
+
+func pp58TaxTable() models.TaxTable {
+	table := models.TaxTable{
+		Version:              "PP-58-2023",
+		EffectiveFrom:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		OccupationalCostRate: decimal.RequireFromString("0.05"),
+		OccupationalCostCap:  decimal.NewFromInt(6000000),
+		NonNPWPSurchargeRate: decimal.RequireFromString("0.2"),
+		PTKPs:                append([]models.TaxPTKP(nil), ptkpPMK101...),
+		CreatedBy:            999,
+	}
+	for _, category := range []string{"A", "B", "C"} {
+		table.Brackets = append(table.Brackets, toTaxBrackets(models.TaxBracketKindTER, category, terPP58[category])...)
+	}
+	table.Brackets = append(table.Brackets, toTaxBrackets(models.TaxBracketKindProgressive, "", progressiveHPP)...)
+	return table
+}
+
+func toTaxBrackets(kind string, category string, bands []terBand) []models.TaxBracket {
+	var brackets []models.TaxBracket
+	lower := decimal.Zero
+	for _, band := range bands {
+		bracket := models.TaxBracket{
+			Kind:       kind,
+			Category:   category,
+			LowerBound: lower,
+			Rate:       decimal.RequireFromString(band.rate).Div(decimal.NewFromInt(100)),
+		}
+		if band.upper > 0 {
+			upper := decimal.NewFromInt(band.upper)
+			bracket.UpperBound = decimal.NewNullDecimal(upper)
+			lower = upper
+		}
+		brackets = append(brackets, bracket)
+	}
+	return brackets
+}
@@ -0,0 +1,51 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPP58TaxTable_MonthlyWithholding(t *testing.T) {
+	table := pp58TaxTable()
+
+	tests := []struct {
+		status   string
+		npwp     bool
+		gross    int64
+		rate     string
+		withheld string
+	}{
+		{"TK/0", true, 5000000, "0", "0"},
+		{"TK/0", true, 10000000, "0.02", "200000"},
+		{"TK/0", false, 10000000, "0.02", "240000"},
+		{"K/1", true, 10000000, "0.015", "150000"},
+		{"K/3", true, 10000000, "0.015", "150000"},
+		{"TK/0", true, 2000000000, "0.34", "680000000"},
+	}
+
+	for _, tt := range tests {
+		ptkp, ok := table.PTKP(tt.status)
+		require.True(t, ok, tt.status)
+
+		rate, tax := table.MonthlyWithholding(ptkp, tt.npwp, decimal.NewFromInt(tt.gross))
+		assert.Equal(t, tt.rate, rate.String(), "%s %d", tt.status, tt.gross)
+		assert.Equal(t, tt.withheld, tax.String(), "%s %d", tt.status, tt.gross)
+	}
+}
+
+func TestPP58TaxTable_AnnualWithholding(t *testing.T) {
+	table := pp58TaxTable()
+	ptkp, ok := table.PTKP("TK/0")
+	require.True(t, ok)
+
+	// 120,000,000 - 6,000,000 biaya jabatan - 54,000,000 PTKP = 60,000,000 PKP at 5%
+	annual := table.AnnualWithholding(ptkp, true, decimal.NewFromInt(120000000), decimal.Zero, decimal.Zero)
+	assert.Equal(t, "3000000", annual.String())
+
+	// 11 months of TER at 2% already withheld 2,200,000
+	trueUp := table.AnnualWithholding(ptkp, true, decimal.NewFromInt(120000000), decimal.Zero, decimal.NewFromInt(2200000))
+	assert.Equal(t, "800000", trueUp.String())
+}
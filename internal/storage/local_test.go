@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStorage checks the behaviour every Storage implementation shares.
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	content := "%PDF-1.4 receipt"
+	require.NoError(t, s.Put(ctx, "reimbursements/1/receipt.pdf", strings.NewReader(content), int64(len(content)), "application/pdf"))
+
+	r, err := s.Get(ctx, "reimbursements/1/receipt.pdf")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	r.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	assert.NoError(t, s.Delete(ctx, "reimbursements/1/receipt.pdf"))
+	_, err = s.Get(ctx, "reimbursements/1/receipt.pdf")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// deleting a missing object is not an error
+	assert.NoError(t, s.Delete(ctx, "reimbursements/1/receipt.pdf"))
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "reimbursements/../../secret", "a//b"} {
+		assert.Error(t, s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), key)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	testStorage(t, s)
+}
@@ -3,15 +3,26 @@ package utils
 import "time"
 
 func CountWeekdays(start, end time.Time) int {
+	return CountWorkingDays(start, end, nil)
+}
+
+// CountWorkingDays counts the weekdays from start to end that are not holidays.
+// Holidays are keyed by date in the "2006-01-02" format.
+func CountWorkingDays(start, end time.Time, holidays map[string]bool) int {
 	if start.After(end) {
 		start, end = end, start
 	}
 
 	workingDays := 0
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		if d.Weekday() >= time.Monday && d.Weekday() <= time.Friday {
+		if IsWorkingDay(d, holidays) {
 			workingDays++
 		}
 	}
 	return workingDays
 }
+
+// IsWorkingDay reports whether the date is a weekday and not a holiday.
+func IsWorkingDay(d time.Time, holidays map[string]bool) bool {
+	return d.Weekday() >= time.Monday && d.Weekday() <= time.Friday && !holidays[d.Format("2006-01-02")]
+}
@@ -32,3 +32,14 @@ func TestCountWeekdays(t *testing.T) {
 		})
 	}
 }
+
+func TestCountWorkingDays(t *testing.T) {
+	start, _ := time.Parse("2006-01-02", "2025-06-01")
+	end, _ := time.Parse("2006-01-02", "2025-06-30")
+
+	// Idul Adha on a Friday, Islamic New Year on a Friday and a holiday falling on a Sunday
+	holidays := map[string]bool{"2025-06-06": true, "2025-06-27": true, "2025-06-01": true}
+
+	assert.Equal(t, 19, CountWorkingDays(start, end, holidays))
+	assert.Equal(t, 21, CountWorkingDays(start, end, nil))
+}
@@ -0,0 +1,318 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Formula is a parsed arithmetic expression over named decimal variables, e.g.
+// "monthly_salary * 0.1" or "max(amount * days_attended, 100000)".
+// It supports + - * /, parentheses, unary minus and the functions min and max.
+type Formula struct {
+	root formulaNode
+}
+
+// ParseFormula parses an expression. Variables are only checked when evaluated,
+// use Variables to validate them up front.
+func ParseFormula(expr string) (*Formula, error) {
+	p := &formulaParser{input: expr}
+	p.next()
+
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos)
+	}
+	return &Formula{root: root}, nil
+}
+
+// Variables returns the names of the variables the formula refers to.
+func (f *Formula) Variables() []string {
+	seen := map[string]bool{}
+	var names []string
+	f.root.walk(func(n formulaNode) {
+		if v, ok := n.(variableNode); ok && !seen[string(v)] {
+			seen[string(v)] = true
+			names = append(names, string(v))
+		}
+	})
+	return names
+}
+
+// Evaluate computes the formula with the given variable values.
+func (f *Formula) Evaluate(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	return f.root.eval(vars)
+}
+
+type formulaNode interface {
+	eval(vars map[string]decimal.Decimal) (decimal.Decimal, error)
+	walk(fn func(formulaNode))
+}
+
+type numberNode decimal.Decimal
+
+func (n numberNode) eval(map[string]decimal.Decimal) (decimal.Decimal, error) {
+	return decimal.Decimal(n), nil
+}
+
+func (n numberNode) walk(fn func(formulaNode)) { fn(n) }
+
+type variableNode string
+
+func (n variableNode) eval(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	v, ok := vars[string(n)]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown variable %q", string(n))
+	}
+	return v, nil
+}
+
+func (n variableNode) walk(fn func(formulaNode)) { fn(n) }
+
+type negateNode struct {
+	operand formulaNode
+}
+
+func (n negateNode) eval(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return v.Neg(), nil
+}
+
+func (n negateNode) walk(fn func(formulaNode)) {
+	fn(n)
+	n.operand.walk(fn)
+}
+
+type binaryNode struct {
+	op          byte
+	left, right formulaNode
+}
+
+func (n binaryNode) eval(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	switch n.op {
+	case '+':
+		return l.Add(r), nil
+	case '-':
+		return l.Sub(r), nil
+	case '*':
+		return l.Mul(r), nil
+	default:
+		if r.IsZero() {
+			return decimal.Zero, fmt.Errorf("division by zero")
+		}
+		return l.Div(r), nil
+	}
+}
+
+func (n binaryNode) walk(fn func(formulaNode)) {
+	fn(n)
+	n.left.walk(fn)
+	n.right.walk(fn)
+}
+
+type callNode struct {
+	name string
+	args []formulaNode
+}
+
+func (n callNode) eval(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	values := make([]decimal.Decimal, 0, len(n.args))
+	for _, arg := range n.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		values = append(values, v)
+	}
+
+	if n.name == "min" {
+		return decimal.Min(values[0], values[1:]...), nil
+	}
+	return decimal.Max(values[0], values[1:]...), nil
+}
+
+func (n callNode) walk(fn func(formulaNode)) {
+	fn(n)
+	for _, arg := range n.args {
+		arg.walk(fn)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type formulaParser struct {
+	input string
+	pos   int
+	tok   token
+}
+
+func (p *formulaParser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokEOF, text: "end of formula", pos: start}
+		return
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.input[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	case strings.IndexByte("+-*/(),", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokOperator, text: string(c), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokInvalid, text: string(c), pos: start}
+	}
+}
+
+func (p *formulaParser) isOperator(op string) bool {
+	return p.tok.kind == tokOperator && p.tok.text == op
+}
+
+// expr := term (('+' | '-') term)*
+func (p *formulaParser) parseExpr() (formulaNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+") || p.isOperator("-") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// term := unary (('*' | '/') unary)*
+func (p *formulaParser) parseTerm() (formulaNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*") || p.isOperator("/") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// unary := '-' unary | primary
+func (p *formulaParser) parseUnary() (formulaNode, error) {
+	if p.isOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// primary := number | variable | function '(' expr (',' expr)* ')' | '(' expr ')'
+func (p *formulaParser) parsePrimary() (formulaNode, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		v, err := decimal.NewFromString(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		p.next()
+		return numberNode(v), nil
+
+	case tok.kind == tokIdent:
+		p.next()
+		if !p.isOperator("(") {
+			return variableNode(tok.text), nil
+		}
+		if tok.text != "min" && tok.text != "max" {
+			return nil, fmt.Errorf("unknown function %q at position %d", tok.text, tok.pos)
+		}
+		p.next()
+
+		var args []formulaNode
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+		if !p.isOperator(")") {
+			return nil, fmt.Errorf("expected ) at position %d", p.tok.pos)
+		}
+		p.next()
+		return callNode{name: tok.text, args: args}, nil
+
+	case p.isOperator("("):
+		p.next()
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOperator(")") {
+			return nil, fmt.Errorf("expected ) at position %d", p.tok.pos)
+		}
+		p.next()
+		return inner, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
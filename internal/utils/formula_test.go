@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormula_Evaluate(t *testing.T) {
+	vars := map[string]decimal.Decimal{
+		"monthly_salary": decimal.NewFromInt(10000000),
+		"days_attended":  decimal.NewFromInt(20),
+		"amount":         decimal.NewFromInt(25000),
+	}
+
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"150000", "150000"},
+		{"monthly_salary * 0.1", "1000000"},
+		{"amount * days_attended", "500000"},
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"-amount + 30000", "5000"},
+		{"10 - 2 - 3", "5"},
+		{"monthly_salary / 4", "2500000"},
+		{"min(amount * days_attended, 400000)", "400000"},
+		{"max(monthly_salary * 0.01, 150000, 50000)", "150000"},
+		{"  Monthly_Salary*0.05 ", "500000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFormula(tt.expr)
+			assert.NoError(t, err)
+
+			actual, err := f.Evaluate(vars)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual.String())
+		})
+	}
+}
+
+func TestFormula_EvaluateErrors(t *testing.T) {
+	f, err := ParseFormula("unknown_var * 2")
+	assert.NoError(t, err)
+	_, err = f.Evaluate(map[string]decimal.Decimal{})
+	assert.EqualError(t, err, `unknown variable "unknown_var"`)
+
+	f, err = ParseFormula("10 / (2 - 2)")
+	assert.NoError(t, err)
+	_, err = f.Evaluate(nil)
+	assert.EqualError(t, err, "division by zero")
+}
+
+func TestParseFormula_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "1 +", "(1 + 2", "1 2", "sqrt(4)", "min()", "1 % 2", "1..2"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFormula(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormula_Variables(t *testing.T) {
+	f, err := ParseFormula("max(amount * days_attended, amount) - monthly_salary / 100")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"amount", "days_attended", "monthly_salary"}, f.Variables())
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CalendarEvent is a VEVENT of an iCalendar file. Start and End are dates at midnight UTC,
+// End is exclusive as in DTEND.
+type CalendarEvent struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Dates returns every date the event covers.
+func (e CalendarEvent) Dates() []time.Time {
+	var dates []time.Time
+	for d := e.Start; d.Before(e.End); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// ParseICS reads the events of an iCalendar (RFC 5545) file, such as the public holiday
+// calendars published by Google or Apple. Only the date part of DTSTART and DTEND is kept.
+func ParseICS(r io.Reader) ([]CalendarEvent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, errors.New("not an iCalendar file")
+	}
+
+	var events []CalendarEvent
+	var event *CalendarEvent
+	for i, line := range lines {
+		name, params, value, ok := splitICSProperty(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid line %d: %q", i+1, line)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = &CalendarEvent{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event == nil {
+				return nil, fmt.Errorf("unexpected END:VEVENT at line %d", i+1)
+			}
+			if event.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", event.Summary)
+			}
+			if !event.End.After(event.Start) {
+				event.End = event.Start.AddDate(0, 0, 1)
+			}
+			events = append(events, *event)
+			event = nil
+		case event == nil:
+			// calendar properties and other components are ignored
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = unescapeICSText(value)
+		case name == "DTSTART" || name == "DTEND":
+			date, err := parseICSDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q at line %d", name, value, i+1)
+			}
+			if name == "DTSTART" {
+				event.Start = date
+			} else if strings.Contains(strings.ToUpper(params), "VALUE=DATE") && !strings.Contains(value, "T") {
+				event.End = date
+			} else {
+				// a date-time end is on the last day of the event
+				event.End = date.AddDate(0, 0, 1)
+			}
+		}
+	}
+	if event != nil {
+		return nil, errors.New("unterminated VEVENT")
+	}
+	return events, nil
+}
+
+// unfoldICSLines joins folded lines, a line starting with a space or tab continues the previous one.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitICSProperty splits "NAME;PARAM=X:value" into its name, parameters and value.
+func splitICSProperty(line string) (name, params, value string, ok bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon < 0 {
+		return "", "", "", false
+	}
+	name, value = line[:colon], line[colon+1:]
+	if semi := strings.IndexByte(name, ';'); semi >= 0 {
+		name, params = name[:semi], name[semi+1:]
+	}
+	return strings.ToUpper(name), params, value, true
+}
+
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("too short")
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250331",
+		"DTEND;VALUE=DATE:20250402",
+		"UID:20250331_idulfitri@google.com",
+		"SUMMARY:Hari Raya Idul Fitri\\, Cuti Bersama",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250817",
+		"UID:20250817_independence@google.com",
+		"SUMMARY:Hari Kemerdekaan Republik Indone",
+		" sia",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20251225T000000Z",
+		"DTEND:20251225T235959Z",
+		"SUMMARY:Hari Raya Natal",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := ParseICS(strings.NewReader(ics))
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	assert.Equal(t, "20250331_idulfitri@google.com", events[0].UID)
+	assert.Equal(t, "Hari Raya Idul Fitri, Cuti Bersama", events[0].Summary)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	}, events[0].Dates())
+
+	// folded summary, no DTEND means a single day
+	assert.Equal(t, "Hari Kemerdekaan Republik Indonesia", events[1].Summary)
+	assert.Len(t, events[1].Dates(), 1)
+
+	// date-time values keep their date
+	assert.Equal(t, []time.Time{time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)}, events[2].Dates())
+}
+
+func TestParseICS_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{"not a calendar", "hello world"},
+		{"missing start", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Nyepi\nEND:VEVENT\nEND:VCALENDAR"},
+		{"invalid date", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:2025-03-29\nEND:VEVENT\nEND:VCALENDAR"},
+		{"unterminated event", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20250329\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseICS(strings.NewReader(tt.ics))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero (1.005 -> 1.01)
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even digit, a.k.a. banker's rounding (1.005 -> 1.00)
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+type RoundingScope string
+
+const (
+	// RoundPerLineItem rounds every line item (base salary, overtime pay, ...) before summing
+	RoundPerLineItem RoundingScope = "line_item"
+	// RoundTotal keeps line items at full precision and only rounds the total
+	RoundTotal RoundingScope = "total"
+)
+
+const defaultMoneyScale = 2
+
+// MoneyRounding describes how computed amounts are rounded.
+type MoneyRounding struct {
+	Scale int32
+	Mode  RoundingMode
+	Scope RoundingScope
+}
+
+// MoneyRoundingFromEnv reads the rounding rules from MONEY_SCALE, MONEY_ROUNDING_MODE
+// and MONEY_ROUNDING_SCOPE, falling back to 2 decimals, half-up, per line item.
+func MoneyRoundingFromEnv() MoneyRounding {
+	r := MoneyRounding{
+		Scale: defaultMoneyScale,
+		Mode:  RoundHalfUp,
+		Scope: RoundPerLineItem,
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("MONEY_SCALE")); err == nil && v >= 0 {
+		r.Scale = int32(v)
+	}
+	if v := RoundingMode(os.Getenv("MONEY_ROUNDING_MODE")); v == RoundHalfUp || v == RoundHalfEven {
+		r.Mode = v
+	}
+	if v := RoundingScope(os.Getenv("MONEY_ROUNDING_SCOPE")); v == RoundPerLineItem || v == RoundTotal {
+		r.Scope = v
+	}
+
+	return r
+}
+
+// Round rounds an amount to the configured scale using the configured mode.
+func (r MoneyRounding) Round(d decimal.Decimal) decimal.Decimal {
+	if r.Mode == RoundHalfEven {
+		return d.RoundBank(r.Scale)
+	}
+	return d.Round(r.Scale)
+}
+
+// RoundLine rounds a line item, unless rounding is only applied to totals.
+func (r MoneyRounding) RoundLine(d decimal.Decimal) decimal.Decimal {
+	if r.Scope == RoundTotal {
+		return d
+	}
+	return r.Round(d)
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyRounding_Round(t *testing.T) {
+	tests := []struct {
+		mode     RoundingMode
+		value    string
+		expected string
+	}{
+		{RoundHalfUp, "1.005", "1.01"},
+		{RoundHalfUp, "1.015", "1.02"},
+		{RoundHalfUp, "-1.005", "-1.01"},
+		{RoundHalfEven, "1.005", "1"},
+		{RoundHalfEven, "1.015", "1.02"},
+		{RoundHalfEven, "1.0051", "1.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+" "+tt.value, func(t *testing.T) {
+			r := MoneyRounding{Scale: 2, Mode: tt.mode, Scope: RoundPerLineItem}
+			actual := r.Round(decimal.RequireFromString(tt.value))
+			assert.True(t, decimal.RequireFromString(tt.expected).Equal(actual), "got %s", actual)
+		})
+	}
+}
+
+func TestMoneyRounding_RoundLine(t *testing.T) {
+	value := decimal.RequireFromString("104761.904761")
+
+	perLine := MoneyRounding{Scale: 0, Mode: RoundHalfUp, Scope: RoundPerLineItem}
+	assert.Equal(t, "104762", perLine.RoundLine(value).String())
+
+	totalOnly := MoneyRounding{Scale: 0, Mode: RoundHalfUp, Scope: RoundTotal}
+	assert.Equal(t, "104761.904761", totalOnly.RoundLine(value).String())
+	assert.Equal(t, "104762", totalOnly.Round(value).String())
+}
+
+func TestMoneyRoundingFromEnv(t *testing.T) {
+	t.Setenv("MONEY_SCALE", "")
+	t.Setenv("MONEY_ROUNDING_MODE", "")
+	t.Setenv("MONEY_ROUNDING_SCOPE", "")
+	assert.Equal(t, MoneyRounding{Scale: 2, Mode: RoundHalfUp, Scope: RoundPerLineItem}, MoneyRoundingFromEnv())
+
+	t.Setenv("MONEY_SCALE", "0")
+	t.Setenv("MONEY_ROUNDING_MODE", "half_even")
+	t.Setenv("MONEY_ROUNDING_SCOPE", "total")
+	assert.Equal(t, MoneyRounding{Scale: 0, Mode: RoundHalfEven, Scope: RoundTotal}, MoneyRoundingFromEnv())
+
+	t.Setenv("MONEY_ROUNDING_MODE", "bogus")
+	assert.Equal(t, RoundHalfUp, MoneyRoundingFromEnv().Mode)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	defaultOvertimeSubmissionDays = 7
+	defaultOvertimeMonthlyCap     = 40
+)
+
+// OvertimeLimits bound the overtime an employee can submit, on top of the 3 hours a day.
+type OvertimeLimits struct {
+	// SubmissionDays is how many days back overtime can be submitted, 0 for the same day only
+	SubmissionDays int
+	// MonthlyCap is the most overtime hours an employee can submit in a calendar month, 0 for no cap
+	MonthlyCap float64
+}
+
+// OvertimeLimitsFromEnv reads the limits from OVERTIME_SUBMISSION_DAYS and OVERTIME_MONTHLY_CAP,
+// falling back to 7 days back and 40 hours a month.
+func OvertimeLimitsFromEnv() OvertimeLimits {
+	l := OvertimeLimits{
+		SubmissionDays: defaultOvertimeSubmissionDays,
+		MonthlyCap:     defaultOvertimeMonthlyCap,
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("OVERTIME_SUBMISSION_DAYS")); err == nil && v >= 0 {
+		l.SubmissionDays = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("OVERTIME_MONTHLY_CAP"), 64); err == nil && v >= 0 {
+		l.MonthlyCap = v
+	}
+
+	return l
+}
+
+// WithMonthlyCap is the limits with the employee's own monthly cap, if they have one.
+func (l OvertimeLimits) WithMonthlyCap(hours *float64) OvertimeLimits {
+	if hours != nil {
+		l.MonthlyCap = *hours
+	}
+	return l
+}
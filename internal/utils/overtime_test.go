@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertimeLimitsFromEnv(t *testing.T) {
+	t.Setenv("OVERTIME_SUBMISSION_DAYS", "")
+	t.Setenv("OVERTIME_MONTHLY_CAP", "")
+	assert.Equal(t, OvertimeLimits{SubmissionDays: 7, MonthlyCap: 40}, OvertimeLimitsFromEnv())
+
+	t.Setenv("OVERTIME_SUBMISSION_DAYS", "0")
+	t.Setenv("OVERTIME_MONTHLY_CAP", "12.5")
+	assert.Equal(t, OvertimeLimits{SubmissionDays: 0, MonthlyCap: 12.5}, OvertimeLimitsFromEnv())
+
+	t.Setenv("OVERTIME_SUBMISSION_DAYS", "-1")
+	t.Setenv("OVERTIME_MONTHLY_CAP", "a lot")
+	assert.Equal(t, OvertimeLimits{SubmissionDays: 7, MonthlyCap: 40}, OvertimeLimitsFromEnv())
+}
+
+func TestOvertimeLimits_WithMonthlyCap(t *testing.T) {
+	l := OvertimeLimits{SubmissionDays: 7, MonthlyCap: 40}
+	assert.Equal(t, l, l.WithMonthlyCap(nil))
+
+	none := 0.0
+	assert.Equal(t, OvertimeLimits{SubmissionDays: 7, MonthlyCap: 0}, l.WithMonthlyCap(&none))
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Page is the page of a list endpoint and its order, read from the query string:
+// ?page=1&per_page=20&sort=date&order=desc. Lists are sorted by id after the sort column
+// so pages never overlap.
+type Page struct {
+	Page    int
+	PerPage int
+	// Sort is the column the list is sorted by
+	Sort string
+	Desc bool
+}
+
+// ParsePage reads the page of a list from the request. sortable maps the sort values the list accepts
+// to their columns, defaultSort is used without one. Lists are in descending order unless order=asc.
+func ParsePage(c *gin.Context, sortable map[string]string, defaultSort string) (Page, error) {
+	p := Page{Page: 1, PerPage: DefaultPerPage, Sort: sortable[defaultSort], Desc: true}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return Page{}, errors.New("page must be a number from 1")
+		}
+		p.Page = page
+	}
+	if v := c.Query("per_page"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil || perPage < 1 || perPage > MaxPerPage {
+			return Page{}, fmt.Errorf("per_page must be a number from 1 to %d", MaxPerPage)
+		}
+		p.PerPage = perPage
+	}
+	if v := c.Query("sort"); v != "" {
+		column, ok := sortable[v]
+		if !ok {
+			keys := make([]string, 0, len(sortable))
+			for key := range sortable {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return Page{}, fmt.Errorf("sort must be one of %s", strings.Join(keys, ", "))
+		}
+		p.Sort = column
+	}
+	switch c.Query("order") {
+	case "", "desc":
+	case "asc":
+		p.Desc = false
+	default:
+		return Page{}, errors.New("order must be asc or desc")
+	}
+
+	return p, nil
+}
+
+// Offset is the number of rows before the page.
+func (p Page) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// OrderBy is the ORDER BY clause of the page.
+func (p Page) OrderBy() string {
+	direction := "ASC"
+	if p.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, id %s", p.Sort, direction, direction)
+}
+
+// WrapPageResponse wraps a page of a list with the page it is and the total number of rows.
+func WrapPageResponse(data interface{}, p Page, total int64) gin.H {
+	totalPages := int((total + int64(p.PerPage) - 1) / int64(p.PerPage))
+
+	return gin.H{
+		"message": "success",
+		"data":    data,
+		"meta": gin.H{
+			"page":        p.Page,
+			"per_page":    p.PerPage,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	}
+}
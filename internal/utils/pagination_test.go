@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	sortable := map[string]string{"date": "date", "amount": "amount"}
+	parse := func(query string) (Page, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		return ParsePage(c, sortable, "date")
+	}
+
+	p, err := parse("")
+	assert.Nil(t, err)
+	assert.Equal(t, Page{Page: 1, PerPage: DefaultPerPage, Sort: "date", Desc: true}, p)
+	assert.Equal(t, 0, p.Offset())
+	assert.Equal(t, "date DESC, id DESC", p.OrderBy())
+
+	p, err = parse("page=3&per_page=10&sort=amount&order=asc")
+	assert.Nil(t, err)
+	assert.Equal(t, 20, p.Offset())
+	assert.Equal(t, "amount ASC, id ASC", p.OrderBy())
+
+	for _, query := range []string{"page=0", "page=x", "per_page=101", "per_page=0", "order=up"} {
+		_, err = parse(query)
+		assert.NotNil(t, err, query)
+	}
+	_, err = parse("sort=user_id")
+	assert.EqualError(t, err, "sort must be one of amount, date")
+}
+
+func TestWrapPageResponse(t *testing.T) {
+	response := WrapPageResponse([]int{1, 2}, Page{Page: 2, PerPage: 2}, 5)
+
+	assert.Equal(t, "success", response["message"])
+	assert.Equal(t, []int{1, 2}, response["data"])
+	assert.Equal(t, gin.H{"page": 2, "per_page": 2, "total": int64(5), "total_pages": 3}, response["meta"])
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"time"
+
+	// embed the IANA database so zones resolve on hosts without one
+	_ "time/tzdata"
+)
+
+// DefaultCompanyTimezone is used when COMPANY_TIMEZONE is not set.
+const DefaultCompanyTimezone = "Asia/Jakarta"
+
+// CompanyLocation is the timezone payroll periods are in and the default timezone of employees,
+// configured with COMPANY_TIMEZONE. An unknown zone falls back to DefaultCompanyTimezone.
+func CompanyLocation() *time.Location {
+	if name := os.Getenv("COMPANY_TIMEZONE"); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	loc, _ := time.LoadLocation(DefaultCompanyTimezone)
+	return loc
+}
+
+// LoadTimezone resolves an employee's IANA timezone, an empty one is the company's.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return CompanyLocation(), nil
+	}
+	return time.LoadLocation(name)
+}
+
+// CalendarDate returns the calendar date of t in loc as midnight UTC, the way dates are stored.
+func CalendarDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseTimeOfDay parses a wall clock time such as "17:30" into the duration since midnight.
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyLocation(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "")
+	assert.Equal(t, "Asia/Jakarta", CompanyLocation().String())
+
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Makassar")
+	assert.Equal(t, "Asia/Makassar", CompanyLocation().String())
+
+	t.Setenv("COMPANY_TIMEZONE", "Mars/Olympus_Mons")
+	assert.Equal(t, "Asia/Jakarta", CompanyLocation().String())
+}
+
+func TestLoadTimezone(t *testing.T) {
+	t.Setenv("COMPANY_TIMEZONE", "Asia/Jakarta")
+
+	loc, err := LoadTimezone("")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", loc.String())
+
+	loc, err = LoadTimezone("Asia/Jayapura")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jayapura", loc.String())
+
+	_, err = LoadTimezone("WITA")
+	assert.Error(t, err)
+}
+
+func TestCalendarDate(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	makassar, _ := time.LoadLocation("Asia/Makassar")
+	jayapura, _ := time.LoadLocation("Asia/Jayapura")
+
+	// 23:30 in Jakarta on the 5th is already the 6th in Makassar and Jayapura
+	instant := time.Date(2025, 6, 5, 16, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), CalendarDate(instant, jakarta))
+	assert.Equal(t, time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), CalendarDate(instant, makassar))
+	assert.Equal(t, time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), CalendarDate(instant, jayapura))
+	assert.Equal(t, time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), CalendarDate(instant, time.UTC))
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	d, err := ParseTimeOfDay("17:30")
+	assert.NoError(t, err)
+	assert.Equal(t, 17*time.Hour+30*time.Minute, d)
+
+	d, err = ParseTimeOfDay("00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	for _, s := range []string{"", "5pm", "24:00", "17:60"} {
+		_, err = ParseTimeOfDay(s)
+		assert.Error(t, err, s)
+	}
+}
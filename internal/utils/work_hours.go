@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultWorkHoursRounding = 15 * time.Minute
+	defaultBreak             = time.Hour
+	defaultBreakAfter        = 6 * time.Hour
+)
+
+// WorkHours describes how the time between check-in and check-out becomes the hours
+// an hourly employee is paid for.
+type WorkHours struct {
+	// RoundTo rounds the time of a day to the nearest multiple, 0 keeps it to the minute
+	RoundTo time.Duration
+	// Break is deducted from days longer than BreakAfter, at most the time worked past BreakAfter
+	Break      time.Duration
+	BreakAfter time.Duration
+}
+
+// WorkHoursFromEnv reads the rules from WORK_HOURS_ROUNDING, WORK_HOURS_BREAK and
+// WORK_HOURS_BREAK_AFTER, durations such as "15m", falling back to the nearest 15 minutes
+// and a 1 hour break on days longer than 6 hours.
+func WorkHoursFromEnv() WorkHours {
+	w := WorkHours{
+		RoundTo:    defaultWorkHoursRounding,
+		Break:      defaultBreak,
+		BreakAfter: defaultBreakAfter,
+	}
+
+	if v, err := time.ParseDuration(os.Getenv("WORK_HOURS_ROUNDING")); err == nil && v >= 0 {
+		w.RoundTo = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("WORK_HOURS_BREAK")); err == nil && v >= 0 {
+		w.Break = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("WORK_HOURS_BREAK_AFTER")); err == nil && v >= 0 {
+		w.BreakAfter = v
+	}
+
+	return w
+}
+
+// BreakFor is the break deducted from a day worked for the given time. It grows with the time
+// worked past BreakAfter up to Break, so working longer never pays less.
+func (w WorkHours) BreakFor(worked time.Duration) time.Duration {
+	if w.Break <= 0 || worked <= w.BreakAfter {
+		return 0
+	}
+	return min(w.Break, worked-w.BreakAfter)
+}
+
+// Paid returns the hours paid for a day worked for the given time: the break deducted,
+// then rounded to the nearest RoundTo.
+func (w WorkHours) Paid(worked time.Duration) decimal.Decimal {
+	paid := worked - w.BreakFor(worked)
+	if w.RoundTo > 0 {
+		paid = paid.Round(w.RoundTo)
+	} else {
+		paid = paid.Truncate(time.Minute)
+	}
+	if paid < 0 {
+		paid = 0
+	}
+	return decimal.NewFromInt(int64(paid / time.Minute)).Div(decimal.NewFromInt(60)).Round(4)
+}
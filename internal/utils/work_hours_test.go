@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkHoursFromEnv(t *testing.T) {
+	t.Setenv("WORK_HOURS_ROUNDING", "")
+	t.Setenv("WORK_HOURS_BREAK", "")
+	t.Setenv("WORK_HOURS_BREAK_AFTER", "")
+	assert.Equal(t, WorkHours{RoundTo: 15 * time.Minute, Break: time.Hour, BreakAfter: 6 * time.Hour}, WorkHoursFromEnv())
+
+	t.Setenv("WORK_HOURS_ROUNDING", "0")
+	t.Setenv("WORK_HOURS_BREAK", "30m")
+	t.Setenv("WORK_HOURS_BREAK_AFTER", "-1h")
+	assert.Equal(t, WorkHours{RoundTo: 0, Break: 30 * time.Minute, BreakAfter: 6 * time.Hour}, WorkHoursFromEnv())
+}
+
+func TestWorkHours_Paid(t *testing.T) {
+	w := WorkHours{RoundTo: 15 * time.Minute, Break: time.Hour, BreakAfter: 6 * time.Hour}
+
+	tests := []struct {
+		worked   time.Duration
+		expected string
+	}{
+		{4*time.Hour + 7*time.Minute, "4"},
+		{4*time.Hour + 8*time.Minute, "4.25"},
+		{6 * time.Hour, "6"},
+		// past 6 hours the break takes the extra time until it is an hour, deducted before rounding
+		{6*time.Hour + 1*time.Minute, "6"},
+		{6*time.Hour + 5*time.Minute, "6"},
+		{6*time.Hour + 59*time.Minute, "6"},
+		{7 * time.Hour, "6"},
+		{7*time.Hour + 10*time.Minute, "6.25"},
+		{9*time.Hour + 40*time.Minute, "8.75"},
+		{0, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.worked.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, w.Paid(tt.worked).String())
+		})
+	}
+
+	// paid hours never drop when working longer
+	for worked, previous := 5*time.Hour, w.Paid(5*time.Hour); worked <= 10*time.Hour; worked += time.Minute {
+		paid := w.Paid(worked)
+		assert.False(t, paid.LessThan(previous), worked.String())
+		previous = paid
+	}
+
+	exact := WorkHours{}
+	assert.Equal(t, "1.3333", exact.Paid(80*time.Minute+30*time.Second).String())
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dealls-case-study/internal/handlers"
+
+	"gorm.io/gorm"
+)
+
+// RunAttendanceWorker closes or flags the attendances of previous days without a check-out
+// every interval until ctx is cancelled. A zero shiftEnd only flags them.
+func RunAttendanceWorker(ctx context.Context, d *gorm.DB, interval, shiftEnd time.Duration) {
+	log.Printf("Attendance worker started (interval %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		closed, flagged, err := handlers.CloseOpenAttendances(d, time.Now(), shiftEnd)
+		if err != nil {
+			log.Printf("attendance worker: %v", err)
+		} else if closed > 0 || flagged > 0 {
+			log.Printf("Attendance worker closed %d and flagged %d open attendances", closed, flagged)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Attendance worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"dealls-case-study/internal/handlers"
+	"dealls-case-study/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	payrollJobBaseBackoff = 30 * time.Second
+	payrollJobMaxBackoff  = 30 * time.Minute
+	// payrollJobLease is how long a claimed job is hidden from other workers while it runs.
+	payrollJobLease = 15 * time.Minute
+)
+
+var errPayrollJobAbandoned = errors.New("payroll job stopped before its last attempt finished")
+
+// RunPayrollWorker polls the payroll job queue until ctx is cancelled.
+// Every tick it drains all jobs that are due before going back to sleep.
+func RunPayrollWorker(ctx context.Context, d *gorm.DB, interval time.Duration) {
+	log.Printf("Payroll worker started (interval %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			processed, err := ProcessNextPayrollJob(d)
+			if err != nil {
+				log.Printf("payroll worker: %v", err)
+				break
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Payroll worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNextPayrollJob claims the next due job and processes its payroll.
+// The claim and its attempt are committed before the payroll runs, so a worker that dies
+// mid-run still uses up the attempt, and other workers skip the job until its lease runs out.
+// It reports whether a job was found.
+func ProcessNextPayrollJob(d *gorm.DB) (bool, error) {
+	job, err := claimPayrollJob(d)
+	if err != nil || job == nil {
+		return job != nil, err
+	}
+	if job.Status != models.PayrollJobStatusQueued {
+		// the job ran out of attempts while claiming it
+		return true, nil
+	}
+
+	processErr := handlers.ProcessPayroll(d, job.PayrollID, job.CreatedBy)
+
+	return true, d.Transaction(func(tx *gorm.DB) error {
+		return finishPayrollJob(tx, job, processErr)
+	})
+}
+
+// claimPayrollJob locks the next due job, counts the attempt on the job and its payroll and
+// pushes the job's run_at past the lease. A job whose last attempt never finished is failed
+// instead of being run again.
+func claimPayrollJob(d *gorm.DB) (*models.PayrollJob, error) {
+	var job *models.PayrollJob
+
+	err := d.Transaction(func(tx *gorm.DB) error {
+		var claimed models.PayrollJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", models.PayrollJobStatusQueued, time.Now()).
+			Order("run_at").
+			First(&claimed).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		job = &claimed
+
+		if job.Attempts >= job.MaxAttempts {
+			return finishPayrollJob(tx, job, errPayrollJobAbandoned)
+		}
+
+		job.Attempts++
+		job.RunAt = time.Now().Add(payrollJobLease)
+		if err := tx.Model(&models.Payroll{}).
+			Where("id = ?", job.PayrollID).
+			Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Save(job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// finishPayrollJob records the outcome of the job's current attempt. A failed attempt is
+// retried with backoff until the job runs out of attempts, then the payroll is marked failed.
+func finishPayrollJob(tx *gorm.DB, job *models.PayrollJob, processErr error) error {
+	now := time.Now()
+	if processErr == nil {
+		job.Status = models.PayrollJobStatusSucceeded
+		job.LastError = ""
+		job.CompletedAt = &now
+	} else {
+		job.LastError = processErr.Error()
+		if job.Attempts >= job.MaxAttempts {
+			job.Status = models.PayrollJobStatusFailed
+			job.CompletedAt = &now
+		} else {
+			job.RunAt = now.Add(payrollJobBackoff(job.Attempts))
+		}
+		log.Printf("Payroll %d failed (attempt %d/%d): %v", job.PayrollID, job.Attempts, job.MaxAttempts, processErr)
+	}
+
+	updates := map[string]interface{}{
+		"last_error": job.LastError,
+	}
+	if job.Status == models.PayrollJobStatusFailed {
+		// out of retries: surface the failure on the payroll so it can be retried manually
+		updates["status"] = models.PayrollStatusFailed
+		updates["failure_reason"] = job.LastError
+		updates["failed_at"] = now
+	}
+	if err := tx.Model(&models.Payroll{}).
+		Where("id = ?", job.PayrollID).
+		Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return tx.Save(job).Error
+}
+
+// payrollJobBackoff doubles the delay for every failed attempt, capped at payrollJobMaxBackoff.
+func payrollJobBackoff(attempts int) time.Duration {
+	backoff := payrollJobBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= payrollJobMaxBackoff {
+			return payrollJobMaxBackoff
+		}
+	}
+	return backoff
+}
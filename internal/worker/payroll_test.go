@@ -0,0 +1,163 @@
+package worker_test
+
+import (
+	"dealls-case-study/internal/db"
+	"dealls-case-study/internal/models"
+	"dealls-case-study/internal/worker"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestDBForWorker(status string) (*gorm.DB, models.Payroll, func()) {
+	d, cleanup, err := db.InitTestDB()
+	if err != nil {
+		log.Fatalf("failed to initialize test db: %v", err)
+	}
+
+	employee := models.User{
+		ID:       2,
+		Username: "employee",
+		Password: "password",
+		RoleID:   2,
+		Salary:   decimal.NewFromInt(2200000),
+	}
+	d.Create(&employee)
+
+	payroll := models.Payroll{
+		Month:       6,
+		Year:        2025,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:      status,
+	}
+	d.Create(&payroll)
+
+	return d, payroll, cleanup
+}
+
+func TestProcessNextPayrollJob_Success(t *testing.T) {
+	d, payroll, cleanup := setupTestDBForWorker(models.PayrollStatusPending)
+	defer cleanup()
+
+	job := models.PayrollJob{
+		PayrollID:   payroll.ID,
+		MaxAttempts: models.DefaultPayrollJobMaxAttempts,
+		RunAt:       time.Now().Add(-time.Minute),
+		CreatedBy:   1,
+	}
+	d.Create(&job)
+
+	processed, err := worker.ProcessNextPayrollJob(d)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	d.First(&job, job.ID)
+	assert.Equal(t, models.PayrollJobStatusSucceeded, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.NotNil(t, job.CompletedAt)
+
+	d.First(&payroll, payroll.ID)
+	assert.Equal(t, models.PayrollStatusProcessed, payroll.Status)
+	assert.Equal(t, 1, payroll.Attempts)
+
+	var count int64
+	d.Model(&models.Payslip{}).Where("payroll_id = ?", payroll.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestProcessNextPayrollJob_RetriesWithBackoff(t *testing.T) {
+	// a draft payroll cannot be processed, so the job fails
+	d, payroll, cleanup := setupTestDBForWorker(models.PayrollStatusDraft)
+	defer cleanup()
+
+	job := models.PayrollJob{
+		PayrollID:   payroll.ID,
+		MaxAttempts: models.DefaultPayrollJobMaxAttempts,
+		RunAt:       time.Now().Add(-time.Minute),
+		CreatedBy:   1,
+	}
+	d.Create(&job)
+
+	processed, err := worker.ProcessNextPayrollJob(d)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	d.First(&job, job.ID)
+	assert.Equal(t, models.PayrollJobStatusQueued, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.True(t, job.RunAt.After(time.Now()))
+	assert.Contains(t, job.LastError, "not in a pending state")
+
+	d.First(&payroll, payroll.ID)
+	assert.Equal(t, 1, payroll.Attempts)
+	assert.Contains(t, payroll.LastError, "not in a pending state")
+
+	// the job is not due yet, so nothing is picked up
+	processed, err = worker.ProcessNextPayrollJob(d)
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestProcessNextPayrollJob_GivesUpAfterMaxAttempts(t *testing.T) {
+	d, payroll, cleanup := setupTestDBForWorker(models.PayrollStatusDraft)
+	defer cleanup()
+
+	job := models.PayrollJob{
+		PayrollID:   payroll.ID,
+		Attempts:    models.DefaultPayrollJobMaxAttempts - 1,
+		MaxAttempts: models.DefaultPayrollJobMaxAttempts,
+		RunAt:       time.Now().Add(-time.Minute),
+		CreatedBy:   1,
+	}
+	d.Create(&job)
+
+	processed, err := worker.ProcessNextPayrollJob(d)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	d.First(&job, job.ID)
+	assert.Equal(t, models.PayrollJobStatusFailed, job.Status)
+	assert.Equal(t, models.DefaultPayrollJobMaxAttempts, job.Attempts)
+	assert.NotNil(t, job.CompletedAt)
+
+	d.First(&payroll, payroll.ID)
+	assert.Equal(t, models.PayrollStatusFailed, payroll.Status)
+	assert.Contains(t, payroll.FailureReason, "not in a pending state")
+	assert.NotNil(t, payroll.FailedAt)
+}
+
+func TestProcessNextPayrollJob_AbandonedLastAttempt(t *testing.T) {
+	d, payroll, cleanup := setupTestDBForWorker(models.PayrollStatusPending)
+	defer cleanup()
+
+	// the worker claimed the last attempt and died before recording its outcome
+	job := models.PayrollJob{
+		PayrollID:   payroll.ID,
+		Attempts:    models.DefaultPayrollJobMaxAttempts,
+		MaxAttempts: models.DefaultPayrollJobMaxAttempts,
+		RunAt:       time.Now().Add(-time.Minute),
+		CreatedBy:   1,
+	}
+	d.Create(&job)
+
+	processed, err := worker.ProcessNextPayrollJob(d)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	d.First(&job, job.ID)
+	assert.Equal(t, models.PayrollJobStatusFailed, job.Status)
+	assert.Equal(t, models.DefaultPayrollJobMaxAttempts, job.Attempts)
+	assert.Contains(t, job.LastError, "stopped before its last attempt finished")
+
+	d.First(&payroll, payroll.ID)
+	assert.Equal(t, models.PayrollStatusFailed, payroll.Status)
+
+	var count int64
+	d.Model(&models.Payslip{}).Where("payroll_id = ?", payroll.ID).Count(&count)
+	assert.Zero(t, count)
+}